@@ -4,10 +4,13 @@ import (
 	"flag"
 	"fmt"
 	"github.com/xianyudd/hanzi-data-kit/generator"
-	"github.com/xianyudd/hanzi-data-kit/model"
+	"github.com/xianyudd/hanzi-data-kit/internal/cliutil"
+	"github.com/xianyudd/hanzi-data-kit/internal/studentgen"
 	"github.com/xianyudd/hanzi-data-kit/parser"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 func main() {
@@ -19,13 +22,98 @@ func main() {
 		ageMax   = flag.Int("age-max", 30, "年龄上限(闭区间)")
 		scoreMin = flag.Float64("score-min", 60, "得分下限(闭区间)")
 		scoreMax = flag.Float64("score-max", 100, "得分上限(闭区间)")
+
+		delimiter   = flag.String("delimiter", ",", "字段分隔符(如 , ; | tab)")
+		crlf        = flag.Bool("crlf", false, "是否使用 CRLF(\\r\\n) 换行")
+		alwaysQuote = flag.Bool("always-quote", false, "是否为每个字段加双引号")
+		headerLang  = flag.String("headers", "cn", "表头语言: cn|en")
+		address     = flag.Bool("address", false, "按人口加权从行政区划表生成城市，并追加 省份/区县/区划代码/街道/门牌号 列")
+		withContact = flag.Bool("contact", false, "由姓名拼音生成联系方式，并追加 手机号/邮箱/用户名 列")
+		maskPhone   = flag.Bool("mask-phone", false, "手机号脱敏输出(如 138****1234)，需配合 -contact")
+		withDates   = flag.Bool("dates", false, "追加 出生日期/入学日期 列：出生日期与年龄一致，入学日期符合学年规则")
+		refDate     = flag.String("ref-date", "2025-09-01", "计算年龄与学年的参考日期(即“今天”)，需配合 -dates")
+		tz          = flag.String("tz", "+08:00", "生成日期所在的时区(如 Asia/Shanghai、+08:00)")
+		enrollAge   = flag.Int("enroll-age", 18, "入学年龄(学年开始前满该周岁)，需配合 -dates")
+		perRow      = flag.Bool("per-row", false, "按行确定：第 i 条记录只由 hash(seed, i) 决定，可分段/并行生成(与默认模式的数据不同)")
+		offset      = flag.Int("offset", 0, "从第 offset 条(从 0 开始)开始生成，用于断点续写或分段生成，需配合 -per-row")
+		workers     = flag.Int("workers", 1, "并行生成的 goroutine 数，需配合 -per-row")
+		carriers    = flag.String("carriers", "", "限定手机号运营商，逗号分隔: mobile|unicom|telecom|broadnet(为空表示全部)")
+		appendRows  = flag.Bool("append", false, "在已有的 -out 文件末尾接续生成，使其共有 -n 行(结果与一次生成 -n 行相同)；生成参数取自其 .meta.json 伴随文件")
+		rngName     = flag.String("rng", "mathrand", "随机数算法: "+rngNames()+"(除 mathrand 外均保证跨版本输出稳定)")
 	)
+	logFlags := cliutil.RegisterLogFlags(flag.CommandLine)
 	flag.Parse()
 
+	logger, err := logFlags.Logger(os.Stderr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "参数错误: %v\n", err)
+		os.Exit(2)
+	}
+
+	if *appendRows {
+		runAppend(*out, *n, *workers, logger)
+		return
+	}
+
+	delim, err := parser.ParseDelimiter(*delimiter)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "参数错误: -delimiter: %v\n", err)
+		os.Exit(2)
+	}
 	if *n <= 0 {
 		fmt.Fprintf(os.Stderr, "参数错误: -n 必须 > 0")
 		os.Exit(2)
 	}
+	if (*offset != 0 || *workers > 1) && !*perRow {
+		fmt.Fprintln(os.Stderr, "参数错误: -offset/-workers 需配合 -per-row")
+		os.Exit(2)
+	}
+	if *offset < 0 {
+		fmt.Fprintln(os.Stderr, "参数错误: -offset 不能为负数")
+		os.Exit(2)
+	}
+	if (*offset != 0 || *workers > 1) && (*withContact || *withDates) {
+		// 联系方式要跨行去重、日期生成器按顺序抽取，二者都依赖此前各行。
+		fmt.Fprintln(os.Stderr, "参数错误: -offset/-workers 不能与 -contact/-dates 同时使用")
+		os.Exit(2)
+	}
+	alg, err := generator.ParseRNGAlgorithm(*rngName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "参数错误: -rng: %v\n", err)
+		os.Exit(2)
+	}
+
+	job := studentgen.Job{
+		Student: generator.StudentGenConfig{
+			Seed:     *seed,
+			AgeMin:   *ageMin,
+			AgeMax:   *ageMax,
+			ScoreMin: *scoreMin,
+			ScoreMax: *scoreMax,
+			PerRow:   *perRow,
+			RNG:      alg,
+		},
+		Rows:    *n,
+		Offset:  *offset,
+		Address: *address,
+		Headers: *headerLang,
+		Dialect: studentgen.DialectSpec{Delimiter: string(delim), CRLF: *crlf, AlwaysQuote: *alwaysQuote},
+	}
+	if *withContact {
+		cs, err := studentgen.ParseCarriers(*carriers)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "参数错误: -carriers: %v\n", err)
+			os.Exit(2)
+		}
+		job.Contact = &studentgen.ContactSpec{Carriers: cs, MaskPhone: *maskPhone}
+	}
+	if *withDates {
+		job.Dates = &studentgen.DateSpec{Reference: *refDate, Location: *tz, EnrollmentAge: *enrollAge}
+	}
+	if err := job.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "参数错误: %v\n", err)
+		os.Exit(2)
+	}
 
 	// 确保输出目录存在
 	if err := os.MkdirAll(filepath.Dir(*out), 0o755); err != nil {
@@ -33,29 +121,60 @@ func main() {
 		os.Exit(1)
 	}
 
-	gen := generator.NewStudentGenerator(generator.StudentGenConfig{
-		Seed:     *seed,
-		AgeMin:   *ageMin,
-		AgeMax:   *ageMax,
-		ScoreMin: *scoreMin,
-		ScoreMax: *scoreMax,
-	})
-
-	students := make([]model.Student, 0, *n)
-	for i := 0; i < *n; i++ {
-		students = append(students, gen.Next())
+	// 行在写出时按需生成，不在内存中保留整张表。
+	meta, err := studentgen.Generate(*out, job, *workers, logger)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "写入CSV失败: %v\n", err)
+		os.Exit(1)
 	}
-
-	headers := model.StudentHeadersCN()
-	totalRows := len(students)
-	rowGenerator := func(i int) []string {
-		return model.StudentToRowCN(students[i-1])
+	if err := studentgen.WriteMeta(*out+studentgen.MetaSuffix, meta); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
 	}
 
-	if err := parser.WriteLargeCSV(*out, headers, totalRows, rowGenerator); err != nil {
-		fmt.Fprintf(os.Stderr, "写入CSV失败: %v\n", err)
+	fmt.Printf("成功生成并写入 %d 条学生数据到 >>> %s\n", *n, *out)
+}
+
+// runAppend 按伴随文件中的参数与生成器状态，在 out 末尾接续生成到共 n 行。
+func runAppend(out string, n, workers int, logger *slog.Logger) {
+	var conflicts []string
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "append", "n", "out", "workers", "log-level", "log-format":
+		default:
+			conflicts = append(conflicts, "-"+f.Name)
+		}
+	})
+	if len(conflicts) > 0 {
+		fmt.Fprintf(os.Stderr, "参数错误: -append 时生成参数取自伴随文件, 不能再指定 %s\n", strings.Join(conflicts, " "))
+		os.Exit(2)
+	}
+	meta, err := studentgen.ReadMeta(out + studentgen.MetaSuffix)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	if n <= meta.Job.Rows {
+		fmt.Fprintf(os.Stderr, "参数错误: %s 已有 %d 行, -n 须大于它(-n 为追加后的总行数)\n", out, meta.Job.Rows)
+		os.Exit(2)
+	}
+	before := meta.Job.Rows
+	meta, err = studentgen.Append(out, meta, n, workers, logger)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "追加失败: %v\n", err)
+		os.Exit(1)
+	}
+	if err := studentgen.WriteMeta(out+studentgen.MetaSuffix, meta); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
 		os.Exit(1)
 	}
+	fmt.Printf("成功追加 %d 条学生数据到 >>> %s(共 %d 条)\n", n-before, out, n)
+}
 
-	fmt.Printf("成功生成并写入 %d 条学生数据到 >>> %s\n", totalRows, *out)
+func rngNames() string {
+	var names []string
+	for _, a := range generator.RNGAlgorithms() {
+		names = append(names, string(a))
+	}
+	return strings.Join(names, "|")
 }
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/xianyudd/hanzi-data-kit/dedupe"
+	"github.com/xianyudd/hanzi-data-kit/internal/cliutil"
+	"github.com/xianyudd/hanzi-data-kit/model"
+	"github.com/xianyudd/hanzi-data-kit/parser"
+)
+
+func runDedupe(args []string) error {
+	fs := flag.NewFlagSet("dedupe", flag.ContinueOnError)
+	defaults := dedupe.DefaultFuzzyOptions()
+	var (
+		in         = fs.String("in", "data/students.csv", "输入CSV路径")
+		out        = fs.String("out", "data/students_dedup.csv", "去重后输出的CSV路径")
+		report     = fs.String("report", "", "簇报告CSV路径(为空则不输出)")
+		key        = fs.String("key", "姓名,年龄,城市,得分", "精确去重使用的列(-fuzzy 时忽略)")
+		fuzzy      = fs.Bool("fuzzy", false, "启用模糊匹配(繁简/空白/同音/编辑距离)")
+		threshold  = fs.Float64("threshold", defaults.NameThreshold, "模糊匹配的姓名相似度阈值(0~1)")
+		anyCity    = fs.Bool("any-city", false, "模糊匹配时不要求城市相同")
+		ageTol     = fs.Int("age-tolerance", defaults.AgeTolerance, "模糊匹配的年龄容差(岁)")
+		inputFlags = cliutil.RegisterCSVInputFlags(fs)
+		logFlags   = cliutil.RegisterLogFlags(fs)
+	)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *threshold <= 0 || *threshold > 1 {
+		return usageError{fmt.Errorf("-threshold 必须在 (0,1] 区间内, 实际为 %v", *threshold)}
+	}
+	if *ageTol < 0 {
+		return usageError{fmt.Errorf("-age-tolerance 不能为负数")}
+	}
+
+	logger, err := logFlags.Logger(os.Stderr)
+	if err != nil {
+		return usageError{err}
+	}
+	opts, err := inputFlags.Options(logger)
+	if err != nil {
+		return usageError{err}
+	}
+
+	table, err := parser.ParseCSVToStudentTable(*in, opts)
+	if err != nil {
+		return fmt.Errorf("解析CSV失败: %w", err)
+	}
+
+	res, err := dedupe.Dedupe(table.Students(), dedupe.Options{
+		Key:           cliutil.SplitList(*key),
+		Fuzzy:         *fuzzy,
+		NameThreshold: *threshold,
+		AnyCity:       *anyCity,
+		AgeTolerance:  *ageTol,
+	})
+	if err != nil {
+		return usageError{err}
+	}
+
+	kept := &parser.StudentTable{ExtraHeaders: table.ExtraHeaders}
+	for _, i := range res.Keep {
+		kept.Records = append(kept.Records, table.Records[i])
+	}
+	wopts := parser.CSVWriteOptions{Dialect: opts.Dialect, Logger: logger}
+	if err := parser.WriteStudentTableCSV(*out, kept, wopts); err != nil {
+		return err
+	}
+	if *report != "" {
+		if err := writeClusterReport(*report, table, res, wopts); err != nil {
+			return err
+		}
+	}
+
+	logger.Info("去重完成", "in", len(table.Records), "out", len(res.Keep), "clusters", len(res.Clusters))
+	return nil
+}
+
+// writeClusterReport 每个簇成员输出一行：簇编号、是否为代表记录、源文件行号与标准列。
+func writeClusterReport(path string, table *parser.StudentTable, res *dedupe.Result, opts parser.CSVWriteOptions) error {
+	type row struct{ cluster, member int }
+	var rows []row
+	for c, cl := range res.Clusters {
+		for _, m := range cl.Members {
+			rows = append(rows, row{c, m})
+		}
+	}
+
+	headers := append([]string{"簇编号", "代表", "行号"}, model.StudentHeadersCN()...)
+	return parser.WriteLargeCSVWithOptions(path, headers, len(rows), func(i int) []string {
+		r := rows[i-1]
+		rec := table.Records[r.member]
+		rep := "否"
+		if res.Clusters[r.cluster].Representative == r.member {
+			rep = "是"
+		}
+		return append([]string{strconv.Itoa(r.cluster + 1), rep, strconv.Itoa(rec.Line)}, model.StudentToRowCN(rec.Student)...)
+	}, opts)
+}
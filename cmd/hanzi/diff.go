@@ -0,0 +1,73 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/xianyudd/hanzi-data-kit/diff"
+	"github.com/xianyudd/hanzi-data-kit/internal/cliutil"
+)
+
+func runDiff(args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	var (
+		oldPath    = fs.String("old", "", "旧CSV路径(必填)")
+		newPath    = fs.String("new", "", "新CSV路径(必填)")
+		key        = fs.String("key", "姓名,城市", "匹配键(逗号分隔的列名，可用附加列如 学号)")
+		format     = fs.String("format", "text", "输出格式: text|json|csv")
+		out        = fs.String("out", "-", "输出路径(- 表示标准输出)")
+		chunkRows  = fs.Int("chunk-rows", diff.DefaultChunkRows, "外部排序每段的行数")
+		scoreTol   = fs.Float64("score-tolerance", 0, "得分之差不超过该值时视为未修改(默认 0 即按数值精确比较)")
+		oldEnc     = fs.String("old-encoding", "", "旧文件编码(为空时使用 -encoding)")
+		newEnc     = fs.String("new-encoding", "", "新文件编码(为空时使用 -encoding)")
+		inputFlags = cliutil.RegisterCSVInputFlags(fs)
+		logFlags   = cliutil.RegisterLogFlags(fs)
+	)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *oldPath == "" || *newPath == "" {
+		return usageError{fmt.Errorf("-old 与 -new 均为必填")}
+	}
+
+	logger, err := logFlags.Logger(os.Stderr)
+	if err != nil {
+		return usageError{err}
+	}
+	opts, err := inputFlags.Options(logger)
+	if err != nil {
+		return usageError{err}
+	}
+	oldIn := diff.Input{Path: *oldPath, Options: opts}
+	newIn := diff.Input{Path: *newPath, Options: opts}
+	if *oldEnc != "" {
+		oldIn.Options.Encoding = *oldEnc
+	}
+	if *newEnc != "" {
+		newIn.Options.Encoding = *newEnc
+	}
+
+	w, closeOut, err := cliutil.CreateOutput(*out)
+	if err != nil {
+		return err
+	}
+	report, err := diff.NewReportWriter(w, *format)
+	if err != nil {
+		closeOut()
+		return usageError{err}
+	}
+
+	summary, err := diff.Files(oldIn, newIn, diff.Options{
+		Key:            cliutil.SplitList(*key),
+		ChunkRows:      *chunkRows,
+		ScoreTolerance: *scoreTol,
+	}, report.Write)
+	if err == nil {
+		err = report.Close(summary)
+	}
+	if cerr := closeOut(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xianyudd/hanzi-data-kit/generator"
+	"github.com/xianyudd/hanzi-data-kit/internal/cliutil"
+	"github.com/xianyudd/hanzi-data-kit/model"
+	"github.com/xianyudd/hanzi-data-kit/parser"
+	"github.com/xianyudd/hanzi-data-kit/rank"
+)
+
+func runExam(args []string) error {
+	fs := flag.NewFlagSet("exam", flag.ContinueOnError)
+	var (
+		out         = fs.String("out", "data/exam.csv", "输出CSV路径")
+		seed        = fs.Int64("seed", 42, "随机种子(用于复现)")
+		schools     = fs.Int("schools", 2, "学校数")
+		classes     = fs.Int("classes", 4, "每所学校的班级数")
+		students    = fs.Int("students", 30, "每个班级的学生数")
+		subjects    = fs.String("subjects", "语文:150,数学:150,英语:150,物理:100,化学:100", "科目及满分(科目:满分，逗号分隔；省略满分时为 100)")
+		correlation = fs.Float64("correlation", 0.5, "任意两科成绩之间的相关系数(-1~1)")
+		method      = fs.String("rank", "competition", "并列总分的排名方式: competition|dense|ordinal")
+		start       = fs.String("start", "", "考试开始时间(如 \"2025-06-07 09:00\")；设置后生成 交卷时间 列，落在考试后半程内")
+		duration    = fs.Duration("duration", 2*time.Hour, "考试时长(如 150m)")
+		tz          = fs.String("tz", "+08:00", "考试时间所在的时区(如 Asia/Shanghai、+08:00)；交卷时间按该时区输出")
+		delimiter   = fs.String("delimiter", ",", "字段分隔符(如 , ; | tab)")
+		rngName     = fs.String("rng", "mathrand", "随机数算法: mathrand|pcg|chacha8|splitmix64(除 mathrand 外均保证跨版本输出稳定)")
+		logFlags    = cliutil.RegisterLogFlags(fs)
+	)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	specs, err := parseSubjectSpecs(*subjects)
+	if err != nil {
+		return usageError{fmt.Errorf("-subjects: %w", err)}
+	}
+	m, err := rank.ParseMethod(*method)
+	if err != nil {
+		return usageError{fmt.Errorf("-rank: %w", err)}
+	}
+	delim, err := parser.ParseDelimiter(*delimiter)
+	if err != nil {
+		return usageError{fmt.Errorf("-delimiter: %w", err)}
+	}
+	loc, err := cliutil.ParseLocation(*tz)
+	if err != nil {
+		return usageError{fmt.Errorf("-tz: %w", err)}
+	}
+	startAt, err := cliutil.ParseTimeFlag(*start, loc)
+	if err != nil {
+		return usageError{fmt.Errorf("-start: %w", err)}
+	}
+	alg, err := generator.ParseRNGAlgorithm(*rngName)
+	if err != nil {
+		return usageError{fmt.Errorf("-rng: %w", err)}
+	}
+	logger, err := logFlags.Logger(os.Stderr)
+	if err != nil {
+		return usageError{err}
+	}
+
+	ds := generator.GenerateSchoolDataset(generator.SchoolGenConfig{
+		Seed:             *seed,
+		Schools:          *schools,
+		ClassesPerSchool: *classes,
+		StudentsPerClass: *students,
+		Student:          generator.StudentGenConfig{RNG: alg},
+	})
+	exam, err := generator.GenerateExam(ds, generator.ExamGenConfig{
+		Seed:        *seed,
+		Subjects:    specs,
+		Correlation: *correlation,
+		StartAt:     startAt,
+		Duration:    *duration,
+		Location:    loc,
+		RNG:         alg,
+	})
+	if err != nil {
+		return usageError{err}
+	}
+	if err := rank.ScoreExam(exam, rank.ExamOptions{Method: m}); err != nil {
+		return err
+	}
+	return parser.WriteLargeCSVWithOptions(*out, model.ExamHeadersCN(exam.Subjects), len(exam.Results), func(i int) []string {
+		return model.ExamResultToRowCN(exam.Results[i-1])
+	}, parser.CSVWriteOptions{Dialect: parser.Dialect{Delimiter: delim}, Logger: logger})
+}
+
+// parseSubjectSpecs 解析形如 "语文:150,数学:150,物理" 的科目列表。
+func parseSubjectSpecs(s string) ([]generator.ExamSubjectSpec, error) {
+	var specs []generator.ExamSubjectSpec
+	for _, item := range cliutil.SplitList(s) {
+		name, full, ok := strings.Cut(item, ":")
+		spec := generator.ExamSubjectSpec{Name: strings.TrimSpace(name)}
+		if ok {
+			v, err := strconv.ParseFloat(strings.TrimSpace(full), 64)
+			if err != nil || v <= 0 {
+				return nil, fmt.Errorf("科目 %s 的满分不是正数: %q", spec.Name, full)
+			}
+			spec.FullMarks = v
+		}
+		specs = append(specs, spec)
+	}
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("至少需要一个科目")
+	}
+	return specs, nil
+}
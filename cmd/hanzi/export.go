@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/xianyudd/hanzi-data-kit/db"
+	"github.com/xianyudd/hanzi-data-kit/internal/cliutil"
+	"github.com/xianyudd/hanzi-data-kit/parser"
+
+	_ "modernc.org/sqlite" // 注册 "sqlite" 驱动
+)
+
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	var (
+		driver      = fs.String("driver", "sqlite", "database/sql 驱动名")
+		dsn         = fs.String("dsn", "", "数据源(必填)，如 SQLite 文件路径 data/students.db")
+		query       = fs.String("query", "SELECT * FROM students", "要导出的查询")
+		out         = fs.String("out", "data/export.csv", "输出CSV路径")
+		columns     = fs.String("columns", "name,age,city,score", "数据库中 姓名,年龄,城市,得分 对应的列名")
+		delimiter   = fs.String("delimiter", ",", "字段分隔符(如 , ; | tab)")
+		crlf        = fs.Bool("crlf", false, "是否使用 CRLF(\\r\\n) 换行")
+		alwaysQuote = fs.Bool("always-quote", false, "是否为每个字段加双引号")
+		logFlags    = cliutil.RegisterLogFlags(fs)
+	)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *dsn == "" {
+		return usageError{fmt.Errorf("-dsn 为必填")}
+	}
+	if !slices.Contains(sql.Drivers(), *driver) {
+		return usageError{fmt.Errorf("未注册的驱动 %q（当前可用: %s）", *driver, strings.Join(sql.Drivers(), ","))}
+	}
+	cols := cliutil.SplitList(*columns)
+	if len(cols) != 4 {
+		return usageError{fmt.Errorf("-columns 需要 4 个列名，实际为 %d 个", len(cols))}
+	}
+	delim, err := parser.ParseDelimiter(*delimiter)
+	if err != nil {
+		return usageError{fmt.Errorf("-delimiter: %w", err)}
+	}
+
+	logger, err := logFlags.Logger(os.Stderr)
+	if err != nil {
+		return usageError{err}
+	}
+
+	conn, err := sql.Open(*driver, *dsn)
+	if err != nil {
+		return fmt.Errorf("连接数据库失败: %w", err)
+	}
+	defer conn.Close()
+
+	if err := os.MkdirAll(filepath.Dir(*out), 0o755); err != nil {
+		return fmt.Errorf("创建输出目录失败: %w", err)
+	}
+	n, err := db.ExportCSV(context.Background(), conn, *out, *query, db.ExportOptions{
+		Columns: db.Columns{Name: cols[0], Age: cols[1], City: cols[2], Score: cols[3]},
+		CSV: parser.CSVWriteOptions{
+			Dialect: parser.Dialect{Delimiter: delim, UseCRLF: *crlf, AlwaysQuote: *alwaysQuote},
+			Logger:  logger,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	logger.Debug("导出完成", "rows", n)
+	return nil
+}
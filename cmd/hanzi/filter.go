@@ -0,0 +1,178 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/xianyudd/hanzi-data-kit/expr"
+	"github.com/xianyudd/hanzi-data-kit/internal/cliutil"
+	"github.com/xianyudd/hanzi-data-kit/parser"
+)
+
+func runFilter(args []string) error {
+	fs := flag.NewFlagSet("filter", flag.ContinueOnError)
+	var (
+		in         = fs.String("in", "data/students.csv", "输入CSV路径")
+		out        = fs.String("out", "-", "输出路径(- 表示标准输出)")
+		format     = fs.String("format", "csv", "输出格式: "+cliutil.RowFormats)
+		where      = fs.String("where", "", `筛选条件，如 '得分 >= 90 && 城市 in ["北京", "上海"]'`)
+		selectList = fs.String("select", "", "输出列，逗号分隔，每项为 表达式 [as 名称]；为空时输出全部列")
+		limit      = fs.Int("limit", 0, "最多输出的行数(0表示不限)")
+		sets       []expr.Assignment
+		inputFlags = cliutil.RegisterCSVInputFlags(fs)
+		logFlags   = cliutil.RegisterLogFlags(fs)
+	)
+	fs.Func("set", "赋值 列名 = 表达式(可重复，按顺序执行；列不存在时新增)，如 '得分 = 得分 + 5'", func(s string) error {
+		a, err := expr.ParseAssignment(s)
+		if err != nil {
+			return err
+		}
+		sets = append(sets, a)
+		return nil
+	})
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	var (
+		cond        *expr.Expr
+		projections []expr.Projection
+		err         error
+	)
+	if *where != "" {
+		if cond, err = expr.Compile(*where); err != nil {
+			return usageError{fmt.Errorf("-where: %w", err)}
+		}
+	}
+	if *selectList != "" {
+		if projections, err = expr.ParseSelect(*selectList); err != nil {
+			return usageError{fmt.Errorf("-select: %w", err)}
+		}
+	}
+
+	logger, err := logFlags.Logger(os.Stderr)
+	if err != nil {
+		return usageError{err}
+	}
+	opts, err := inputFlags.Options(logger)
+	if err != nil {
+		return usageError{err}
+	}
+
+	sc, err := parser.NewStudentScanner(*in, opts)
+	if err != nil {
+		return fmt.Errorf("解析CSV失败: %w", err)
+	}
+	defer sc.Close()
+
+	// 可用的列：标准列 + 附加列 + -set 新增的列；在读取数据前检查表达式引用的列是否都存在。
+	headers := (&parser.StudentTable{ExtraHeaders: sc.ExtraHeaders()}).Headers()
+	known := func(col string) bool {
+		_, std := expr.StudentColumn(col)
+		return std || slices.Contains(headers, col)
+	}
+	check := func(flagName string, e *expr.Expr) error {
+		for _, col := range e.Columns() {
+			if !known(col) {
+				return usageError{fmt.Errorf("%s: 未知的列 %q（可用: %v）", flagName, col, headers)}
+			}
+		}
+		return nil
+	}
+	if cond != nil {
+		if err := check("-where", cond); err != nil {
+			return err
+		}
+	}
+	for _, a := range sets {
+		if err := check("-set", a.Expr); err != nil {
+			return err
+		}
+		if !known(a.Column) {
+			headers = append(headers, a.Column)
+		}
+	}
+	if projections == nil {
+		for _, h := range headers {
+			projections = append(projections, expr.Projection{Name: h, Expr: expr.Column(h)})
+		}
+	}
+	for _, p := range projections {
+		if err := check("-select", p.Expr); err != nil {
+			return err
+		}
+	}
+
+	w, closeOut, err := cliutil.CreateOutput(*out)
+	if err != nil {
+		return err
+	}
+	rw, err := cliutil.NewRowWriter(w, *format, opts.Dialect.Delimiter)
+	if err != nil {
+		closeOut()
+		return usageError{err}
+	}
+	err = filterRecords(sc, cond, sets, projections, *limit, rw)
+	if cerr := rw.Close(); err == nil {
+		err = cerr
+	}
+	if cerr := closeOut(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// filterRecords 逐条读取记录：先按 cond 筛选，再执行 sets，最后按 projections 输出。
+func filterRecords(sc *parser.StudentScanner, cond *expr.Expr, sets []expr.Assignment, projections []expr.Projection, limit int, rw cliutil.RowWriter) error {
+	names := make([]string, len(projections))
+	for i, p := range projections {
+		names[i] = p.Name
+	}
+	if err := rw.WriteHeader(names); err != nil {
+		return err
+	}
+
+	written := 0
+	for limit <= 0 || written < limit {
+		rec, err := sc.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if cond != nil {
+			ok, err := cond.Match(&rec)
+			if err != nil {
+				return fmt.Errorf("第 %d 行: %w", rec.Line, err)
+			}
+			if !ok {
+				continue
+			}
+		}
+		for _, a := range sets {
+			if err := a.Apply(&rec); err != nil {
+				return fmt.Errorf("第 %d 行: %w", rec.Line, err)
+			}
+		}
+
+		env := expr.RecordEnv{Record: &rec}
+		vals := make([]any, len(projections))
+		for i, p := range projections {
+			v, err := p.Expr.Eval(env)
+			if err != nil {
+				return fmt.Errorf("第 %d 行: %w", rec.Line, err)
+			}
+			vals[i] = v
+		}
+		if err := rw.WriteRow(vals); err != nil {
+			return err
+		}
+		written++
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+// hanzi 是 hanzi-data-kit 的多子命令命令行工具。
+//
+// 用法:
+//
+//	hanzi <command> [flags]
+//
+// 运行 `hanzi help` 查看全部子命令，`hanzi <command> -h` 查看子命令参数。
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// command 描述一个子命令；run 接收子命令名之后的全部参数。
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
+
+// commands 按帮助信息中的展示顺序排列。
+var commands = []command{
+	{name: "stats", summary: "统计学生CSV: 计数/均值/分位数/城市分组/直方图", run: runStats},
+	{name: "diff", summary: "比较两份学生CSV: 新增/删除/字段级修改", run: runDiff},
+	{name: "dedupe", summary: "学生CSV去重: 精确键或模糊姓名匹配，输出簇报告", run: runDedupe},
+	{name: "filter", summary: "按表达式筛选/变换学生CSV(-where/-select/-set)，输出 csv/jsonl/json/table", run: runFilter},
+	{name: "query", summary: "将学生CSV载入内嵌SQLite并执行SQL，输出 table/csv/json/jsonl", run: runQuery},
+	{name: "export", summary: "执行SQL查询并将结果按学生CSV格式导出", run: runExport},
+	{name: "sample", summary: "可复现抽样: 蓄水池/伯努利/按城市或分数段分层", run: runSample},
+	{name: "split", summary: "按键哈希将学生CSV确定性地划分为多份(如 train/test)", run: runSplit},
+	{name: "gen", summary: "按JSON模板生成任意结构的表(列类型如 name:cn/int:18..30/enum/ref 外键)", run: runGen},
+	{name: "fixtures", summary: "生成外键一致的学校关系数据(学校/班级/教师/课程/学生/成绩)", run: runFixtures},
+	{name: "exam", summary: "生成多科考试成绩(可设相关性)并计算总分/班级与学校排名/百分位/等级", run: runExam},
+	{name: "reproduce", summary: "按 gen_students 写出的 .meta.json 重新生成学生CSV并校验 sha256 是否一致", run: runReproduce},
+	{name: "serve", summary: "启动HTTP(及可选gRPC)服务: 流式生成、上传解析与校验", run: runServe},
+}
+
+func main() {
+	if len(os.Args) < 2 || os.Args[1] == "help" || os.Args[1] == "-h" || os.Args[1] == "--help" {
+		usage()
+		return
+	}
+
+	name := os.Args[1]
+	for _, c := range commands {
+		if c.name != name {
+			continue
+		}
+		if err := c.run(os.Args[2:]); err != nil {
+			var ue usageError
+			switch {
+			case errors.Is(err, flag.ErrHelp):
+				return
+			case errors.As(err, &ue):
+				fmt.Fprintf(os.Stderr, "参数错误: %v\n", ue.err)
+				os.Exit(2)
+			default:
+				fmt.Fprintf(os.Stderr, "hanzi %s: %v\n", name, err)
+				os.Exit(1)
+			}
+		}
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "未知子命令: %s\n\n", name)
+	usage()
+	os.Exit(2)
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "用法: hanzi <command> [flags]")
+	fmt.Fprintln(os.Stderr, "\n子命令:")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", c.name, c.summary)
+	}
+}
+
+// usageError 表示命令行参数错误（退出码 2），区别于运行期错误（退出码 1）。
+type usageError struct{ err error }
+
+func (e usageError) Error() string { return e.err.Error() }
+
+// parseFlags 解析子命令参数；参数错误统一包装为 usageError。
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return usageError{err}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/xianyudd/hanzi-data-kit/internal/cliutil"
+	"github.com/xianyudd/hanzi-data-kit/sqlquery"
+)
+
+func runQuery(args []string) error {
+	fs := flag.NewFlagSet("query", flag.ContinueOnError)
+	var (
+		tables     []string
+		query      = fs.String("sql", "", "要执行的SQL(也可作为最后一个位置参数给出)")
+		format     = fs.String("format", "table", "输出格式: "+cliutil.RowFormats)
+		out        = fs.String("out", "-", "输出路径(- 表示标准输出)")
+		inputFlags = cliutil.RegisterCSVInputFlags(fs)
+		logFlags   = cliutil.RegisterLogFlags(fs)
+	)
+	fs.Func("t", "载入的文件: 路径 或 表名=路径(可重复；省略表名时取文件名)", func(s string) error {
+		tables = append(tables, s)
+		return nil
+	})
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *query == "" {
+		*query = strings.Join(fs.Args(), " ")
+	} else if fs.NArg() > 0 {
+		return usageError{fmt.Errorf("-sql 与位置参数不能同时给出")}
+	}
+	if strings.TrimSpace(*query) == "" {
+		return usageError{fmt.Errorf("缺少SQL语句")}
+	}
+	if len(tables) == 0 {
+		return usageError{fmt.Errorf("至少需要一个 -t 载入文件")}
+	}
+
+	logger, err := logFlags.Logger(os.Stderr)
+	if err != nil {
+		return usageError{err}
+	}
+	opts, err := inputFlags.Options(logger)
+	if err != nil {
+		return usageError{err}
+	}
+
+	engine, err := sqlquery.NewEngine()
+	if err != nil {
+		return err
+	}
+	defer engine.Close()
+
+	ctx := context.Background()
+	for _, spec := range tables {
+		name, path := sqlquery.ParseTableSpec(spec)
+		n, err := engine.LoadCSV(ctx, name, path, opts)
+		if err != nil {
+			return fmt.Errorf("载入 %s 失败: %w", path, err)
+		}
+		logger.Debug("载入表", "table", name, "file", path, "rows", n)
+	}
+
+	w, closeOut, err := cliutil.CreateOutput(*out)
+	if err != nil {
+		return err
+	}
+	rw, err := cliutil.NewRowWriter(w, *format, 0)
+	if err != nil {
+		closeOut()
+		return usageError{err}
+	}
+	err = engine.Query(ctx, *query, rw.WriteHeader, rw.WriteRow)
+	if cerr := rw.Close(); err == nil {
+		err = cerr
+	}
+	if cerr := closeOut(); err == nil {
+		err = cerr
+	}
+	return err
+}
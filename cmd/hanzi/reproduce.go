@@ -0,0 +1,90 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/xianyudd/hanzi-data-kit/internal/cliutil"
+	"github.com/xianyudd/hanzi-data-kit/internal/studentgen"
+)
+
+func runReproduce(args []string) error {
+	fs := flag.NewFlagSet("reproduce", flag.ContinueOnError)
+	var (
+		metaPath = fs.String("meta", "", "gen_students 写出的伴随文件(如 data/students.csv.meta.json)")
+		out      = fs.String("out", "", "重新生成的文件路径(同时写出其伴随文件)；为空时只校验，不保留文件")
+		workers  = fs.Int("workers", 1, "并行生成的 goroutine 数(仅对 per-row 生成的文件有效，不影响结果)")
+		logFlags = cliutil.RegisterLogFlags(fs)
+	)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *metaPath == "" {
+		return usageError{fmt.Errorf("缺少 -meta")}
+	}
+	logger, err := logFlags.Logger(os.Stderr)
+	if err != nil {
+		return usageError{err}
+	}
+	meta, err := studentgen.ReadMeta(*metaPath)
+	if err != nil {
+		return err
+	}
+	if err := meta.Job.Validate(); err != nil {
+		return fmt.Errorf("伴随文件中的参数无效: %w", err)
+	}
+	if *workers > 1 && (!meta.Job.Student.PerRow || meta.Job.Contact != nil || meta.Job.Dates != nil) {
+		logger.Info("该文件不是按行确定地生成的，忽略 -workers")
+		*workers = 1
+	}
+
+	path := *out
+	if path == "" {
+		f, err := os.CreateTemp("", "hanzi-reproduce-*.csv")
+		if err != nil {
+			return err
+		}
+		f.Close()
+		path = f.Name()
+		defer os.Remove(path)
+	} else if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("创建输出目录失败: %w", err)
+	}
+
+	fmt.Printf("指纹     %s\n", meta.Fingerprint)
+	fmt.Printf("生成工具 %s %s (%s)\n", meta.Tool.Version, meta.Tool.Revision, meta.Tool.Go)
+	fmt.Printf("生成时间 %s\n", meta.GeneratedAt.Format("2006-01-02 15:04:05Z07:00"))
+
+	got, err := studentgen.Reproduce(path, meta, *workers, logger)
+	if err != nil && !errors.Is(err, studentgen.ErrMismatch) {
+		return err
+	}
+	if *out != "" {
+		if werr := studentgen.WriteMeta(*out+studentgen.MetaSuffix, got); werr != nil {
+			return werr
+		}
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("校验通过 %d 行 sha256:%s\n", got.Output.Rows, got.Output.SHA256)
+
+	// 伴随文件旁的原文件若仍存在，顺带检查它是否被改动过。
+	if orig, ok := strings.CutSuffix(*metaPath, studentgen.MetaSuffix); ok {
+		if _, statErr := os.Stat(orig); statErr == nil {
+			sum, _, err := studentgen.FileSHA256(orig)
+			if err != nil {
+				return err
+			}
+			if sum != meta.Output.SHA256 {
+				return fmt.Errorf("%s 的内容已被修改(sha256:%s)，与生成时不一致", orig, sum)
+			}
+			fmt.Printf("原文件   %s 未被修改\n", orig)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/xianyudd/hanzi-data-kit/internal/cliutil"
+	"github.com/xianyudd/hanzi-data-kit/model"
+	"github.com/xianyudd/hanzi-data-kit/parser"
+	"github.com/xianyudd/hanzi-data-kit/sample"
+)
+
+func runSample(args []string) error {
+	fs := flag.NewFlagSet("sample", flag.ContinueOnError)
+	var (
+		in         = fs.String("in", "data/students.csv", "输入CSV路径")
+		out        = fs.String("out", "data/students_sample.csv", "输出CSV路径")
+		method     = fs.String("method", "reservoir", "抽样方法: reservoir|bernoulli|stratified")
+		n          = fs.Int("n", 100, "reservoir 的样本量；stratified 的每层样本量")
+		rate       = fs.Float64("rate", 0.1, "bernoulli 的保留概率(0~1)")
+		by         = fs.String("by", "city", "stratified 的分层依据: city|score")
+		bandWidth  = fs.Float64("band-width", 10, "按 score 分层时每段的宽度")
+		seed       = fs.Int64("seed", 42, "随机种子(用于复现)")
+		inputFlags = cliutil.RegisterCSVInputFlags(fs)
+		logFlags   = cliutil.RegisterLogFlags(fs)
+	)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	var (
+		add   func(parser.StudentRecord)
+		items func() []parser.StudentRecord
+	)
+	switch *method {
+	case "reservoir":
+		if *n <= 0 {
+			return usageError{fmt.Errorf("-n 必须为正数")}
+		}
+		r := sample.NewReservoir[parser.StudentRecord](*n, *seed)
+		add, items = r.Add, r.Items
+	case "bernoulli":
+		b, err := sample.NewBernoulli(*rate, *seed)
+		if err != nil {
+			return usageError{fmt.Errorf("-rate: %w", err)}
+		}
+		var kept []parser.StudentRecord
+		add = func(rec parser.StudentRecord) {
+			if b.Keep() {
+				kept = append(kept, rec)
+			}
+		}
+		items = func() []parser.StudentRecord { return kept }
+	case "stratified":
+		if *n <= 0 {
+			return usageError{fmt.Errorf("-n 必须为正数")}
+		}
+		var key func(model.Student) string
+		switch *by {
+		case "city":
+			key = sample.ByCity
+		case "score":
+			key = sample.ByScoreBand(*bandWidth)
+		default:
+			return usageError{fmt.Errorf("-by 只支持 city|score, 实际为 %q", *by)}
+		}
+		s := sample.NewStratified(func(rec parser.StudentRecord) string { return key(rec.Student) }, *n, *seed)
+		add, items = s.Add, s.Items
+	default:
+		return usageError{fmt.Errorf("-method 只支持 reservoir|bernoulli|stratified, 实际为 %q", *method)}
+	}
+
+	logger, err := logFlags.Logger(os.Stderr)
+	if err != nil {
+		return usageError{err}
+	}
+	opts, err := inputFlags.Options(logger)
+	if err != nil {
+		return usageError{err}
+	}
+
+	// 逐条读取，内存中只保留样本。
+	sc, err := parser.NewStudentScanner(*in, opts)
+	if err != nil {
+		return fmt.Errorf("解析CSV失败: %w", err)
+	}
+	defer sc.Close()
+	seen := 0
+	for {
+		rec, err := sc.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("解析CSV失败: %w", err)
+		}
+		add(rec)
+		seen++
+	}
+
+	table := &parser.StudentTable{Records: items(), ExtraHeaders: sc.ExtraHeaders()}
+	if err := parser.WriteStudentTableCSV(*out, table, parser.CSVWriteOptions{Dialect: opts.Dialect, Logger: logger}); err != nil {
+		return err
+	}
+	logger.Info("抽样完成", "method", *method, "in", seen, "out", len(table.Records))
+	return nil
+}
+
+func runSplit(args []string) error {
+	fs := flag.NewFlagSet("split", flag.ContinueOnError)
+	var (
+		in         = fs.String("in", "data/students.csv", "输入CSV路径")
+		outDir     = fs.String("out-dir", "data/split", "输出目录(每份写为 <名称>.csv)")
+		ratios     = fs.String("ratios", "8,2", "各份的相对权重(逗号分隔，如 8,1,1)")
+		names      = fs.String("names", "", "各份的名称(逗号分隔)；为空时 2 份为 train,test，3 份为 train,val,test，否则为 part-1..k")
+		key        = fs.String("key", "姓名,城市", "划分键(逗号分隔的列名，可用附加列如 学号)")
+		seed       = fs.Int64("seed", 42, "随机种子(用于复现)")
+		inputFlags = cliutil.RegisterCSVInputFlags(fs)
+		logFlags   = cliutil.RegisterLogFlags(fs)
+	)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	weights, err := parseFloats(*ratios)
+	if err != nil {
+		return usageError{fmt.Errorf("-ratios: %w", err)}
+	}
+	splitter, err := sample.NewSplitter(weights, *seed)
+	if err != nil {
+		return usageError{fmt.Errorf("-ratios: %w", err)}
+	}
+	partNames := cliutil.SplitList(*names)
+	if len(partNames) == 0 {
+		partNames = defaultPartNames(len(weights))
+	}
+	if len(partNames) != len(weights) {
+		return usageError{fmt.Errorf("-names 有 %d 项，与 -ratios 的 %d 项不一致", len(partNames), len(weights))}
+	}
+	keyCols := cliutil.SplitList(*key)
+	if len(keyCols) == 0 {
+		return usageError{fmt.Errorf("-key 不能为空")}
+	}
+
+	logger, err := logFlags.Logger(os.Stderr)
+	if err != nil {
+		return usageError{err}
+	}
+	opts, err := inputFlags.Options(logger)
+	if err != nil {
+		return usageError{err}
+	}
+
+	table, err := parser.ParseCSVToStudentTable(*in, opts)
+	if err != nil {
+		return fmt.Errorf("解析CSV失败: %w", err)
+	}
+	headers := table.Headers()
+	keyIdx := make([]int, len(keyCols))
+	for i, col := range keyCols {
+		if keyIdx[i] = slices.Index(headers, col); keyIdx[i] < 0 {
+			return usageError{fmt.Errorf("-key 中的列 %q 不存在（可选: %s）", col, strings.Join(headers, ","))}
+		}
+	}
+
+	parts := make([]*parser.StudentTable, len(weights))
+	for i := range parts {
+		parts[i] = &parser.StudentTable{ExtraHeaders: table.ExtraHeaders}
+	}
+	for i, rec := range table.Records {
+		row := table.Row(i)
+		vals := make([]string, len(keyIdx))
+		for j, k := range keyIdx {
+			vals[j] = row[k]
+		}
+		p := splitter.Part(strings.Join(vals, "\x1f"))
+		parts[p].Records = append(parts[p].Records, rec)
+	}
+
+	if err := os.MkdirAll(*outDir, 0o755); err != nil {
+		return fmt.Errorf("创建输出目录失败: %w", err)
+	}
+	wopts := parser.CSVWriteOptions{Dialect: opts.Dialect, Logger: logger}
+	for i, part := range parts {
+		path := filepath.Join(*outDir, partNames[i]+".csv")
+		if err := parser.WriteStudentTableCSV(path, part, wopts); err != nil {
+			return err
+		}
+		logger.Info("划分完成", "part", partNames[i], "rows", len(part.Records))
+	}
+	return nil
+}
+
+func defaultPartNames(k int) []string {
+	switch k {
+	case 2:
+		return []string{"train", "test"}
+	case 3:
+		return []string{"train", "val", "test"}
+	}
+	names := make([]string, k)
+	for i := range names {
+		names[i] = "part-" + strconv.Itoa(i+1)
+	}
+	return names
+}
+
+// parseFloats 解析逗号分隔的数字列表。
+func parseFloats(s string) ([]float64, error) {
+	var out []float64
+	for _, p := range cliutil.SplitList(s) {
+		v, err := strconv.ParseFloat(p, 64)
+		if err != nil {
+			return nil, fmt.Errorf("无法解析数字 %q", p)
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
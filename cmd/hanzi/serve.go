@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/xianyudd/hanzi-data-kit/internal/cliutil"
+	"github.com/xianyudd/hanzi-data-kit/rpc"
+	"github.com/xianyudd/hanzi-data-kit/server"
+)
+
+// shutdownTimeout 为收到退出信号后等待进行中请求完成的最长时间。
+const shutdownTimeout = 10 * time.Second
+
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	var (
+		addr        = fs.String("addr", ":8080", "HTTP 监听地址")
+		grpcAddr    = fs.String("grpc-addr", "", "gRPC 监听地址(为空时不启动 gRPC 服务)")
+		maxRows     = fs.Int("max-rows", 1_000_000, "GET /students 单次允许生成的最大行数")
+		maxUploadMB = fs.Int64("max-upload-mb", 32, "POST /parse、/validate 请求体的最大大小(MiB)")
+		logFlags    = cliutil.RegisterLogFlags(fs)
+	)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *maxRows <= 0 {
+		return usageError{fmt.Errorf("-max-rows 必须为正数, 实际为 %d", *maxRows)}
+	}
+	if *maxUploadMB <= 0 {
+		return usageError{fmt.Errorf("-max-upload-mb 必须为正数, 实际为 %d", *maxUploadMB)}
+	}
+	logger, err := logFlags.Logger(os.Stderr)
+	if err != nil {
+		return usageError{err}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ln, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return err
+	}
+	srv := &http.Server{
+		Handler: server.New(server.Options{
+			MaxRows:        *maxRows,
+			MaxUploadBytes: *maxUploadMB << 20,
+			Logger:         logger,
+		}),
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+
+	errc := make(chan error, 2)
+	go func() { errc <- srv.Serve(ln) }()
+	logger.Info("开始监听", "addr", ln.Addr().String())
+
+	if *grpcAddr != "" {
+		gln, err := net.Listen("tcp", *grpcAddr)
+		if err != nil {
+			srv.Close()
+			return err
+		}
+		gsrv := grpc.NewServer(grpc.MaxRecvMsgSize(int(min(*maxUploadMB<<20, math.MaxInt32))))
+		rpc.Register(gsrv, rpc.Options{MaxRows: int64(*maxRows), MaxUploadBytes: *maxUploadMB << 20})
+		go func() {
+			if err := gsrv.Serve(gln); err != nil {
+				errc <- err
+			}
+		}()
+		defer gsrv.GracefulStop()
+		logger.Info("开始监听 gRPC", "addr", gln.Addr().String())
+	}
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+	logger.Info("正在关闭")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
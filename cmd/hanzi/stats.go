@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/xianyudd/hanzi-data-kit/internal/cliutil"
+	"github.com/xianyudd/hanzi-data-kit/parser"
+	"github.com/xianyudd/hanzi-data-kit/stats"
+)
+
+func runStats(args []string) error {
+	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+	var (
+		in         = fs.String("in", "data/students.csv", "输入CSV路径")
+		format     = fs.String("format", "table", "输出格式: table|json")
+		histogram  = fs.Bool("hist", false, "table 格式下追加年龄/得分 ASCII 直方图")
+		bins       = fs.Int("bins", 10, "得分直方图桶数")
+		scoreLo    = fs.Float64("score-lo", 0, "得分直方图下界")
+		scoreHi    = fs.Float64("score-hi", 100, "得分直方图上界")
+		topCities  = fs.Int("top", 10, "城市分组最多展示的行数(0表示全部)")
+		barWidth   = fs.Int("bar-width", 40, "直方图最长条的字符数")
+		inputFlags = cliutil.RegisterCSVInputFlags(fs)
+		logFlags   = cliutil.RegisterLogFlags(fs)
+	)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *format != "table" && *format != "json" {
+		return usageError{fmt.Errorf("-format 只支持 table|json, 实际为 %q", *format)}
+	}
+
+	logger, err := logFlags.Logger(os.Stderr)
+	if err != nil {
+		return usageError{err}
+	}
+	opts, err := inputFlags.Options(logger)
+	if err != nil {
+		return usageError{err}
+	}
+
+	// 逐条读入累加器，内存占用与文件大小无关。
+	st := stats.NewStudentStats(stats.StudentStatsConfig{ScoreLo: *scoreLo, ScoreHi: *scoreHi, ScoreBins: *bins})
+	for stu, err := range parser.ScanStudents(*in, opts) {
+		if err != nil {
+			return fmt.Errorf("解析CSV失败: %w", err)
+		}
+		st.Add(stu)
+	}
+	report := st.Report()
+
+	if *format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false)
+		return enc.Encode(report)
+	}
+	return stats.WriteTable(os.Stdout, report, stats.TableOptions{
+		TopCities:  *topCities,
+		Histograms: *histogram,
+		BarWidth:   *barWidth,
+	})
+}
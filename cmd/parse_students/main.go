@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/xianyudd/hanzi-data-kit/internal/cliutil"
 	"github.com/xianyudd/hanzi-data-kit/parser"
 )
 
@@ -15,14 +16,58 @@ func main() {
 		skipBadRows = flag.Bool("skip-bad-rows", true, "遇到坏行是否跳过（否则严格报错）")
 		trimSpace   = flag.Bool("trim-space", true, "是否对字段做TrimSpace")
 		allowBOM    = flag.Bool("allow-bom", true, "是否剥离UTF-8 BOM")
+		delimiter   = flag.String("delimiter", ",", "字段分隔符(如 , ; | tab)")
+		comment     = flag.String("comment", "", "注释行起始字符(如 #，空表示不识别注释)")
+		lazyQuotes  = flag.Bool("lazy-quotes", false, "是否允许不规范的引号")
+		variable    = flag.Bool("variable-fields", false, "是否允许每行字段数不同")
+		sniff       = flag.Bool("sniff", false, "根据文件开头自动推断分隔符/注释符等(忽略上述方言参数)")
+		dupHeaders  = flag.String("dup-headers", "first", "重复列策略: first|error|last|merge|warn")
+		report      = flag.Bool("header-report", false, "只打印表头诊断报告(重复列/空表头/未识别列)后退出")
+		encoding    = flag.String("encoding", "utf-8", "输入文件编码: utf-8|gbk|gb18030|utf-16|auto")
 	)
+	logFlags := cliutil.RegisterLogFlags(flag.CommandLine)
 	flag.Parse()
 
-	students, err := parser.ParseCSVToStudentsWithOptions(*in, parser.CSVParseOptions{
-		TrimSpace:   *trimSpace,
-		AllowBOM:    *allowBOM,
-		SkipBadRows: *skipBadRows,
-	})
+	logger, err := logFlags.Logger(os.Stderr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "参数错误: %v\n", err)
+		os.Exit(2)
+	}
+
+	dupPolicy, err := parser.ParseDuplicateHeaderPolicy(*dupHeaders)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "参数错误: %v\n", err)
+		os.Exit(2)
+	}
+
+	dialect, err := cliutil.DialectFromFlags(*delimiter, *comment, *lazyQuotes, *variable)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "参数错误: %v\n", err)
+		os.Exit(2)
+	}
+
+	opts := parser.CSVParseOptions{
+		TrimSpace:        *trimSpace,
+		AllowBOM:         *allowBOM,
+		SkipBadRows:      *skipBadRows,
+		Dialect:          dialect,
+		SniffDialect:     *sniff,
+		DuplicateHeaders: dupPolicy,
+		Logger:           logger,
+		Encoding:         *encoding,
+	}
+
+	if *report {
+		hr, err := parser.DiagnoseHeaders(*in, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "读取表头失败: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Print(hr)
+		return
+	}
+
+	students, err := parser.ParseCSVToStudentsWithOptions(*in, opts)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "解析CSV失败: %v\n", err)
 		os.Exit(1)
@@ -0,0 +1,104 @@
+package contact
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Carrier 为移动通信运营商。
+type Carrier string
+
+const (
+	ChinaMobile   Carrier = "mobile"   // 中国移动
+	ChinaUnicom   Carrier = "unicom"   // 中国联通
+	ChinaTelecom  Carrier = "telecom"  // 中国电信
+	ChinaBroadnet Carrier = "broadnet" // 中国广电
+)
+
+// prefixes 为各运营商的手机号段（前 3 位）。
+var prefixes = map[Carrier][]string{
+	ChinaMobile: {"134", "135", "136", "137", "138", "139", "147", "150", "151", "152", "157", "158", "159",
+		"172", "178", "182", "183", "184", "187", "188", "195", "197", "198"},
+	ChinaUnicom:   {"130", "131", "132", "145", "155", "156", "166", "175", "176", "185", "186", "196"},
+	ChinaTelecom:  {"133", "149", "153", "173", "177", "180", "181", "189", "190", "191", "193", "199"},
+	ChinaBroadnet: {"192"},
+}
+
+// Carriers 返回全部运营商。
+func Carriers() []Carrier {
+	return []Carrier{ChinaMobile, ChinaUnicom, ChinaTelecom, ChinaBroadnet}
+}
+
+// ParseCarrier 解析运营商名称：mobile|unicom|telecom|broadnet。
+func ParseCarrier(s string) (Carrier, error) {
+	c := Carrier(strings.ToLower(strings.TrimSpace(s)))
+	if _, ok := prefixes[c]; !ok {
+		return "", fmt.Errorf("未知的运营商 %q(可选 mobile|unicom|telecom|broadnet)", s)
+	}
+	return c, nil
+}
+
+// Prefixes 返回运营商 c 的号段。
+func Prefixes(c Carrier) []string { return slices.Clone(prefixes[c]) }
+
+// CarrierOf 返回手机号（可为脱敏形式）所属的运营商。
+func CarrierOf(phone string) (Carrier, bool) {
+	if len(phone) < 3 {
+		return "", false
+	}
+	for c, ps := range prefixes {
+		if slices.Contains(ps, phone[:3]) {
+			return c, true
+		}
+	}
+	return "", false
+}
+
+// MaskPhone 将 11 位手机号的中间 4 位替换为 *，如 13812341234 → 138****1234；长度不为 11 时原样返回。
+func MaskPhone(phone string) string {
+	if len(phone) != 11 {
+		return phone
+	}
+	return phone[:3] + "****" + phone[7:]
+}
+
+var (
+	phoneRe    = regexp.MustCompile(`^1\d{10}$`)
+	maskedRe   = regexp.MustCompile(`^1\d{2}\*{4}\d{4}$`)
+	emailRe    = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._%+-]*@[A-Za-z0-9](?:[A-Za-z0-9-]*[A-Za-z0-9])?(?:\.[A-Za-z0-9](?:[A-Za-z0-9-]*[A-Za-z0-9])?)+$`)
+	usernameRe = regexp.MustCompile(`^[a-z][a-z0-9._]{2,31}$`)
+)
+
+// ValidatePhone 检查 s 是否为大陆手机号：11 位数字且号段属于已知运营商；也接受 138****1234 形式的脱敏号。
+func ValidatePhone(s string) error {
+	if !phoneRe.MatchString(s) && !maskedRe.MatchString(s) {
+		return fmt.Errorf("手机号格式错误: %q", s)
+	}
+	if _, ok := CarrierOf(s); !ok {
+		return fmt.Errorf("未知的手机号段 %s: %q", s[:3], s)
+	}
+	return nil
+}
+
+// ValidateEmail 检查 s 是否为常见格式的邮箱地址（本地部分@域名，域名至少含一个点）。
+func ValidateEmail(s string) error {
+	if len(s) > 254 || !emailRe.MatchString(s) {
+		return fmt.Errorf("邮箱格式错误: %q", s)
+	}
+	local, _, _ := strings.Cut(s, "@")
+	if len(local) > 64 || strings.Contains(local, "..") || strings.HasSuffix(local, ".") {
+		return fmt.Errorf("邮箱格式错误: %q", s)
+	}
+	return nil
+}
+
+// ValidateUsername 检查 s 是否为合法用户名：3~32 位，小写字母开头，只含小写字母、数字、. 与 _，且分隔符不连续、不结尾。
+func ValidateUsername(s string) error {
+	if !usernameRe.MatchString(s) || strings.Contains(s, "..") || strings.Contains(s, "__") ||
+		strings.HasSuffix(s, ".") || strings.HasSuffix(s, "_") {
+		return fmt.Errorf("用户名格式错误: %q", s)
+	}
+	return nil
+}
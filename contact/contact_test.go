@@ -0,0 +1,58 @@
+package contact_test
+
+import (
+	"testing"
+
+	"github.com/xianyudd/hanzi-data-kit/contact"
+)
+
+func TestValidators(t *testing.T) {
+	tests := []struct {
+		name  string
+		check func(string) error
+		in    string
+		ok    bool
+	}{
+		{"手机号", contact.ValidatePhone, "13812341234", true},
+		{"脱敏手机号", contact.ValidatePhone, "138****1234", true},
+		{"广电号段", contact.ValidatePhone, "19212345678", true},
+		{"未知号段", contact.ValidatePhone, "12012345678", false},
+		{"位数不足", contact.ValidatePhone, "1381234123", false},
+		{"脱敏位置错误", contact.ValidatePhone, "1381234****", false},
+		{"邮箱", contact.ValidateEmail, "zhang.san1990@163.com", true},
+		{"邮箱缺少域名点", contact.ValidateEmail, "zhangsan@localhost", false},
+		{"邮箱连续点", contact.ValidateEmail, "zhang..san@qq.com", false},
+		{"邮箱无@", contact.ValidateEmail, "zhangsan.qq.com", false},
+		{"用户名", contact.ValidateUsername, "zhang.san2", true},
+		{"用户名数字开头", contact.ValidateUsername, "2zhang", false},
+		{"用户名大写", contact.ValidateUsername, "ZhangSan", false},
+		{"用户名过短", contact.ValidateUsername, "zs", false},
+		{"用户名以分隔符结尾", contact.ValidateUsername, "zhang_", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.check(tt.in)
+			if (err == nil) != tt.ok {
+				t.Fatalf("%q: err = %v, want ok=%v", tt.in, err, tt.ok)
+			}
+		})
+	}
+}
+
+func TestCarrierOfAndMask(t *testing.T) {
+	if c, ok := contact.CarrierOf("13912345678"); !ok || c != contact.ChinaMobile {
+		t.Errorf("CarrierOf(139...) = %v, %v", c, ok)
+	}
+	if c, ok := contact.CarrierOf("133****5678"); !ok || c != contact.ChinaTelecom {
+		t.Errorf("CarrierOf(133****) = %v, %v", c, ok)
+	}
+	if got := contact.MaskPhone("13812341234"); got != "138****1234" {
+		t.Errorf("MaskPhone = %q", got)
+	}
+	if _, err := contact.ParseCarrier("Unicom"); err != nil {
+		t.Error(err)
+	}
+	if _, err := contact.ParseCarrier("cmcc"); err == nil {
+		t.Error("未知运营商应返回 error")
+	}
+}
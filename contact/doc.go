@@ -0,0 +1,4 @@
+// Package contact 提供联系方式相关的基础数据与格式校验：大陆手机号段（按运营商）、手机号脱敏、邮箱与用户名格式。
+//
+// 生成见 generator.ContactGenerator；在解析时校验列格式见 parser.CSVParseOptions.ColumnValidators。
+package contact
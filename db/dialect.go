@@ -0,0 +1,71 @@
+package db
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Dialect 为 SQL 方言，决定标识符引号、占位符、列类型与 upsert 语法。
+type Dialect int
+
+const (
+	// SQLite 使用 "标识符"、? 占位符与 ON CONFLICT upsert（零值）。
+	SQLite Dialect = iota
+	// Postgres 使用 "标识符"、$n 占位符与 ON CONFLICT upsert。
+	Postgres
+	// MySQL 使用 `标识符`、? 占位符与 ON DUPLICATE KEY UPDATE upsert。
+	MySQL
+)
+
+var dialectNames = []string{"sqlite", "postgres", "mysql"}
+
+// String 返回方言名称（sqlite/postgres/mysql）。
+func (d Dialect) String() string {
+	if d < 0 || int(d) >= len(dialectNames) {
+		return fmt.Sprintf("Dialect(%d)", int(d))
+	}
+	return dialectNames[d]
+}
+
+// ParseDialect 将方言名或常见的驱动名解析为 Dialect：
+// sqlite/sqlite3 → SQLite，postgres/postgresql/pgx → Postgres，mysql → MySQL。
+func ParseDialect(name string) (Dialect, error) {
+	switch strings.ToLower(name) {
+	case "sqlite", "sqlite3":
+		return SQLite, nil
+	case "postgres", "postgresql", "pgx":
+		return Postgres, nil
+	case "mysql":
+		return MySQL, nil
+	}
+	return 0, fmt.Errorf("未知的SQL方言: %q（可选: %s）", name, strings.Join(dialectNames, "|"))
+}
+
+// QuoteIdent 按方言为标识符加引号并转义。
+func (d Dialect) QuoteIdent(name string) string {
+	if d == MySQL {
+		return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// placeholder 返回第 n 个（1-based）参数的占位符。
+func (d Dialect) placeholder(n int) string {
+	if d == Postgres {
+		return "$" + strconv.Itoa(n)
+	}
+	return "?"
+}
+
+// columnTypes 返回 姓名/年龄/城市/得分 在该方言下的列类型。
+// MySQL 的文本列使用 VARCHAR，以便作为 upsert 的唯一键。
+func (d Dialect) columnTypes() [4]string {
+	switch d {
+	case Postgres:
+		return [4]string{"TEXT", "INTEGER", "TEXT", "DOUBLE PRECISION"}
+	case MySQL:
+		return [4]string{"VARCHAR(64)", "INT", "VARCHAR(64)", "DOUBLE"}
+	}
+	return [4]string{"TEXT", "INTEGER", "TEXT", "REAL"}
+}
@@ -0,0 +1,6 @@
+// Package db 通过 database/sql 将学生数据批量写入 SQL 数据库（SQLite / PostgreSQL / MySQL）。
+//
+// 写入在一个事务中以多行 INSERT（每条语句 BatchSize 行）完成；可按模型自动建表（按方言选择列类型），
+// 并支持按键 upsert。本包只依赖 database/sql，驱动由调用方导入并注册，
+// 例如纯 Go 的 SQLite 驱动 modernc.org/sqlite（驱动名 "sqlite"）。
+package db
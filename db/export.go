@@ -0,0 +1,193 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"iter"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xianyudd/hanzi-data-kit/model"
+	"github.com/xianyudd/hanzi-data-kit/parser"
+)
+
+// ExportOptions 控制导出行为。
+type ExportOptions struct {
+	// Columns 为数据库中 Student 各字段的列名；为空的字段使用 DefaultColumns() 中的对应列名。
+	// 结果列名与之相同，或是 CSV 表头别名（姓名/name/score 等，见 parser.DefaultStudentHeaderAliases）时，
+	// 映射为对应的中文标准表头。
+	Columns Columns
+
+	// CSV 为写出 CSV 时使用的方言与日志。
+	CSV parser.CSVWriteOptions
+}
+
+// ExportCSV 执行 query 并将结果流式写入 CSV 文件 filename，返回写入的数据行数。
+//
+// 识别为 Student 字段的列使用中文标准表头，并按 model.StudentToRowCN 的格式输出
+// （年龄为整数、得分保留 1 位小数），排在最前面；其余列按查询中的顺序附加在后，
+// 因此导出结果可以直接与生成的数据或 ParseCSVToStudentTable 的输出做 diff。
+// NULL 输出为空字符串。
+func ExportCSV(ctx context.Context, db *sql.DB, filename, query string, opts ExportOptions, args ...any) (int, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("执行查询失败: %w", err)
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+	layout := newExportLayout(cols, opts.Columns)
+
+	return parser.WriteCSVRows(filename, layout.headers, layout.rows(rows), opts.CSV)
+}
+
+// exportLayout 描述结果列到 CSV 列的映射。
+type exportLayout struct {
+	headers []string
+	// source[i] 为第 i 个 CSV 列在结果集中的下标；kind[i] 为其格式。
+	source []int
+	kind   []valueKind
+}
+
+// valueKind 决定单元格的格式化方式。
+type valueKind int
+
+const (
+	textValue  valueKind = iota // 原样输出
+	ageValue                    // 整数
+	scoreValue                  // 保留 1 位小数
+)
+
+func newExportLayout(cols []string, names Columns) exportLayout {
+	lookup := studentColumnLookup(names)
+	std := make(map[string]int) // 中文标准列 -> 结果下标（重复时第一个生效）
+	var extra []int
+	for i, c := range cols {
+		if h, ok := lookup[parser.NormalizeHeader(c)]; ok {
+			if _, dup := std[h]; !dup {
+				std[h] = i
+				continue
+			}
+		}
+		extra = append(extra, i)
+	}
+
+	var l exportLayout
+	for _, h := range model.StudentHeadersCN() {
+		i, ok := std[h]
+		if !ok {
+			continue
+		}
+		kind := textValue
+		switch h {
+		case "年龄":
+			kind = ageValue
+		case "得分":
+			kind = scoreValue
+		}
+		l.headers = append(l.headers, h)
+		l.source = append(l.source, i)
+		l.kind = append(l.kind, kind)
+	}
+	for _, i := range extra {
+		l.headers = append(l.headers, cols[i])
+		l.source = append(l.source, i)
+		l.kind = append(l.kind, textValue)
+	}
+	return l
+}
+
+// rows 逐行扫描结果集并格式化为 CSV 行。
+func (l exportLayout) rows(rows *sql.Rows) iter.Seq2[[]string, error] {
+	return func(yield func([]string, error) bool) {
+		cols, _ := rows.Columns()
+		vals := make([]any, len(cols))
+		ptrs := make([]any, len(cols))
+		for i := range vals {
+			ptrs[i] = &vals[i]
+		}
+		line := 0
+		for rows.Next() {
+			line++
+			if err := rows.Scan(ptrs...); err != nil {
+				yield(nil, fmt.Errorf("读取第 %d 行失败: %w", line, err))
+				return
+			}
+			out := make([]string, len(l.headers))
+			for j, i := range l.source {
+				s, err := formatValue(vals[i], l.kind[j])
+				if err != nil {
+					yield(nil, fmt.Errorf("第 %d 行列 %s: %w", line, l.headers[j], err))
+					return
+				}
+				out[j] = s
+			}
+			if !yield(out, nil) {
+				return
+			}
+		}
+		if err := rows.Err(); err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+// formatValue 将驱动返回的值按 kind 格式化为单元格文本。
+func formatValue(v any, kind valueKind) (string, error) {
+	if v == nil {
+		return "", nil
+	}
+	var text string
+	switch v := v.(type) {
+	case string:
+		text = v
+	case []byte:
+		text = string(v)
+	case int64:
+		text = strconv.FormatInt(v, 10)
+	case float64:
+		text = strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		text = strconv.FormatBool(v)
+	case time.Time:
+		text = v.Format(time.RFC3339)
+	default:
+		text = fmt.Sprint(v)
+	}
+	if kind == textValue {
+		return text, nil
+	}
+
+	f, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+	if err != nil {
+		return "", fmt.Errorf("无法解析为数字: %q", text)
+	}
+	if kind == ageValue {
+		if f != math.Trunc(f) {
+			return "", fmt.Errorf("年龄不是整数: %q", text)
+		}
+		return strconv.Itoa(int(f)), nil
+	}
+	return model.StudentToRowCN(model.Student{Score: f})[3], nil
+}
+
+// studentColumnLookup 返回 “规范化列名 -> 中文标准列名” 的查找表：包含 names 中的列名与 CSV 表头别名。
+func studentColumnLookup(names Columns) map[string]string {
+	names = names.withDefaults()
+	m := make(map[string]string)
+	aliases := parser.DefaultStudentHeaderAliases()
+	for i, h := range model.StudentHeadersCN() {
+		m[parser.NormalizeHeader(h)] = h
+		for _, a := range aliases[h] {
+			m[parser.NormalizeHeader(a)] = h
+		}
+		m[parser.NormalizeHeader(names.list()[i])] = h
+	}
+	return m
+}
@@ -0,0 +1,75 @@
+package db_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/xianyudd/hanzi-data-kit/db"
+	"github.com/xianyudd/hanzi-data-kit/model"
+	"github.com/xianyudd/hanzi-data-kit/parser"
+)
+
+func TestExportCSV(t *testing.T) {
+	conn := openSQLite(t)
+	ctx := context.Background()
+
+	students := []model.Student{
+		{Name: "张三", Age: 22, City: "北京", Score: 95.0},
+		{Name: "李四", Age: 25, City: "上海", Score: 88.5},
+	}
+	if _, err := db.Insert(ctx, conn, slices.Values(students), db.Options{CreateTable: true}); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{
+			name:  "round_trip_matches_writer_format",
+			query: `SELECT * FROM students ORDER BY age`,
+			want:  "姓名,年龄,城市,得分\n张三,22,北京,95.0\n李四,25,上海,88.5\n",
+		},
+		{
+			name:  "reordered_with_extra_and_null",
+			query: `SELECT score, upper('s') || rowid AS 学号, name, NULL AS 备注, age * 1.0 AS age FROM students WHERE age > 23`,
+			want:  "姓名,年龄,得分,学号,备注\n李四,25,88.5,S2,\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := filepath.Join(t.TempDir(), "out.csv")
+			if _, err := db.ExportCSV(ctx, conn, out, tt.query, db.ExportOptions{}); err != nil {
+				t.Fatalf("export: %v", err)
+			}
+			got, err := os.ReadFile(out)
+			if err != nil {
+				t.Fatalf("read output failed: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Fatalf("output mismatch:\n  got:  %q\n  want: %q", got, tt.want)
+			}
+		})
+	}
+
+	// 导出的文件应能被原解析器读回。
+	out := filepath.Join(t.TempDir(), "out.csv")
+	if _, err := db.ExportCSV(ctx, conn, out, `SELECT name, age, city, score FROM students WHERE age > ?`, db.ExportOptions{}, 20); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	parsed, err := parser.ParseCSVToStudents(out)
+	if err != nil {
+		t.Fatalf("parse exported csv: %v", err)
+	}
+	if !slices.Equal(parsed, students) {
+		t.Fatalf("round trip mismatch: %+v", parsed)
+	}
+
+	if _, err := db.ExportCSV(ctx, conn, out, `SELECT 'abc' AS age`, db.ExportOptions{}); err == nil {
+		t.Fatalf("expected error for non-numeric age")
+	}
+}
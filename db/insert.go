@@ -0,0 +1,292 @@
+package db
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"fmt"
+	"iter"
+	"slices"
+	"strings"
+
+	"github.com/xianyudd/hanzi-data-kit/model"
+)
+
+// DefaultBatchSize 为每条多行 INSERT 语句包含的默认行数。
+const DefaultBatchSize = 500
+
+// Columns 为 Student 各字段在数据库表中的列名。
+type Columns struct {
+	Name, Age, City, Score string
+}
+
+// DefaultColumns 返回默认列名 name/age/city/score。
+func DefaultColumns() Columns {
+	return Columns{Name: "name", Age: "age", City: "city", Score: "score"}
+}
+
+// list 按 Student 字段顺序返回列名。
+func (c Columns) list() []string {
+	return []string{c.Name, c.Age, c.City, c.Score}
+}
+
+// withDefaults 将为空的字段逐个补全为 DefaultColumns() 中的对应列名，避免生成空的 SQL 标识符。
+func (c Columns) withDefaults() Columns {
+	def := DefaultColumns()
+	return Columns{
+		Name:  cmp.Or(c.Name, def.Name),
+		Age:   cmp.Or(c.Age, def.Age),
+		City:  cmp.Or(c.City, def.City),
+		Score: cmp.Or(c.Score, def.Score),
+	}
+}
+
+// Options 控制写入行为。
+type Options struct {
+	// Dialect 为目标数据库的 SQL 方言；零值为 SQLite。
+	Dialect Dialect
+
+	// Table 为目标表名；为空时为 "students"。
+	Table string
+
+	// Columns 为各字段的列名；为空的字段使用 DefaultColumns() 中的对应列名，补全后列名不能重复。
+	Columns Columns
+
+	// BatchSize 为每条 INSERT 语句的行数；<=0 时使用 DefaultBatchSize。
+	BatchSize int
+
+	// CreateTable 为 true 时，写入前执行 CREATE TABLE IF NOT EXISTS（列类型按方言选择）。
+	CreateTable bool
+
+	// UpsertKey 非空时按这些列（使用 Columns 中的列名，如 name、city）做 upsert：
+	// 键已存在的行更新其余列。自动建表时会为这些列建立唯一约束；
+	// 已有的表需要调用方自行保证存在对应的唯一约束/主键。
+	UpsertKey []string
+}
+
+func defaultOptions() Options {
+	return Options{Table: "students", Columns: DefaultColumns(), BatchSize: DefaultBatchSize}
+}
+
+// normalize 补全默认值并校验列名与 UpsertKey。
+func (o Options) normalize() (Options, error) {
+	def := defaultOptions()
+	if o.Table == "" {
+		o.Table = def.Table
+	}
+	o.Columns = o.Columns.withDefaults()
+	if o.BatchSize <= 0 {
+		o.BatchSize = def.BatchSize
+	}
+	cols := o.Columns.list()
+	for i, c := range cols {
+		if slices.Contains(cols[:i], c) {
+			return o, fmt.Errorf("列名 %q 重复（Columns: %s）", c, strings.Join(cols, ","))
+		}
+	}
+	for _, k := range o.UpsertKey {
+		if !slices.Contains(cols, k) {
+			return o, fmt.Errorf("upsert 键 %q 不是表中的列（可选: %s）", k, strings.Join(cols, ","))
+		}
+	}
+	return o, nil
+}
+
+// CreateTableSQL 返回按 opts 建表的 DDL 语句。
+func CreateTableSQL(opts Options) (string, error) {
+	opts, err := opts.normalize()
+	if err != nil {
+		return "", err
+	}
+	d := opts.Dialect
+	types := d.columnTypes()
+	defs := make([]string, 0, 5)
+	for i, c := range opts.Columns.list() {
+		defs = append(defs, d.QuoteIdent(c)+" "+types[i])
+	}
+	if len(opts.UpsertKey) > 0 {
+		defs = append(defs, "UNIQUE ("+quoteAll(d, opts.UpsertKey)+")")
+	}
+	return fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s)", d.QuoteIdent(opts.Table), strings.Join(defs, ", ")), nil
+}
+
+// InsertSQL 返回一次插入 rows 行的多行 INSERT 语句（含 upsert 子句）。
+func InsertSQL(opts Options, rows int) (string, error) {
+	opts, err := opts.normalize()
+	if err != nil {
+		return "", err
+	}
+	d := opts.Dialect
+	cols := opts.Columns.list()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "INSERT INTO %s (%s) VALUES ", d.QuoteIdent(opts.Table), quoteAll(d, cols))
+	n := 0
+	for r := 0; r < rows; r++ {
+		if r > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteByte('(')
+		for i := range cols {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			n++
+			b.WriteString(d.placeholder(n))
+		}
+		b.WriteByte(')')
+	}
+
+	if len(opts.UpsertKey) == 0 {
+		return b.String(), nil
+	}
+	var updates []string
+	for _, c := range cols {
+		if slices.Contains(opts.UpsertKey, c) {
+			continue
+		}
+		q := d.QuoteIdent(c)
+		if d == MySQL {
+			updates = append(updates, q+" = VALUES("+q+")")
+		} else {
+			updates = append(updates, q+" = excluded."+q)
+		}
+	}
+	switch {
+	case d == MySQL:
+		fmt.Fprintf(&b, " ON DUPLICATE KEY UPDATE %s", strings.Join(updates, ", "))
+	case len(updates) == 0:
+		fmt.Fprintf(&b, " ON CONFLICT (%s) DO NOTHING", quoteAll(d, opts.UpsertKey))
+	default:
+		fmt.Fprintf(&b, " ON CONFLICT (%s) DO UPDATE SET %s", quoteAll(d, opts.UpsertKey), strings.Join(updates, ", "))
+	}
+	return b.String(), nil
+}
+
+func quoteAll(d Dialect, names []string) string {
+	quoted := make([]string, len(names))
+	for i, n := range names {
+		quoted[i] = d.QuoteIdent(n)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// Insert 在一个事务中将 students 批量写入数据库，返回写入的行数（upsert 更新的行也计入）。
+// 任一批写入失败时整个事务回滚。
+func Insert(ctx context.Context, db *sql.DB, students iter.Seq[model.Student], opts Options) (int, error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("开启事务失败: %w", err)
+	}
+	defer tx.Rollback()
+
+	n, err := InsertTx(ctx, tx, students, opts)
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("提交事务失败: %w", err)
+	}
+	return n, nil
+}
+
+// InsertTx 与 Insert 相同，但在调用方提供的事务中执行，不提交也不回滚。
+func InsertTx(ctx context.Context, tx *sql.Tx, students iter.Seq[model.Student], opts Options) (int, error) {
+	opts, err := opts.normalize()
+	if err != nil {
+		return 0, err
+	}
+	if opts.CreateTable {
+		ddl, _ := CreateTableSQL(opts)
+		if _, err := tx.ExecContext(ctx, ddl); err != nil {
+			return 0, fmt.Errorf("创建表 %s 失败: %w", opts.Table, err)
+		}
+	}
+
+	// 满批次的语句只预编译一次；最后不足一批的行单独生成语句。
+	fullSQL, _ := InsertSQL(opts, opts.BatchSize)
+	full, err := tx.PrepareContext(ctx, fullSQL)
+	if err != nil {
+		return 0, fmt.Errorf("预编译插入语句失败: %w", err)
+	}
+	defer full.Close()
+
+	b := &batch{opts: opts, args: make([]any, 0, opts.BatchSize*4)}
+	total := 0
+	flush := func() error {
+		if b.rows == 0 {
+			return nil
+		}
+		var err error
+		if b.rows == opts.BatchSize {
+			_, err = full.ExecContext(ctx, b.args...)
+		} else {
+			query, _ := InsertSQL(opts, b.rows)
+			_, err = tx.ExecContext(ctx, query, b.args...)
+		}
+		if err != nil {
+			return fmt.Errorf("写入第 %d~%d 行失败: %w", total+1, total+b.rows, err)
+		}
+		total += b.rows
+		b.reset()
+		return nil
+	}
+
+	for stu := range students {
+		// 同一条 upsert 语句中不能两次命中同一个键（PostgreSQL 会报错），遇到重复键先写出当前批次。
+		if b.hasKey(stu) {
+			if err := flush(); err != nil {
+				return total, err
+			}
+		}
+		b.add(stu)
+		if b.rows == opts.BatchSize {
+			if err := flush(); err != nil {
+				return total, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return total, err
+	}
+	return total, nil
+}
+
+// batch 累积一条多行 INSERT 的参数。
+type batch struct {
+	opts Options
+	args []any
+	rows int
+	keys map[string]bool // 仅在 upsert 时使用
+}
+
+func (b *batch) add(stu model.Student) {
+	b.args = append(b.args, stu.Name, stu.Age, stu.City, stu.Score)
+	b.rows++
+	if len(b.opts.UpsertKey) > 0 {
+		if b.keys == nil {
+			b.keys = make(map[string]bool)
+		}
+		b.keys[b.key(stu)] = true
+	}
+}
+
+func (b *batch) hasKey(stu model.Student) bool {
+	return len(b.opts.UpsertKey) > 0 && b.keys[b.key(stu)]
+}
+
+func (b *batch) reset() {
+	b.args = b.args[:0]
+	b.rows = 0
+	clear(b.keys)
+}
+
+func (b *batch) key(stu model.Student) string {
+	row := model.StudentToRowCN(stu)
+	cols := b.opts.Columns.list()
+	parts := make([]string, len(b.opts.UpsertKey))
+	for i, k := range b.opts.UpsertKey {
+		parts[i] = row[slices.Index(cols, k)]
+	}
+	return strings.Join(parts, "\x1f")
+}
@@ -0,0 +1,181 @@
+package db_test
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"testing"
+
+	"github.com/xianyudd/hanzi-data-kit/db"
+	"github.com/xianyudd/hanzi-data-kit/generator"
+	"github.com/xianyudd/hanzi-data-kit/model"
+
+	_ "modernc.org/sqlite"
+)
+
+func openSQLite(t *testing.T) *sql.DB {
+	t.Helper()
+	conn, err := sql.Open("sqlite", "file::memory:")
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	conn.SetMaxOpenConns(1)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestInsert_Batches(t *testing.T) {
+	conn := openSQLite(t)
+	ctx := context.Background()
+
+	g := generator.NewStudentGenerator(generator.StudentGenConfig{Seed: 42})
+	var students []model.Student
+	for i := 0; i < 1234; i++ {
+		students = append(students, g.Next())
+	}
+
+	n, err := db.Insert(ctx, conn, slices.Values(students), db.Options{CreateTable: true, BatchSize: 100})
+	if err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	if n != len(students) {
+		t.Fatalf("expected %d rows written, got %d", len(students), n)
+	}
+
+	var count int
+	var sum float64
+	if err := conn.QueryRowContext(ctx, `SELECT count(*), sum(score) FROM students`).Scan(&count, &sum); err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	var want float64
+	for _, s := range students {
+		want += s.Score
+	}
+	if count != len(students) || sum != want {
+		t.Fatalf("got count=%d sum=%v, want count=%d sum=%v", count, sum, len(students), want)
+	}
+}
+
+func TestInsert_Upsert(t *testing.T) {
+	conn := openSQLite(t)
+	ctx := context.Background()
+	opts := db.Options{
+		Table:       "学生",
+		Columns:     db.Columns{Name: "姓名", Age: "年龄", City: "城市", Score: "得分"},
+		CreateTable: true,
+		UpsertKey:   []string{"姓名", "城市"},
+		BatchSize:   2,
+	}
+
+	first := []model.Student{
+		{Name: "张三", Age: 22, City: "北京", Score: 95.0},
+		{Name: "李四", Age: 25, City: "上海", Score: 88.5},
+	}
+	if _, err := db.Insert(ctx, conn, slices.Values(first), opts); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+	// 同一批次内重复的键也应正确处理（后者生效）。
+	second := []model.Student{
+		{Name: "张三", Age: 23, City: "北京", Score: 90.0},
+		{Name: "张三", Age: 24, City: "北京", Score: 91.0},
+		{Name: "王五", Age: 28, City: "广州", Score: 92.5},
+	}
+	if _, err := db.Insert(ctx, conn, slices.Values(second), opts); err != nil {
+		t.Fatalf("upsert: %v", err)
+	}
+
+	rows, err := conn.QueryContext(ctx, `SELECT "姓名", "年龄", "城市", "得分" FROM "学生" ORDER BY "年龄"`)
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	defer rows.Close()
+	var got []model.Student
+	for rows.Next() {
+		var s model.Student
+		if err := rows.Scan(&s.Name, &s.Age, &s.City, &s.Score); err != nil {
+			t.Fatalf("scan: %v", err)
+		}
+		got = append(got, s)
+	}
+	want := []model.Student{
+		{Name: "张三", Age: 24, City: "北京", Score: 91.0},
+		{Name: "李四", Age: 25, City: "上海", Score: 88.5},
+		{Name: "王五", Age: 28, City: "广州", Score: 92.5},
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("rows mismatch:\n  got:  %+v\n  want: %+v", got, want)
+	}
+}
+
+func TestInsert_RollbackOnError(t *testing.T) {
+	conn := openSQLite(t)
+	ctx := context.Background()
+	if _, err := conn.ExecContext(ctx, `CREATE TABLE students (name TEXT NOT NULL CHECK (name <> ''), age INTEGER, city TEXT, score REAL)`); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	students := []model.Student{{Name: "张三", Age: 22}, {Name: "李四", Age: 25}, {Name: "", Age: 1}}
+	if _, err := db.Insert(ctx, conn, slices.Values(students), db.Options{BatchSize: 2}); err == nil {
+		t.Fatalf("expected error for invalid row")
+	}
+	var count int
+	if err := conn.QueryRowContext(ctx, `SELECT count(*) FROM students`).Scan(&count); err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if count != 0 {
+		t.Fatalf("transaction should roll back, found %d rows", count)
+	}
+}
+
+func TestSQLGeneration(t *testing.T) {
+	tests := []struct {
+		name string
+		opts db.Options
+		ddl  string
+		ins  string
+	}{
+		{
+			name: "postgres_upsert",
+			opts: db.Options{Dialect: db.Postgres, UpsertKey: []string{"name", "city"}},
+			ddl:  `CREATE TABLE IF NOT EXISTS "students" ("name" TEXT, "age" INTEGER, "city" TEXT, "score" DOUBLE PRECISION, UNIQUE ("name", "city"))`,
+			ins:  `INSERT INTO "students" ("name", "age", "city", "score") VALUES ($1, $2, $3, $4), ($5, $6, $7, $8) ON CONFLICT ("name", "city") DO UPDATE SET "age" = excluded."age", "score" = excluded."score"`,
+		},
+		{
+			name: "mysql_upsert",
+			opts: db.Options{Dialect: db.MySQL, Table: "stu", UpsertKey: []string{"name"}},
+			ddl:  "CREATE TABLE IF NOT EXISTS `stu` (`name` VARCHAR(64), `age` INT, `city` VARCHAR(64), `score` DOUBLE, UNIQUE (`name`))",
+			ins:  "INSERT INTO `stu` (`name`, `age`, `city`, `score`) VALUES (?, ?, ?, ?), (?, ?, ?, ?) ON DUPLICATE KEY UPDATE `age` = VALUES(`age`), `city` = VALUES(`city`), `score` = VALUES(`score`)",
+		},
+		{
+			// 只设置部分列名时，其余列逐个使用默认列名。
+			name: "partial_columns",
+			opts: db.Options{Columns: db.Columns{Score: "总分"}},
+			ddl:  `CREATE TABLE IF NOT EXISTS "students" ("name" TEXT, "age" INTEGER, "city" TEXT, "总分" REAL)`,
+			ins:  `INSERT INTO "students" ("name", "age", "city", "总分") VALUES (?, ?, ?, ?), (?, ?, ?, ?)`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ddl, err := db.CreateTableSQL(tt.opts)
+			if err != nil {
+				t.Fatalf("ddl: %v", err)
+			}
+			if ddl != tt.ddl {
+				t.Fatalf("ddl mismatch:\n  got:  %s\n  want: %s", ddl, tt.ddl)
+			}
+			ins, err := db.InsertSQL(tt.opts, 2)
+			if err != nil {
+				t.Fatalf("insert sql: %v", err)
+			}
+			if ins != tt.ins {
+				t.Fatalf("insert sql mismatch:\n  got:  %s\n  want: %s", ins, tt.ins)
+			}
+		})
+	}
+
+	if _, err := db.CreateTableSQL(db.Options{UpsertKey: []string{"学号"}}); err == nil {
+		t.Fatalf("expected error for unknown upsert key")
+	}
+	if _, err := db.CreateTableSQL(db.Options{Columns: db.Columns{Name: "city"}}); err == nil {
+		t.Fatalf("expected error for duplicate column names")
+	}
+}
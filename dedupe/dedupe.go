@@ -0,0 +1,272 @@
+package dedupe
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/xianyudd/hanzi-data-kit/hanzi"
+	"github.com/xianyudd/hanzi-data-kit/model"
+)
+
+// Options 控制去重策略。
+type Options struct {
+	// Key 为精确去重使用的列（姓名/年龄/城市/得分 的子集）；为空时使用全部四列。
+	// 姓名与城市在比较前会先规范化（去空白、全角转半角、繁体转简体）。
+	// Fuzzy=true 时忽略 Key。
+	Key []string
+
+	// Fuzzy 为 true 时启用模糊匹配。
+	Fuzzy bool
+
+	// NameThreshold 为姓名相似度阈值（0~1），达到即视为同名；<=0 时为 0.85。
+	// 相似度取“汉字编辑距离相似度”与“拼音编辑距离相似度”中的较大者，同音字的拼音相似度为 1。
+	NameThreshold float64
+
+	// AnyCity 为 true 时不要求城市相同（默认要求规范化后的城市相同）。
+	AnyCity bool
+
+	// AgeTolerance 为年龄容差（含）；两条记录年龄差超过它则不匹配。
+	AgeTolerance int
+}
+
+// DefaultFuzzyOptions 返回推荐的模糊去重配置：姓名相似度 0.85、要求同城、年龄容差 1 岁。
+func DefaultFuzzyOptions() Options {
+	return Options{Fuzzy: true, NameThreshold: 0.85, AgeTolerance: 1}
+}
+
+// Cluster 是一组被判定为同一人的记录。
+type Cluster struct {
+	// Representative 为代表记录在输入中的下标：簇内与其他成员匹配次数最多者，并列时取最靠前的。
+	Representative int `json:"representative"`
+
+	// Members 为全部成员的下标（升序，含代表记录）。
+	Members []int `json:"members"`
+}
+
+// Result 为去重结果。
+type Result struct {
+	// Clusters 只包含成员数不少于 2 的簇，按代表记录下标升序排列。
+	Clusters []Cluster `json:"clusters"`
+
+	// Keep 为去重后保留的记录下标（各簇的代表记录与未重复的记录），按原顺序排列。
+	Keep []int `json:"keep"`
+}
+
+// Apply 返回 students 中被 Keep 保留的记录。
+func (r *Result) Apply(students []model.Student) []model.Student {
+	out := make([]model.Student, len(r.Keep))
+	for i, k := range r.Keep {
+		out[i] = students[k]
+	}
+	return out
+}
+
+// Dedupe 对 students 做去重，返回聚簇结果。
+func Dedupe(students []model.Student, opts Options) (*Result, error) {
+	if opts.NameThreshold <= 0 {
+		opts.NameThreshold = 0.85
+	}
+	uf := newUnionFind(len(students))
+	matches := make([]int, len(students))
+
+	if opts.Fuzzy {
+		fuzzyMatch(students, opts, uf, matches)
+	} else {
+		key := opts.Key
+		if len(key) == 0 {
+			key = model.StudentHeadersCN()
+		}
+		first := make(map[string]int, len(students))
+		for i, stu := range students {
+			k, err := exactKey(stu, key)
+			if err != nil {
+				return nil, err
+			}
+			if j, ok := first[k]; ok {
+				uf.union(i, j)
+				matches[i]++
+				matches[j]++
+			} else {
+				first[k] = i
+			}
+		}
+	}
+	return buildResult(uf, matches), nil
+}
+
+// exactKey 拼接精确去重键。
+func exactKey(stu model.Student, key []string) (string, error) {
+	parts := make([]string, len(key))
+	for i, col := range key {
+		switch col {
+		case "姓名":
+			parts[i] = hanzi.NormalizeName(stu.Name)
+		case "年龄":
+			parts[i] = strconv.Itoa(stu.Age)
+		case "城市":
+			parts[i] = hanzi.NormalizeName(stu.City)
+		case "得分":
+			parts[i] = strconv.FormatFloat(stu.Score, 'f', -1, 64)
+		default:
+			return "", fmt.Errorf("不支持的去重列: %s（可选: 姓名/年龄/城市/得分）", col)
+		}
+	}
+	return strings.Join(parts, "\x1f"), nil
+}
+
+// candidate 是预先计算好的比较特征。
+type candidate struct {
+	idx    int
+	name   []rune
+	pinyin string
+	city   string
+	age    int
+}
+
+// fuzzyMatch 对候选记录两两比较。为避免 O(n²)，先按“城市 + 姓氏拼音首字母”分块，只在块内比较；
+// 因此姓氏首字母也写错的记录不会被匹配。
+func fuzzyMatch(students []model.Student, opts Options, uf *unionFind, matches []int) {
+	blocks := make(map[string][]candidate)
+	var order []string
+	for i, stu := range students {
+		name := hanzi.NormalizeName(stu.Name)
+		py := hanzi.NamePinyin(name)
+		c := candidate{
+			idx:    i,
+			name:   []rune(name),
+			pinyin: strings.Join(py, " "),
+			city:   hanzi.NormalizeName(stu.City),
+			age:    stu.Age,
+		}
+		block := hanzi.Initials(py[:min(1, len(py))])
+		if !opts.AnyCity {
+			block = c.city + "\x1f" + block
+		}
+		if _, ok := blocks[block]; !ok {
+			order = append(order, block)
+		}
+		blocks[block] = append(blocks[block], c)
+	}
+
+	for _, b := range order {
+		cs := blocks[b]
+		for i := 0; i < len(cs); i++ {
+			for j := i + 1; j < len(cs); j++ {
+				if similar(cs[i], cs[j], opts) {
+					uf.union(cs[i].idx, cs[j].idx)
+					matches[cs[i].idx]++
+					matches[cs[j].idx]++
+				}
+			}
+		}
+	}
+}
+
+func similar(a, b candidate, opts Options) bool {
+	if !opts.AnyCity && a.city != b.city {
+		return false
+	}
+	if d := a.age - b.age; d > opts.AgeTolerance || -d > opts.AgeTolerance {
+		return false
+	}
+	return NameSimilarity(a.name, b.name, a.pinyin, b.pinyin) >= opts.NameThreshold
+}
+
+// NameSimilarity 计算两个（已规范化的）姓名的相似度，取值 0~1。
+// 结果为汉字序列与拼音串各自的 1-编辑距离/较长长度 中的较大者。
+func NameSimilarity(a, b []rune, pinyinA, pinyinB string) float64 {
+	return max(editSimilarity(a, b), editSimilarity([]rune(pinyinA), []rune(pinyinB)))
+}
+
+func editSimilarity(a, b []rune) float64 {
+	n := max(len(a), len(b))
+	if n == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(a, b))/float64(n)
+}
+
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func buildResult(uf *unionFind, matches []int) *Result {
+	groups := make(map[int][]int)
+	for i := range matches {
+		root := uf.find(i)
+		groups[root] = append(groups[root], i)
+	}
+
+	res := &Result{}
+	for i := range matches {
+		members := groups[uf.find(i)]
+		if members[0] != i {
+			continue // 每个簇只在其最小下标处处理一次
+		}
+		rep := members[0]
+		for _, m := range members[1:] {
+			if matches[m] > matches[rep] {
+				rep = m
+			}
+		}
+		if len(members) > 1 {
+			res.Clusters = append(res.Clusters, Cluster{Representative: rep, Members: members})
+		}
+		res.Keep = append(res.Keep, rep)
+	}
+	slices.Sort(res.Keep)
+	slices.SortFunc(res.Clusters, func(a, b Cluster) int { return a.Representative - b.Representative })
+	return res
+}
+
+// unionFind 是带路径压缩的并查集。
+type unionFind struct {
+	parent []int
+}
+
+func newUnionFind(n int) *unionFind {
+	p := make([]int, n)
+	for i := range p {
+		p[i] = i
+	}
+	return &unionFind{parent: p}
+}
+
+func (u *unionFind) find(x int) int {
+	for u.parent[x] != x {
+		u.parent[x] = u.parent[u.parent[x]]
+		x = u.parent[x]
+	}
+	return x
+}
+
+func (u *unionFind) union(a, b int) {
+	ra, rb := u.find(a), u.find(b)
+	if ra == rb {
+		return
+	}
+	// 让较小的下标做根，便于 buildResult 的遍历顺序稳定。
+	if ra < rb {
+		u.parent[rb] = ra
+	} else {
+		u.parent[ra] = rb
+	}
+}
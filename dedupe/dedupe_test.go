@@ -0,0 +1,82 @@
+package dedupe_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/xianyudd/hanzi-data-kit/dedupe"
+	"github.com/xianyudd/hanzi-data-kit/model"
+)
+
+func TestDedupe(t *testing.T) {
+	students := []model.Student{
+		{Name: "张三", Age: 22, City: "北京", Score: 95.0},  // 0
+		{Name: "張三", Age: 22, City: "北京", Score: 95.0},  // 1 繁体
+		{Name: "张 三", Age: 23, City: "北京", Score: 90.0}, // 2 空白 + 年龄差 1
+		{Name: "张珊", Age: 22, City: "北京", Score: 88.0},  // 3 近音
+		{Name: "张三", Age: 22, City: "上海", Score: 95.0},  // 4 不同城
+		{Name: "李四", Age: 25, City: "上海", Score: 80.0},  // 5
+		{Name: "张三", Age: 30, City: "北京", Score: 95.0},  // 6 年龄超出容差
+	}
+
+	tests := []struct {
+		name     string
+		opts     dedupe.Options
+		wantKeep []int
+		wantRep  []int
+	}{
+		{
+			name:     "exact_all_columns",
+			opts:     dedupe.Options{},
+			wantKeep: []int{0, 2, 3, 4, 5, 6},
+			wantRep:  []int{0},
+		},
+		{
+			name:     "exact_name_city",
+			opts:     dedupe.Options{Key: []string{"姓名", "城市"}},
+			wantKeep: []int{0, 3, 4, 5},
+			wantRep:  []int{0},
+		},
+		{
+			name:     "fuzzy_default",
+			opts:     dedupe.DefaultFuzzyOptions(),
+			wantKeep: []int{0, 4, 5, 6},
+			wantRep:  []int{0},
+		},
+		{
+			name:     "fuzzy_any_city",
+			opts:     dedupe.Options{Fuzzy: true, AnyCity: true},
+			wantKeep: []int{0, 2, 5, 6},
+			wantRep:  []int{0},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := dedupe.Dedupe(students, tt.opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(res.Keep, tt.wantKeep) {
+				t.Fatalf("keep mismatch: got %v, want %v (clusters %+v)", res.Keep, tt.wantKeep, res.Clusters)
+			}
+			var reps []int
+			for _, c := range res.Clusters {
+				reps = append(reps, c.Representative)
+			}
+			if !slices.Equal(reps, tt.wantRep) {
+				t.Fatalf("representatives mismatch: got %v, want %v", reps, tt.wantRep)
+			}
+			if got := res.Apply(students); len(got) != len(tt.wantKeep) {
+				t.Fatalf("apply: expected %d students, got %d", len(tt.wantKeep), len(got))
+			}
+		})
+	}
+}
+
+func TestDedupe_UnknownKey(t *testing.T) {
+	if _, err := dedupe.Dedupe([]model.Student{{Name: "张三"}}, dedupe.Options{Key: []string{"学号"}}); err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+}
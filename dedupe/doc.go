@@ -0,0 +1,3 @@
+// Package dedupe 对 []model.Student 做去重：精确键去重，以及基于规范化姓名、拼音相似度、
+// 编辑距离、同城与年龄容差的模糊匹配。匹配的记录通过并查集聚成簇，每簇选出一条代表记录。
+package dedupe
@@ -0,0 +1,339 @@
+package diff
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/xianyudd/hanzi-data-kit/model"
+	"github.com/xianyudd/hanzi-data-kit/parser"
+)
+
+// DefaultChunkRows 为外部排序时每个内存分段的默认行数。
+const DefaultChunkRows = 100_000
+
+// Options 控制比较行为。
+type Options struct {
+	// Key 为匹配记录所用的列（标准中文列名或附加列的原表头），如 {"姓名","城市"} 或 {"学号"}。
+	// 为空时使用 DefaultKey()。键列必须同时存在于两份数据中，且键值在各自数据中唯一。
+	Key []string
+
+	// ChunkRows 为外部排序每个分段的行数；<=0 时使用 DefaultChunkRows。
+	ChunkRows int
+
+	// TempDir 为外部排序临时文件所在目录；为空时使用系统临时目录。
+	TempDir string
+
+	// ScoreTolerance 大于 0 时，新旧得分之差的绝对值不超过它即视为未修改；
+	// 默认为 0，即得分按数值精确比较（88.5 与 88.50 相同，88.71 与 88.74 不同）。
+	ScoreTolerance float64
+}
+
+// DefaultKey 返回默认的匹配键：姓名+城市。
+func DefaultKey() []string {
+	return []string{"姓名", "城市"}
+}
+
+// ChangeKind 为记录变化的类别。
+type ChangeKind string
+
+const (
+	Added    ChangeKind = "added"
+	Removed  ChangeKind = "removed"
+	Modified ChangeKind = "modified"
+)
+
+// FieldChange 为一个字段的修改。
+type FieldChange struct {
+	Column string `json:"column"`
+	Old    string `json:"old"`
+	New    string `json:"new"`
+}
+
+// Change 为一条记录的变化。
+type Change struct {
+	Kind ChangeKind `json:"kind"`
+
+	// Key 为该记录的键值，顺序与 Options.Key 一致。
+	Key []string `json:"key"`
+
+	// OldLine/NewLine 为记录在旧/新数据中的行号；不存在时为 0。
+	OldLine int `json:"old_line,omitempty"`
+	NewLine int `json:"new_line,omitempty"`
+
+	// Record 为新增（取新数据）或删除（取旧数据）记录的全部字段；修改时为 nil。
+	Record map[string]string `json:"record,omitempty"`
+
+	// Fields 为修改的字段，按列顺序排列；仅 Modified 时非空。
+	Fields []FieldChange `json:"fields,omitempty"`
+}
+
+// Summary 为比较结果的汇总。
+type Summary struct {
+	Key       []string `json:"key"`
+	Columns   []string `json:"compared_columns"`
+	OnlyInOld []string `json:"columns_only_in_old,omitempty"`
+	OnlyInNew []string `json:"columns_only_in_new,omitempty"`
+	Added     int      `json:"added"`
+	Removed   int      `json:"removed"`
+	Modified  int      `json:"modified"`
+	Unchanged int      `json:"unchanged"`
+}
+
+// Tables 比较两张已解析的学生表；每发现一处变化就按键的升序调用一次 emit。
+// emit 返回的 error 会中止比较并原样返回。
+func Tables(old, new *parser.StudentTable, opts Options, emit func(Change) error) (*Summary, error) {
+	oldCols, newCols := tableColumns(old.ExtraHeaders), tableColumns(new.ExtraHeaders)
+	plan, err := newPlan(oldCols, newCols, opts)
+	if err != nil {
+		return nil, err
+	}
+	return plan.run(newSliceSource(old, plan.oldKey), newSliceSource(new, plan.newKey), emit)
+}
+
+// Input 描述一个待比较的文件及其解析选项（两份文件可以使用不同的方言与编码）。
+type Input struct {
+	Path    string
+	Options parser.CSVParseOptions
+}
+
+// Files 以外部排序的方式流式比较两份学生 CSV 文件，适用于无法整体载入内存的大文件。
+// 结果与先解析为 StudentTable 再调用 Tables 相同。
+func Files(old, new Input, opts Options, emit func(Change) error) (*Summary, error) {
+	oldSc, err := parser.NewStudentScanner(old.Path, old.Options)
+	if err != nil {
+		return nil, fmt.Errorf("打开旧文件失败: %w", err)
+	}
+	defer oldSc.Close()
+	newSc, err := parser.NewStudentScanner(new.Path, new.Options)
+	if err != nil {
+		return nil, fmt.Errorf("打开新文件失败: %w", err)
+	}
+	defer newSc.Close()
+
+	plan, err := newPlan(tableColumns(oldSc.ExtraHeaders()), tableColumns(newSc.ExtraHeaders()), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	chunk := opts.ChunkRows
+	if chunk <= 0 {
+		chunk = DefaultChunkRows
+	}
+	oldSrc, err := externalSort(oldSc, plan.oldKey, chunk, opts.TempDir)
+	if err != nil {
+		return nil, fmt.Errorf("排序旧文件失败: %w", err)
+	}
+	defer oldSrc.Close()
+	newSrc, err := externalSort(newSc, plan.newKey, chunk, opts.TempDir)
+	if err != nil {
+		return nil, fmt.Errorf("排序新文件失败: %w", err)
+	}
+	defer newSrc.Close()
+
+	return plan.run(oldSrc, newSrc, emit)
+}
+
+// tableColumns 返回一张表的全部列：标准中文列在前，附加列按原顺序在后。
+func tableColumns(extra []string) []string {
+	return append(model.StudentHeadersCN(), extra...)
+}
+
+// recordValues 将记录展开为与 tableColumns 对齐的一行。
+// 得分不能沿用 StudentToRowCN 的一位小数，否则低于 0.1 的修改会被掩盖，见 formatScore。
+func recordValues(rec parser.StudentRecord, extra []string) []string {
+	row := model.StudentToRowCN(rec.Student)
+	row[scoreColumn] = formatScore(rec.Score)
+	for _, h := range extra {
+		row = append(row, rec.Extra[h])
+	}
+	return row
+}
+
+// scoreColumn 为得分在 model.StudentHeadersCN() 中的下标。
+var scoreColumn = slices.Index(model.StudentHeadersCN(), "得分")
+
+// formatScore 将得分格式化为能精确还原该数值的文本：一位小数足够时与 StudentToRowCN 相同（如 88.5），
+// 否则使用最短的精确表示（如 88.71）。因此文本相同当且仅当数值相同。
+func formatScore(v float64) string {
+	s := strconv.FormatFloat(v, 'f', 1, 64)
+	if f, _ := strconv.ParseFloat(s, 64); f == v {
+		return s
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// plan 记录两份数据的列布局以及键列/比较列在各自行中的下标。
+type plan struct {
+	oldCols, newCols []string
+	oldKey, newKey   []int
+	// compare 为需要比较的公共列：[i][0] 为旧行下标，[i][1] 为新行下标。
+	compare [][2]int
+	// scoreTolerance 见 Options.ScoreTolerance；scoreCompare 为得分在 compare 中的下标，不比较得分时为 -1。
+	scoreTolerance float64
+	scoreCompare   int
+	summary        Summary
+}
+
+func newPlan(oldCols, newCols []string, opts Options) (*plan, error) {
+	key := opts.Key
+	if len(key) == 0 {
+		key = DefaultKey()
+	}
+	if opts.ScoreTolerance < 0 {
+		return nil, fmt.Errorf("得分容差不能为负数: %v", opts.ScoreTolerance)
+	}
+	p := &plan{oldCols: oldCols, newCols: newCols, scoreTolerance: opts.ScoreTolerance}
+	p.summary.Key = key
+
+	for _, k := range key {
+		oi, ni := slices.Index(oldCols, k), slices.Index(newCols, k)
+		switch {
+		case oi < 0:
+			return nil, fmt.Errorf("键列 %s 不存在于旧数据中", k)
+		case ni < 0:
+			return nil, fmt.Errorf("键列 %s 不存在于新数据中", k)
+		}
+		p.oldKey = append(p.oldKey, oi)
+		p.newKey = append(p.newKey, ni)
+	}
+
+	for oi, c := range oldCols {
+		ni := slices.Index(newCols, c)
+		if ni < 0 {
+			p.summary.OnlyInOld = append(p.summary.OnlyInOld, c)
+			continue
+		}
+		if !slices.Contains(key, c) {
+			p.compare = append(p.compare, [2]int{oi, ni})
+			p.summary.Columns = append(p.summary.Columns, c)
+		}
+	}
+	p.scoreCompare = slices.Index(p.summary.Columns, "得分")
+	for _, c := range newCols {
+		if !slices.Contains(oldCols, c) {
+			p.summary.OnlyInNew = append(p.summary.OnlyInNew, c)
+		}
+	}
+	return p, nil
+}
+
+// run 对两个已按键排序的数据源做归并连接。
+func (p *plan) run(oldSrc, newSrc source, emit func(Change) error) (*Summary, error) {
+	s := p.summary
+	ou := &uniqueSource{src: oldSrc, label: "旧数据"}
+	nu := &uniqueSource{src: newSrc, label: "新数据"}
+	o, err := ou.next()
+	if err != nil {
+		return nil, err
+	}
+	n, err := nu.next()
+	if err != nil {
+		return nil, err
+	}
+
+	for o != nil || n != nil {
+		var ch *Change
+		switch {
+		case n == nil || (o != nil && o.key < n.key):
+			s.Removed++
+			ch = &Change{Kind: Removed, Key: o.keyValues(p.oldKey), OldLine: o.line, Record: zip(p.oldCols, o.values)}
+			o, err = ou.next()
+		case o == nil || n.key < o.key:
+			s.Added++
+			ch = &Change{Kind: Added, Key: n.keyValues(p.newKey), NewLine: n.line, Record: zip(p.newCols, n.values)}
+			n, err = nu.next()
+		default:
+			var fields []FieldChange
+			for i, c := range p.compare {
+				if o.values[c[0]] != n.values[c[1]] && !p.withinTolerance(i, o.values[c[0]], n.values[c[1]]) {
+					fields = append(fields, FieldChange{Column: s.Columns[i], Old: o.values[c[0]], New: n.values[c[1]]})
+				}
+			}
+			if len(fields) == 0 {
+				s.Unchanged++
+			} else {
+				s.Modified++
+				ch = &Change{Kind: Modified, Key: n.keyValues(p.newKey), OldLine: o.line, NewLine: n.line, Fields: fields}
+			}
+			if o, err = ou.next(); err == nil {
+				n, err = nu.next()
+			}
+		}
+		if ch != nil {
+			if err := emit(*ch); err != nil {
+				return nil, err
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &s, nil
+}
+
+// withinTolerance 报告第 i 个比较列是否为得分，且两值之差不超过 scoreTolerance。
+func (p *plan) withinTolerance(i int, old, new string) bool {
+	if p.scoreTolerance <= 0 || i != p.scoreCompare {
+		return false
+	}
+	a, err1 := strconv.ParseFloat(old, 64)
+	b, err2 := strconv.ParseFloat(new, 64)
+	return err1 == nil && err2 == nil && math.Abs(a-b) <= p.scoreTolerance
+}
+
+func zip(cols, values []string) map[string]string {
+	m := make(map[string]string, len(cols))
+	for i, c := range cols {
+		m[c] = values[i]
+	}
+	return m
+}
+
+// uniqueSource 包装一个有序数据源，并校验键值唯一（有序流中重复键必然相邻）。
+type uniqueSource struct {
+	src   source
+	label string
+	last  *keyedRow
+}
+
+func (u *uniqueSource) next() (*keyedRow, error) {
+	r, err := u.src.next()
+	if err != nil || r == nil {
+		return nil, err
+	}
+	if u.last != nil && u.last.key == r.key {
+		return nil, fmt.Errorf("键 %q 在%s中不唯一(第%d行与第%d行)，请换用能唯一标识记录的键",
+			strings.ReplaceAll(r.key, keySep, "|"), u.label, u.last.line, r.line)
+	}
+	u.last = r
+	return r, nil
+}
+
+// keyedRow 是一条带排序键的行。
+type keyedRow struct {
+	key    string
+	line   int
+	values []string
+}
+
+// keySep 用于拼接多列键值；它是 ASCII 单元分隔符，不会出现在正常文本中。
+const keySep = "\x1f"
+
+func makeKey(values []string, keyIdx []int) string {
+	parts := make([]string, len(keyIdx))
+	for i, k := range keyIdx {
+		parts[i] = values[k]
+	}
+	return strings.Join(parts, keySep)
+}
+
+func (r *keyedRow) keyValues(keyIdx []int) []string {
+	out := make([]string, len(keyIdx))
+	for i, k := range keyIdx {
+		out[i] = r.values[k]
+	}
+	return out
+}
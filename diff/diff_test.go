@@ -0,0 +1,150 @@
+package diff_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/xianyudd/hanzi-data-kit/diff"
+	"github.com/xianyudd/hanzi-data-kit/parser"
+)
+
+func writeTempFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write temp file failed: %v", err)
+	}
+	return path
+}
+
+func collect(changes *[]diff.Change) func(diff.Change) error {
+	return func(c diff.Change) error {
+		*changes = append(*changes, c)
+		return nil
+	}
+}
+
+func TestFiles_MatchesTablesWithExternalSort(t *testing.T) {
+	oldPath := writeTempFile(t, "old.csv",
+		"学号,姓名,年龄,城市,得分\n"+
+			"S3,王五,28,广州,70\n"+
+			"S1,张三,22,北京,95.0\n"+
+			"S2,李四,25,上海,88.5\n")
+	// 列顺序不同、分隔符不同、多一个附加列。
+	newPath := writeTempFile(t, "new.csv",
+		"得分;城市;姓名;年龄;学号;备注\n"+
+			"60;深圳;赵六;20;S4;新生\n"+
+			"95;北京;张三;22;S1;\n"+
+			"90.0;上海;李四;26;S2;\n")
+	oldOpts := parser.CSVParseOptions{TrimSpace: true}
+	newOpts := parser.CSVParseOptions{TrimSpace: true, Dialect: parser.Dialect{Delimiter: ';'}}
+	opts := diff.Options{Key: []string{"学号"}, ChunkRows: 1}
+
+	var fromFiles []diff.Change
+	sumFiles, err := diff.Files(diff.Input{Path: oldPath, Options: oldOpts}, diff.Input{Path: newPath, Options: newOpts}, opts, collect(&fromFiles))
+	if err != nil {
+		t.Fatalf("Files: unexpected error: %v", err)
+	}
+
+	oldTable, err := parser.ParseCSVToStudentTable(oldPath, oldOpts)
+	if err != nil {
+		t.Fatalf("parse old: %v", err)
+	}
+	newTable, err := parser.ParseCSVToStudentTable(newPath, newOpts)
+	if err != nil {
+		t.Fatalf("parse new: %v", err)
+	}
+	var fromTables []diff.Change
+	sumTables, err := diff.Tables(oldTable, newTable, opts, collect(&fromTables))
+	if err != nil {
+		t.Fatalf("Tables: unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(fromFiles, fromTables) || !reflect.DeepEqual(sumFiles, sumTables) {
+		t.Fatalf("Files and Tables disagree:\n  files:  %+v %+v\n  tables: %+v %+v", fromFiles, sumFiles, fromTables, sumTables)
+	}
+
+	want := []diff.Change{
+		{Kind: diff.Modified, Key: []string{"S2"}, OldLine: 4, NewLine: 4, Fields: []diff.FieldChange{
+			{Column: "年龄", Old: "25", New: "26"},
+			{Column: "得分", Old: "88.5", New: "90.0"},
+		}},
+		{Kind: diff.Removed, Key: []string{"S3"}, OldLine: 2, Record: map[string]string{
+			"学号": "S3", "姓名": "王五", "年龄": "28", "城市": "广州", "得分": "70.0",
+		}},
+		{Kind: diff.Added, Key: []string{"S4"}, NewLine: 2, Record: map[string]string{
+			"学号": "S4", "姓名": "赵六", "年龄": "20", "城市": "深圳", "得分": "60.0", "备注": "新生",
+		}},
+	}
+	if !reflect.DeepEqual(fromFiles, want) {
+		t.Fatalf("changes mismatch:\n  got:  %+v\n  want: %+v", fromFiles, want)
+	}
+	if sumFiles.Added != 1 || sumFiles.Removed != 1 || sumFiles.Modified != 1 || sumFiles.Unchanged != 1 {
+		t.Fatalf("summary mismatch: %+v", sumFiles)
+	}
+	if !reflect.DeepEqual(sumFiles.OnlyInNew, []string{"备注"}) {
+		t.Fatalf("columns only in new: %v", sumFiles.OnlyInNew)
+	}
+}
+
+func TestFiles_DuplicateKeyReturnsError(t *testing.T) {
+	oldPath := writeTempFile(t, "old.csv", "姓名,年龄,城市,得分\n张三,22,北京,95\n张三,23,北京,80\n")
+	newPath := writeTempFile(t, "new.csv", "姓名,年龄,城市,得分\n张三,22,北京,95\n")
+	in := parser.CSVParseOptions{TrimSpace: true}
+
+	_, err := diff.Files(diff.Input{Path: oldPath, Options: in}, diff.Input{Path: newPath, Options: in}, diff.Options{}, func(diff.Change) error { return nil })
+	if err == nil || !strings.Contains(err.Error(), "不唯一") {
+		t.Fatalf("expected duplicate key error, got %v", err)
+	}
+}
+
+func TestNewReportWriter_CSV(t *testing.T) {
+	var b strings.Builder
+	w, err := diff.NewReportWriter(&b, "csv")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	w.Write(diff.Change{Kind: diff.Modified, Key: []string{"张三", "北京"}, Fields: []diff.FieldChange{{Column: "得分", Old: "1.0", New: "2.0"}}})
+	if err := w.Close(&diff.Summary{}); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+	want := "change,key,column,old,new\nmodified,张三|北京,得分,1.0,2.0\n"
+	if b.String() != want {
+		t.Fatalf("csv report mismatch:\n  got:  %q\n  want: %q", b.String(), want)
+	}
+}
+
+func TestTables_ScoreBelowDisplayPrecision(t *testing.T) {
+	oldPath := writeTempFile(t, "old.csv", "学号,姓名,年龄,城市,得分\nS1,张三,22,北京,88.71\nS2,李四,25,上海,88.5\nS3,王五,28,广州,70\n")
+	newPath := writeTempFile(t, "new.csv", "学号,姓名,年龄,城市,得分\nS1,张三,22,北京,88.74\nS2,李四,25,上海,88.50\nS3,王五,28,广州,70.0\n")
+	tests := []struct {
+		name      string
+		tolerance float64
+		modified  int
+	}{
+		{"exact", 0, 1},
+		{"tolerance", 0.05, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := diff.Options{Key: []string{"学号"}, ChunkRows: 1, ScoreTolerance: tt.tolerance}
+			var changes []diff.Change
+			sum, err := diff.Files(diff.Input{Path: oldPath}, diff.Input{Path: newPath}, opts, collect(&changes))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sum.Modified != tt.modified || sum.Unchanged != 3-tt.modified {
+				t.Fatalf("summary = %+v", sum)
+			}
+			if tt.modified == 1 {
+				want := []diff.FieldChange{{Column: "得分", Old: "88.71", New: "88.74"}}
+				if !reflect.DeepEqual(changes[0].Fields, want) {
+					t.Fatalf("fields = %+v, want %+v", changes[0].Fields, want)
+				}
+			}
+		})
+	}
+}
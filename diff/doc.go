@@ -0,0 +1,6 @@
+// Package diff 比较两份学生数据集，按可配置的键匹配记录，报告新增、删除与字段级修改。
+//
+// 记录先按键排序再做归并连接：内存中的 StudentTable 直接排序；文件输入则使用外部排序
+// （按 Options.ChunkRows 分段排序写入临时文件后多路归并），因此可以比较远大于内存的文件。
+// 两份文件的列顺序、附加列与字符编码均可以不同。
+package diff
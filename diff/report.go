@@ -0,0 +1,163 @@
+package diff
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// ReportWriter 以流式方式输出比较结果：每条变化调用一次 Write，结束时调用一次 Close 写出汇总。
+type ReportWriter interface {
+	Write(Change) error
+	Close(*Summary) error
+}
+
+// NewReportWriter 按格式名（text|json|csv）创建 ReportWriter。
+func NewReportWriter(w io.Writer, format string) (ReportWriter, error) {
+	switch format {
+	case "text":
+		return &textWriter{w: w}, nil
+	case "json":
+		return &jsonWriter{w: w}, nil
+	case "csv":
+		return &csvWriter{w: csv.NewWriter(w)}, nil
+	}
+	return nil, fmt.Errorf("不支持的输出格式: %q（可选: text|json|csv）", format)
+}
+
+// textWriter 输出便于人工阅读的报告：+ 新增，- 删除，~ 修改。
+type textWriter struct {
+	w io.Writer
+}
+
+func (t *textWriter) Write(c Change) error {
+	key := strings.Join(c.Key, "|")
+	var err error
+	switch c.Kind {
+	case Added:
+		_, err = fmt.Fprintf(t.w, "+ %s (新文件第%d行) %s\n", key, c.NewLine, formatRecord(c.Record))
+	case Removed:
+		_, err = fmt.Fprintf(t.w, "- %s (旧文件第%d行) %s\n", key, c.OldLine, formatRecord(c.Record))
+	case Modified:
+		parts := make([]string, len(c.Fields))
+		for i, f := range c.Fields {
+			parts[i] = fmt.Sprintf("%s: %q → %q", f.Column, f.Old, f.New)
+		}
+		_, err = fmt.Fprintf(t.w, "~ %s (第%d行 → 第%d行) %s\n", key, c.OldLine, c.NewLine, strings.Join(parts, "; "))
+	}
+	return err
+}
+
+func (t *textWriter) Close(s *Summary) error {
+	fmt.Fprintf(t.w, "\n键: %s  比较列: %s\n", strings.Join(s.Key, "+"), strings.Join(s.Columns, ","))
+	if len(s.OnlyInOld) > 0 {
+		fmt.Fprintf(t.w, "仅旧文件有的列: %s\n", strings.Join(s.OnlyInOld, ","))
+	}
+	if len(s.OnlyInNew) > 0 {
+		fmt.Fprintf(t.w, "仅新文件有的列: %s\n", strings.Join(s.OnlyInNew, ","))
+	}
+	_, err := fmt.Fprintf(t.w, "新增 %d, 删除 %d, 修改 %d, 未变 %d\n", s.Added, s.Removed, s.Modified, s.Unchanged)
+	return err
+}
+
+// formatRecord 以 列=值 的形式输出记录，按列名排序保证输出稳定。
+func formatRecord(rec map[string]string) string {
+	cols := make([]string, 0, len(rec))
+	for c := range rec {
+		cols = append(cols, c)
+	}
+	sort.Strings(cols)
+	parts := make([]string, len(cols))
+	for i, c := range cols {
+		parts[i] = c + "=" + rec[c]
+	}
+	return strings.Join(parts, " ")
+}
+
+// jsonWriter 输出 {"changes":[...],"summary":{...}}；变化逐条写出，不在内存中累积。
+type jsonWriter struct {
+	w     io.Writer
+	count int
+}
+
+func (j *jsonWriter) Write(c Change) error {
+	prefix := ",\n    "
+	if j.count == 0 {
+		prefix = "{\n  \"changes\": [\n    "
+	}
+	j.count++
+	b, err := marshal(c)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(j.w, "%s%s", prefix, b)
+	return err
+}
+
+func (j *jsonWriter) Close(s *Summary) error {
+	if j.count == 0 {
+		if _, err := io.WriteString(j.w, "{\n  \"changes\": ["); err != nil {
+			return err
+		}
+	} else if _, err := io.WriteString(j.w, "\n  "); err != nil {
+		return err
+	}
+	b, err := marshal(s)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(j.w, "],\n  \"summary\": %s\n}\n", b)
+	return err
+}
+
+func marshal(v any) ([]byte, error) {
+	var b strings.Builder
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return []byte(strings.TrimSuffix(b.String(), "\n")), nil
+}
+
+// csvWriter 每个字段变化输出一行: change,key,column,old,new；新增/删除的记录各输出一行（column 为空）。
+type csvWriter struct {
+	w      *csv.Writer
+	header bool
+}
+
+func (c *csvWriter) Write(ch Change) error {
+	if !c.header {
+		c.header = true
+		if err := c.w.Write([]string{"change", "key", "column", "old", "new"}); err != nil {
+			return err
+		}
+	}
+	key := strings.Join(ch.Key, "|")
+	switch ch.Kind {
+	case Added:
+		return c.w.Write([]string{string(ch.Kind), key, "", "", formatRecord(ch.Record)})
+	case Removed:
+		return c.w.Write([]string{string(ch.Kind), key, "", formatRecord(ch.Record), ""})
+	}
+	for _, f := range ch.Fields {
+		if err := c.w.Write([]string{string(ch.Kind), key, f.Column, f.Old, f.New}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *csvWriter) Close(*Summary) error {
+	if !c.header {
+		c.header = true
+		if err := c.w.Write([]string{"change", "key", "column", "old", "new"}); err != nil {
+			return err
+		}
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
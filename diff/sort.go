@@ -0,0 +1,205 @@
+package diff
+
+import (
+	"bufio"
+	"cmp"
+	"container/heap"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+
+	"github.com/xianyudd/hanzi-data-kit/parser"
+)
+
+// source 是按键升序产出行的数据源；结束时返回 (nil, nil)。
+type source interface {
+	next() (*keyedRow, error)
+}
+
+// compareRows 先按键、再按行号排序，保证重复键的报错行号稳定。
+func compareRows(a, b *keyedRow) int {
+	if c := cmp.Compare(a.key, b.key); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.line, b.line)
+}
+
+// sliceSource 是内存中已排序的数据源。
+type sliceSource struct {
+	rows []*keyedRow
+	i    int
+}
+
+func newSliceSource(t *parser.StudentTable, keyIdx []int) *sliceSource {
+	rows := make([]*keyedRow, len(t.Records))
+	for i, rec := range t.Records {
+		values := recordValues(rec, t.ExtraHeaders)
+		rows[i] = &keyedRow{key: makeKey(values, keyIdx), line: rec.Line, values: values}
+	}
+	slices.SortFunc(rows, compareRows)
+	return &sliceSource{rows: rows}
+}
+
+func (s *sliceSource) next() (*keyedRow, error) {
+	if s.i >= len(s.rows) {
+		return nil, nil
+	}
+	s.i++
+	return s.rows[s.i-1], nil
+}
+
+// sortedSource 是外部排序的结果：若数据能装入一个分段则直接在内存中排序，否则多路归并临时文件。
+type sortedSource struct {
+	mem   *sliceSource
+	heap  runHeap
+	files []*os.File
+}
+
+// externalSort 从 sc 读取全部记录，按 chunk 行分段排序；超过一个分段时写入 tempDir 下的临时文件。
+func externalSort(sc *parser.StudentScanner, keyIdx []int, chunk int, tempDir string) (*sortedSource, error) {
+	out := &sortedSource{}
+	extra := sc.ExtraHeaders()
+	buf := make([]*keyedRow, 0, min(chunk, 1024))
+
+	for {
+		rec, err := sc.Next()
+		eof := errors.Is(err, io.EOF)
+		if err != nil && !eof {
+			out.Close()
+			return nil, err
+		}
+		if !eof {
+			values := recordValues(rec, extra)
+			buf = append(buf, &keyedRow{key: makeKey(values, keyIdx), line: rec.Line, values: values})
+		}
+		if len(buf) < chunk && !eof {
+			continue
+		}
+
+		slices.SortFunc(buf, compareRows)
+		if eof && len(out.files) == 0 {
+			out.mem = &sliceSource{rows: buf}
+			return out, nil
+		}
+		if len(buf) > 0 {
+			if err := out.spill(buf, tempDir); err != nil {
+				out.Close()
+				return nil, err
+			}
+			buf = buf[:0]
+		}
+		if eof {
+			break
+		}
+	}
+
+	for _, f := range out.files {
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			out.Close()
+			return nil, err
+		}
+		r := &runReader{r: csv.NewReader(bufio.NewReader(f))}
+		r.r.FieldsPerRecord = -1
+		if err := r.advance(); err != nil {
+			out.Close()
+			return nil, err
+		}
+		if r.cur != nil {
+			out.heap = append(out.heap, r)
+		}
+	}
+	heap.Init(&out.heap)
+	return out, nil
+}
+
+// spill 将一个已排序分段写入临时文件；每行格式为: key, line, values...
+func (s *sortedSource) spill(rows []*keyedRow, tempDir string) error {
+	f, err := os.CreateTemp(tempDir, "hanzi-diff-*.csv")
+	if err != nil {
+		return fmt.Errorf("创建临时文件失败: %w", err)
+	}
+	s.files = append(s.files, f)
+
+	w := csv.NewWriter(f)
+	for _, r := range rows {
+		if err := w.Write(append([]string{r.key, strconv.Itoa(r.line)}, r.values...)); err != nil {
+			return fmt.Errorf("写入临时文件失败: %w", err)
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("写入临时文件失败: %w", err)
+	}
+	return nil
+}
+
+func (s *sortedSource) next() (*keyedRow, error) {
+	if s.mem != nil {
+		return s.mem.next()
+	}
+	if len(s.heap) == 0 {
+		return nil, nil
+	}
+	top := s.heap[0]
+	row := top.cur
+	if err := top.advance(); err != nil {
+		return nil, err
+	}
+	if top.cur == nil {
+		heap.Pop(&s.heap)
+	} else {
+		heap.Fix(&s.heap, 0)
+	}
+	return row, nil
+}
+
+// Close 删除全部临时文件。
+func (s *sortedSource) Close() error {
+	var errs []error
+	for _, f := range s.files {
+		errs = append(errs, f.Close(), os.Remove(f.Name()))
+	}
+	s.files = nil
+	return errors.Join(errs...)
+}
+
+// runReader 顺序读取一个临时分段文件。
+type runReader struct {
+	r   *csv.Reader
+	cur *keyedRow
+}
+
+func (r *runReader) advance() error {
+	rec, err := r.r.Read()
+	if errors.Is(err, io.EOF) {
+		r.cur = nil
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("读取临时文件失败: %w", err)
+	}
+	line, err := strconv.Atoi(rec[1])
+	if err != nil {
+		return fmt.Errorf("临时文件已损坏: %w", err)
+	}
+	r.cur = &keyedRow{key: rec[0], line: line, values: rec[2:]}
+	return nil
+}
+
+// runHeap 是以各分段当前行为键的最小堆。
+type runHeap []*runReader
+
+func (h runHeap) Len() int           { return len(h) }
+func (h runHeap) Less(i, j int) bool { return compareRows(h[i].cur, h[j].cur) < 0 }
+func (h runHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *runHeap) Push(x any)        { *h = append(*h, x.(*runReader)) }
+func (h *runHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
@@ -0,0 +1,19 @@
+// Package expr 实现一个用于筛选/变换学生记录的小型表达式语言。
+//
+// 语法示例：
+//
+//	得分 >= 90 && 城市 in ["北京", "上海"]
+//	startsWith(姓名, "张") or len(姓名) == 3
+//	initials(姓名) == "zs"
+//	得分 + 5                      // 用于 -set 得分 = 得分 + 5
+//
+// 支持的元素：
+//   - 字面量：数字、"字符串" 或 '字符串'、true/false、列表 [a, b, ...]
+//   - 列名：标准列（姓名/年龄/城市/得分，也可写英文别名 name/age/city/score）或附加列；
+//     含空白等特殊字符的列名用反引号括起，如 `学生 编号`
+//   - 运算符（优先级由低到高）：|| or；&& and；! not；== != < <= > >= in、not in；+ -；* / %；一元 -
+//   - 关键字 and or not in true false 不区分大小写（AND、True 亦可）；同名列须用反引号括起
+//   - 函数：len startsWith endsWith contains lower upper trim pinyin initials num str round abs if
+//
+// 比较时数字与可解析为数字的字符串按数值比较；+ 作用于两个字符串时为拼接。
+package expr
@@ -0,0 +1,294 @@
+package expr
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/xianyudd/hanzi-data-kit/hanzi"
+)
+
+// Env 为求值环境：按列名返回当前记录的取值。
+type Env interface {
+	Lookup(name string) (Value, bool)
+}
+
+// MapEnv 是以 map 实现的 Env，便于测试或对任意键值数据求值。
+type MapEnv map[string]Value
+
+// Lookup 实现 Env。
+func (m MapEnv) Lookup(name string) (Value, bool) {
+	v, ok := m[name]
+	return v, ok
+}
+
+// Eval 在 env 上对表达式求值。
+func (e *Expr) Eval(env Env) (Value, error) {
+	v, err := eval(e.root, env)
+	if err != nil {
+		return Value{}, fmt.Errorf("求值 %q 失败: %w", e.src, err)
+	}
+	return v, nil
+}
+
+// EvalBool 求值并要求结果为布尔值，用于筛选条件。
+func (e *Expr) EvalBool(env Env) (bool, error) {
+	v, err := e.Eval(env)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.Bool()
+	if !ok {
+		return false, fmt.Errorf("表达式 %q 的结果不是布尔值: %s %q", e.src, v.Kind(), v.String())
+	}
+	return b, nil
+}
+
+func eval(n node, env Env) (Value, error) {
+	switch n := n.(type) {
+	case literalNode:
+		return n.v, nil
+	case identNode:
+		v, ok := env.Lookup(n.name)
+		if !ok {
+			return Value{}, fmt.Errorf("未知的列: %s", n.name)
+		}
+		return v, nil
+	case listNode:
+		vs := make([]Value, len(n.elems))
+		for i, e := range n.elems {
+			v, err := eval(e, env)
+			if err != nil {
+				return Value{}, err
+			}
+			vs[i] = v
+		}
+		return ListValue(vs...), nil
+	case unaryNode:
+		x, err := eval(n.x, env)
+		if err != nil {
+			return Value{}, err
+		}
+		if n.op == "!" {
+			b, ok := x.Bool()
+			if !ok {
+				return Value{}, fmt.Errorf("! 的操作数应为布尔值，实际为%s %q", x.Kind(), x.String())
+			}
+			return BoolValue(!b), nil
+		}
+		f, ok := x.Number()
+		if !ok {
+			return Value{}, fmt.Errorf("- 的操作数应为数字，实际为%s %q", x.Kind(), x.String())
+		}
+		return NumberValue(-f), nil
+	case binaryNode:
+		return evalBinary(n, env)
+	case callNode:
+		args := make([]Value, len(n.args))
+		for i, a := range n.args {
+			v, err := eval(a, env)
+			if err != nil {
+				return Value{}, err
+			}
+			args[i] = v
+		}
+		v, err := n.fn.call(args)
+		if err != nil {
+			return Value{}, fmt.Errorf("%s: %w", n.name, err)
+		}
+		return v, nil
+	}
+	panic(fmt.Sprintf("expr: 未知节点 %T", n))
+}
+
+func evalBinary(n binaryNode, env Env) (Value, error) {
+	l, err := eval(n.l, env)
+	if err != nil {
+		return Value{}, err
+	}
+
+	// 逻辑运算短路求值。
+	if n.op == "&&" || n.op == "||" {
+		lb, ok := l.Bool()
+		if !ok {
+			return Value{}, fmt.Errorf("%s 的操作数应为布尔值，实际为%s %q", n.op, l.Kind(), l.String())
+		}
+		if n.op == "&&" && !lb || n.op == "||" && lb {
+			return BoolValue(lb), nil
+		}
+		r, err := eval(n.r, env)
+		if err != nil {
+			return Value{}, err
+		}
+		rb, ok := r.Bool()
+		if !ok {
+			return Value{}, fmt.Errorf("%s 的操作数应为布尔值，实际为%s %q", n.op, r.Kind(), r.String())
+		}
+		return BoolValue(rb), nil
+	}
+
+	r, err := eval(n.r, env)
+	if err != nil {
+		return Value{}, err
+	}
+	switch n.op {
+	case "==", "!=":
+		eq, err := equal(l, r)
+		if err != nil {
+			return Value{}, err
+		}
+		return BoolValue(eq == (n.op == "==")), nil
+	case "<", "<=", ">", ">=":
+		c, err := compare(l, r, false)
+		if err != nil {
+			return Value{}, err
+		}
+		switch n.op {
+		case "<":
+			return BoolValue(c < 0), nil
+		case "<=":
+			return BoolValue(c <= 0), nil
+		case ">":
+			return BoolValue(c > 0), nil
+		}
+		return BoolValue(c >= 0), nil
+	case "in", "notin":
+		if r.Kind() != List {
+			return Value{}, fmt.Errorf("in 的右侧应为列表，如 [\"北京\", \"上海\"]，实际为%s", r.Kind())
+		}
+		found := false
+		for _, e := range r.List() {
+			eq, err := equal(l, e)
+			if err != nil {
+				return Value{}, err
+			}
+			if eq {
+				found = true
+				break
+			}
+		}
+		return BoolValue(found == (n.op == "in")), nil
+	case "+":
+		if l.Kind() == String && r.Kind() == String {
+			return StringValue(l.str + r.str), nil
+		}
+	}
+
+	x, okL := l.Number()
+	y, okR := r.Number()
+	if !okL || !okR {
+		return Value{}, fmt.Errorf("%s 的操作数应为数字: %q %s %q", n.op, l.String(), n.op, r.String())
+	}
+	switch n.op {
+	case "+":
+		return NumberValue(x + y), nil
+	case "-":
+		return NumberValue(x - y), nil
+	case "*":
+		return NumberValue(x * y), nil
+	case "/":
+		if y == 0 {
+			return Value{}, fmt.Errorf("除数为 0")
+		}
+		return NumberValue(x / y), nil
+	case "%":
+		if y == 0 {
+			return Value{}, fmt.Errorf("除数为 0")
+		}
+		return NumberValue(math.Mod(x, y)), nil
+	}
+	panic("expr: 未知运算符 " + n.op)
+}
+
+// function 为内置函数。
+type function struct {
+	minArgs, maxArgs int
+	call             func(args []Value) (Value, error)
+}
+
+var functions map[string]*function
+
+func init() {
+	functions = map[string]*function{
+		"len": {1, 1, func(a []Value) (Value, error) {
+			if a[0].Kind() == List {
+				return NumberValue(float64(len(a[0].List()))), nil
+			}
+			return NumberValue(float64(utf8.RuneCountInString(a[0].String()))), nil
+		}},
+		"startsWith": {2, 2, stringPredicate(strings.HasPrefix)},
+		"endsWith":   {2, 2, stringPredicate(strings.HasSuffix)},
+		"contains":   {2, 2, stringPredicate(strings.Contains)},
+		"lower":      {1, 1, stringFunc(strings.ToLower)},
+		"upper":      {1, 1, stringFunc(strings.ToUpper)},
+		"trim":       {1, 1, stringFunc(strings.TrimSpace)},
+		"pinyin": {1, 1, stringFunc(func(s string) string {
+			return strings.Join(hanzi.Pinyin(s), " ")
+		})},
+		"initials": {1, 1, stringFunc(func(s string) string {
+			return hanzi.Initials(hanzi.Pinyin(s))
+		})},
+		"str": {1, 1, stringFunc(func(s string) string { return s })},
+		"num": {1, 1, func(a []Value) (Value, error) {
+			n, ok := a[0].Number()
+			if !ok {
+				return Value{}, fmt.Errorf("无法将 %q 转为数字", a[0].String())
+			}
+			return NumberValue(n), nil
+		}},
+		"abs": {1, 1, numberFunc(math.Abs)},
+		"round": {1, 2, func(a []Value) (Value, error) {
+			x, ok := a[0].Number()
+			if !ok {
+				return Value{}, fmt.Errorf("参数应为数字，实际为 %q", a[0].String())
+			}
+			digits := 0.0
+			if len(a) == 2 {
+				if digits, ok = a[1].Number(); !ok {
+					return Value{}, fmt.Errorf("小数位数应为数字，实际为 %q", a[1].String())
+				}
+			}
+			p := math.Pow(10, math.Trunc(digits))
+			return NumberValue(math.Round(x*p) / p), nil
+		}},
+		"if": {3, 3, func(a []Value) (Value, error) {
+			b, ok := a[0].Bool()
+			if !ok {
+				return Value{}, fmt.Errorf("条件应为布尔值，实际为 %q", a[0].String())
+			}
+			if b {
+				return a[1], nil
+			}
+			return a[2], nil
+		}},
+	}
+}
+
+func functionNames() string {
+	names := make([]string, 0, len(functions))
+	for name := range functions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, " ")
+}
+
+func stringFunc(f func(string) string) func([]Value) (Value, error) {
+	return func(a []Value) (Value, error) { return StringValue(f(a[0].String())), nil }
+}
+
+func stringPredicate(f func(s, sub string) bool) func([]Value) (Value, error) {
+	return func(a []Value) (Value, error) { return BoolValue(f(a[0].String(), a[1].String())), nil }
+}
+
+func numberFunc(f func(float64) float64) func([]Value) (Value, error) {
+	return func(a []Value) (Value, error) {
+		x, ok := a[0].Number()
+		if !ok {
+			return Value{}, fmt.Errorf("参数应为数字，实际为 %q", a[0].String())
+		}
+		return NumberValue(f(x)), nil
+	}
+}
@@ -0,0 +1,174 @@
+package expr_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/xianyudd/hanzi-data-kit/expr"
+	"github.com/xianyudd/hanzi-data-kit/model"
+	"github.com/xianyudd/hanzi-data-kit/parser"
+)
+
+func record() *parser.StudentRecord {
+	return &parser.StudentRecord{
+		Student: model.Student{Name: "张三", Age: 22, City: "北京", Score: 95.0},
+		Line:    2,
+		Extra:   map[string]string{"学号": "S001", "班级 名称": "一班"},
+	}
+}
+
+func TestExpr_Eval(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{`得分 >= 90 && 城市 in ["北京", "上海"]`, "true"},
+		{`score >= 90 and city not in ["北京", "上海"]`, "false"},
+		{`!(年龄 < 18) || false`, "true"},
+		{`startsWith(姓名, "张") and len(姓名) == 2`, "true"},
+		{`endsWith(学号, "01") && contains(学号, "S0")`, "true"},
+		{`pinyin(姓名)`, "zhang san"},
+		{`initials(姓名) == "zs"`, "true"},
+		{`得分`, "95.0"},
+		{`得分 + 5`, "100"},
+		{`round(得分 / 3, 2)`, "31.67"},
+		{`年龄 * 2 - 4 % 3`, "43"},
+		{`-年龄 + 1`, "-21"},
+		{`姓名 + "-" + 城市`, "张三-北京"},
+		{"`班级 名称` == '一班'", "true"},
+		{`if(得分 >= 90, "A", "B")`, "A"},
+		{`学号 == 1`, "false"},
+		{`num("12") > 9`, "true"},
+		{`upper(lower("AbC")) + trim("  x ")`, "ABCx"},
+		{`len([1, 2, 3])`, "3"},
+		{`score >= 90 AND city NOT IN ["北京", "上海"]`, "false"},
+		{`NOT False Or TRUE`, "true"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			e, err := expr.Compile(tt.src)
+			if err != nil {
+				t.Fatalf("compile: %v", err)
+			}
+			got, err := e.Eval(expr.RecordEnv{Record: record()})
+			if err != nil {
+				t.Fatalf("eval: %v", err)
+			}
+			if got.String() != tt.want {
+				t.Fatalf("got %q, want %q", got.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestExpr_Errors(t *testing.T) {
+	tests := []struct {
+		src     string
+		compile bool // true 表示编译期即应报错
+		wantMsg string
+	}{
+		{`得分 >= `, true, "缺少操作数"},
+		{`得分 >= 90 90`, true, "多余的内容"},
+		{`foo(姓名)`, true, "未知函数"},
+		{`len(姓名, 1)`, true, "需要 1 个参数"},
+		{`"abc`, true, "字符串未闭合"},
+		{`城市 in "北京"`, false, "右侧应为列表"},
+		{`不存在的列 == 1`, false, "未知的列"},
+		{`姓名 > 1`, false, "无法比较"},
+		{`得分 / 0`, false, "除数为 0"},
+		{`得分 && true`, false, "布尔值"},
+		{`得分 >= AND`, true, "不应出现关键字"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			e, err := expr.Compile(tt.src)
+			if tt.compile {
+				if err == nil || !strings.Contains(err.Error(), tt.wantMsg) {
+					t.Fatalf("expected compile error containing %q, got %v", tt.wantMsg, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("compile: %v", err)
+			}
+			if _, err := e.Eval(expr.RecordEnv{Record: record()}); err == nil || !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Fatalf("expected eval error containing %q, got %v", tt.wantMsg, err)
+			}
+		})
+	}
+}
+
+func TestFilterMap(t *testing.T) {
+	records := []parser.StudentRecord{
+		{Student: model.Student{Name: "张三", Age: 22, City: "北京", Score: 95.0}, Line: 2},
+		{Student: model.Student{Name: "李四", Age: 25, City: "上海", Score: 80.0}, Line: 3},
+		{Student: model.Student{Name: "王五", Age: 28, City: "广州", Score: 92.5}, Line: 4},
+	}
+
+	got, err := expr.Filter(records, expr.MustCompile(`得分 >= 90 && 城市 in ["北京", "上海"]`))
+	if err != nil {
+		t.Fatalf("filter: %v", err)
+	}
+	if len(got) != 1 || got[0].Name != "张三" {
+		t.Fatalf("filter mismatch: %+v", got)
+	}
+
+	var sets []expr.Assignment
+	for _, src := range []string{"得分 = 得分 + 5", "age = 年龄 + 1", "拼音 = pinyin(姓名)"} {
+		a, err := expr.ParseAssignment(src)
+		if err != nil {
+			t.Fatalf("parse assignment %q: %v", src, err)
+		}
+		sets = append(sets, a)
+	}
+	if err := expr.Map(records, sets); err != nil {
+		t.Fatalf("map: %v", err)
+	}
+	if r := records[1]; r.Score != 85 || r.Age != 26 || r.Extra["拼音"] != "li si" {
+		t.Fatalf("map mismatch: %+v", r)
+	}
+
+	bad, _ := expr.ParseAssignment(`年龄 = 年龄 / 3`)
+	if err := expr.Map(records, []expr.Assignment{bad}); err == nil || !strings.Contains(err.Error(), "不是整数") {
+		t.Fatalf("expected integer error, got %v", err)
+	}
+}
+
+func TestParseSelect(t *testing.T) {
+	ps, err := expr.ParseSelect(`姓名, 得分 + 1, pinyin(姓名) as 拼音`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"姓名", "得分 + 1", "拼音"}
+	if len(ps) != len(want) {
+		t.Fatalf("expected %d projections, got %d", len(want), len(ps))
+	}
+	for i, p := range ps {
+		if p.Name != want[i] {
+			t.Fatalf("projection[%d] name: got %q, want %q", i, p.Name, want[i])
+		}
+	}
+	if cols := ps[2].Expr.Columns(); len(cols) != 1 || cols[0] != "姓名" {
+		t.Fatalf("columns: got %q", cols)
+	}
+}
+
+func TestColumn(t *testing.T) {
+	rec := record()
+	rec.Extra["备注`1"] = "含反引号"
+	for _, name := range []string{"姓名", "班级 名称", "备注`1"} {
+		e := expr.Column(name)
+		if cols := e.Columns(); len(cols) != 1 || cols[0] != name {
+			t.Fatalf("Column(%q).Columns() = %q", name, cols)
+		}
+		if _, err := e.Eval(expr.RecordEnv{Record: rec}); err != nil {
+			t.Fatalf("Column(%q): %v", name, err)
+		}
+	}
+	if _, err := expr.Compile("`备注`1`"); err == nil {
+		t.Error("反引号列名经词法分析应报错(这正是需要 Column 的原因)")
+	}
+	if _, err := expr.Column("不存在").Eval(expr.RecordEnv{Record: rec}); err == nil {
+		t.Error("未知列应返回 error")
+	}
+}
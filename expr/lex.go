@@ -0,0 +1,115 @@
+package expr
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokNumber
+	tokString
+	tokOp // 运算符与标点：( ) [ ] , == != < <= > >= = + - * / % ! && ||
+)
+
+type token struct {
+	kind tokenKind
+	text string // 标识符名、字符串内容（已去引号与转义）、数字或运算符原文
+	pos  int    // 在源文本中的字节偏移
+	end  int
+}
+
+// lex 将 src 切分为记号序列，末尾总有一个 tokEOF。
+func lex(src string) ([]token, error) {
+	var toks []token
+	i := 0
+	for i < len(src) {
+		r, size := utf8.DecodeRuneInString(src[i:])
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+		case r == '"' || r == '\'':
+			s, n, err := lexString(src[i:], r)
+			if err != nil {
+				return nil, syntaxError(src, i, err.Error())
+			}
+			toks = append(toks, token{kind: tokString, text: s, pos: i, end: i + n})
+			i += n
+		case r == '`':
+			end := strings.IndexByte(src[i+1:], '`')
+			if end < 0 {
+				return nil, syntaxError(src, i, "反引号未闭合")
+			}
+			toks = append(toks, token{kind: tokIdent, text: src[i+1 : i+1+end], pos: i, end: i + end + 2})
+			i += end + 2
+		case r >= '0' && r <= '9' || r == '.' && i+1 < len(src) && src[i+1] >= '0' && src[i+1] <= '9':
+			j := i
+			for j < len(src) && (src[j] >= '0' && src[j] <= '9' || src[j] == '.') {
+				j++
+			}
+			toks = append(toks, token{kind: tokNumber, text: src[i:j], pos: i, end: j})
+			i = j
+		case r == '_' || unicode.IsLetter(r):
+			j := i
+			for j < len(src) {
+				r2, s2 := utf8.DecodeRuneInString(src[j:])
+				if r2 != '_' && !unicode.IsLetter(r2) && !unicode.IsDigit(r2) {
+					break
+				}
+				j += s2
+			}
+			toks = append(toks, token{kind: tokIdent, text: src[i:j], pos: i, end: j})
+			i = j
+		default:
+			op := ""
+			for _, cand := range []string{"==", "!=", "<=", ">=", "&&", "||", "(", ")", "[", "]", ",", "<", ">", "=", "+", "-", "*", "/", "%", "!"} {
+				if strings.HasPrefix(src[i:], cand) {
+					op = cand
+					break
+				}
+			}
+			if op == "" {
+				return nil, syntaxError(src, i, fmt.Sprintf("无法识别的字符 %q", r))
+			}
+			toks = append(toks, token{kind: tokOp, text: op, pos: i, end: i + len(op)})
+			i += len(op)
+		}
+	}
+	return append(toks, token{kind: tokEOF, pos: len(src), end: len(src)}), nil
+}
+
+// lexString 解析以 quote 开头的字符串字面量，支持 \\ \" \' \n \t 转义；返回内容与消耗的字节数。
+func lexString(s string, quote rune) (string, int, error) {
+	var b strings.Builder
+	for i := 1; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == quote:
+			return b.String(), i + size, nil
+		case r == '\\' && i+1 < len(s):
+			switch s[i+1] {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			default:
+				b.WriteByte(s[i+1])
+			}
+			i += 2
+			continue
+		}
+		b.WriteRune(r)
+		i += size
+	}
+	return "", 0, fmt.Errorf("字符串未闭合")
+}
+
+// syntaxError 构造带位置（第几个字符，1-based）的语法错误。
+func syntaxError(src string, pos int, msg string) error {
+	return fmt.Errorf("表达式语法错误（第 %d 个字符）: %s", utf8.RuneCountInString(src[:pos])+1, msg)
+}
@@ -0,0 +1,452 @@
+package expr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Expr 是编译后的表达式，可对多条记录重复求值；并发求值是安全的。
+type Expr struct {
+	src  string
+	root node
+}
+
+// Compile 编译表达式 src。
+func Compile(src string) (*Expr, error) {
+	p, err := newParser(src)
+	if err != nil {
+		return nil, err
+	}
+	root, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expectEOF(); err != nil {
+		return nil, err
+	}
+	return &Expr{src: src, root: root}, nil
+}
+
+// MustCompile 与 Compile 相同，但编译失败时 panic；用于包级变量或测试。
+func MustCompile(src string) *Expr {
+	e, err := Compile(src)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// Column 返回只引用列 name 的表达式，等价于 Compile("`name`")，但不经过词法分析，
+// 因此列名中含有反引号等任意字符时也不会出错。String 返回列名本身。
+func Column(name string) *Expr {
+	return &Expr{src: name, root: identNode{name: name}}
+}
+
+// String 返回表达式源文本。
+func (e *Expr) String() string { return e.src }
+
+// Columns 返回表达式引用的全部列名（去重，按首次出现的顺序），便于在求值前检查列是否存在。
+func (e *Expr) Columns() []string {
+	var cols []string
+	seen := make(map[string]bool)
+	walk(e.root, func(n node) {
+		if id, ok := n.(identNode); ok && !seen[id.name] {
+			seen[id.name] = true
+			cols = append(cols, id.name)
+		}
+	})
+	return cols
+}
+
+// Assignment 为一条 "列名 = 表达式" 形式的赋值，用于 Map / -set。
+type Assignment struct {
+	Column string
+	Expr   *Expr
+}
+
+// ParseAssignment 解析 "列名 = 表达式"（如 `得分 = round(得分 * 1.1, 1)`）。
+func ParseAssignment(src string) (Assignment, error) {
+	p, err := newParser(src)
+	if err != nil {
+		return Assignment{}, err
+	}
+	col := p.next()
+	if col.kind != tokIdent || isKeyword(col.text) && src[col.pos] != '`' {
+		return Assignment{}, syntaxError(src, col.pos, "赋值应以列名开头，形如 列名 = 表达式")
+	}
+	if eq := p.next(); eq.kind != tokOp || eq.text != "=" {
+		return Assignment{}, syntaxError(src, eq.pos, "列名之后应为 =")
+	}
+	start := p.peek().pos
+	root, err := p.parseExpr()
+	if err != nil {
+		return Assignment{}, err
+	}
+	if err := p.expectEOF(); err != nil {
+		return Assignment{}, err
+	}
+	return Assignment{Column: col.text, Expr: &Expr{src: strings.TrimSpace(src[start:]), root: root}}, nil
+}
+
+// Projection 为输出中的一列：列名与求值表达式。
+type Projection struct {
+	Name string
+	Expr *Expr
+}
+
+// ParseSelect 解析以逗号分隔的输出列表，每项为 `表达式 [as 名称]`，
+// 如 `姓名, 得分, pinyin(姓名) as 拼音`。未指定名称时，列引用使用列名，其余使用表达式原文。
+func ParseSelect(src string) ([]Projection, error) {
+	p, err := newParser(src)
+	if err != nil {
+		return nil, err
+	}
+	var out []Projection
+	for {
+		start := p.peek().pos
+		root, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		text := strings.TrimSpace(src[start:p.prevEnd])
+		name := text
+		if id, ok := root.(identNode); ok {
+			name = id.name
+		}
+		if t := p.peek(); t.kind == tokIdent && strings.EqualFold(t.text, "as") {
+			p.next()
+			alias := p.next()
+			if alias.kind != tokIdent && alias.kind != tokString {
+				return nil, syntaxError(src, alias.pos, "as 之后应为列名")
+			}
+			name = alias.text
+		}
+		out = append(out, Projection{Name: name, Expr: &Expr{src: text, root: root}})
+		if t := p.peek(); t.kind == tokOp && t.text == "," {
+			p.next()
+			continue
+		}
+		if err := p.expectEOF(); err != nil {
+			return nil, err
+		}
+		return out, nil
+	}
+}
+
+// node 为语法树节点。
+type node interface{}
+
+type (
+	literalNode struct{ v Value }
+	identNode   struct{ name string }
+	listNode    struct{ elems []node }
+	unaryNode   struct {
+		op string // "!" 或 "-"
+		x  node
+	}
+	binaryNode struct {
+		op   string // 规范化后的运算符：|| && == != < <= > >= in notin + - * / %
+		l, r node
+	}
+	callNode struct {
+		name string
+		fn   *function
+		args []node
+	}
+)
+
+func walk(n node, f func(node)) {
+	f(n)
+	switch n := n.(type) {
+	case listNode:
+		for _, e := range n.elems {
+			walk(e, f)
+		}
+	case unaryNode:
+		walk(n.x, f)
+	case binaryNode:
+		walk(n.l, f)
+		walk(n.r, f)
+	case callNode:
+		for _, a := range n.args {
+			walk(a, f)
+		}
+	}
+}
+
+// keywords 为小写形式；关键字匹配不区分大小写（AND、True 等同于 and、true）。
+var keywords = map[string]bool{"and": true, "or": true, "not": true, "in": true, "true": true, "false": true}
+
+func isKeyword(s string) bool { return keywords[strings.ToLower(s)] }
+
+type exprParser struct {
+	src     string
+	toks    []token
+	i       int
+	prevEnd int // 上一个被消耗记号的结束位置
+}
+
+func newParser(src string) (*exprParser, error) {
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	return &exprParser{src: src, toks: toks}, nil
+}
+
+func (p *exprParser) peek() token { return p.toks[p.i] }
+
+func (p *exprParser) next() token {
+	t := p.toks[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	p.prevEnd = t.end
+	return t
+}
+
+// accept 若下一个记号是运算符 op 或（不区分大小写的）关键字 op 之一，则消耗并返回 true。
+func (p *exprParser) accept(ops ...string) (string, bool) {
+	t := p.peek()
+	for _, op := range ops {
+		if t.kind == tokOp && t.text == op ||
+			t.kind == tokIdent && keywords[op] && strings.EqualFold(t.text, op) && p.src[t.pos] != '`' {
+			p.next()
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *exprParser) expect(op string) error {
+	if _, ok := p.accept(op); !ok {
+		return p.errorf("应为 %q", op)
+	}
+	return nil
+}
+
+func (p *exprParser) expectEOF() error {
+	if t := p.peek(); t.kind != tokEOF {
+		return p.errorf("多余的内容 %q", p.src[t.pos:])
+	}
+	return nil
+}
+
+func (p *exprParser) errorf(format string, args ...any) error {
+	t := p.peek()
+	msg := fmt.Sprintf(format, args...)
+	if t.kind == tokEOF {
+		msg += "，但表达式已结束"
+	}
+	return syntaxError(p.src, t.pos, msg)
+}
+
+func (p *exprParser) parseExpr() (node, error) { return p.parseOr() }
+
+func (p *exprParser) parseOr() (node, error) {
+	l, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("||", "or"); !ok {
+			return l, nil
+		}
+		r, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l = binaryNode{op: "||", l: l, r: r}
+	}
+}
+
+func (p *exprParser) parseAnd() (node, error) {
+	l, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("&&", "and"); !ok {
+			return l, nil
+		}
+		r, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		l = binaryNode{op: "&&", l: l, r: r}
+	}
+}
+
+func (p *exprParser) parseNot() (node, error) {
+	if _, ok := p.accept("!", "not"); ok {
+		x, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return unaryNode{op: "!", x: x}, nil
+	}
+	return p.parseCompare()
+}
+
+func (p *exprParser) parseCompare() (node, error) {
+	l, err := p.parseAdd()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.accept("==", "!=", "<=", ">=", "<", ">", "in", "not")
+	if !ok {
+		return l, nil
+	}
+	if op == "not" {
+		if err := p.expect("in"); err != nil {
+			return nil, err
+		}
+		op = "notin"
+	}
+	r, err := p.parseAdd()
+	if err != nil {
+		return nil, err
+	}
+	return binaryNode{op: op, l: l, r: r}, nil
+}
+
+func (p *exprParser) parseAdd() (node, error) {
+	l, err := p.parseMul()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept("+", "-")
+		if !ok {
+			return l, nil
+		}
+		r, err := p.parseMul()
+		if err != nil {
+			return nil, err
+		}
+		l = binaryNode{op: op, l: l, r: r}
+	}
+}
+
+func (p *exprParser) parseMul() (node, error) {
+	l, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept("*", "/", "%")
+		if !ok {
+			return l, nil
+		}
+		r, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l = binaryNode{op: op, l: l, r: r}
+	}
+}
+
+func (p *exprParser) parseUnary() (node, error) {
+	if _, ok := p.accept("-"); ok {
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return unaryNode{op: "-", x: x}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *exprParser) parsePrimary() (node, error) {
+	t := p.peek()
+	switch t.kind {
+	case tokNumber:
+		p.next()
+		n, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, syntaxError(p.src, t.pos, fmt.Sprintf("非法数字 %q", t.text))
+		}
+		return literalNode{NumberValue(n)}, nil
+	case tokString:
+		p.next()
+		return literalNode{StringValue(t.text)}, nil
+	case tokIdent:
+		quoted := p.src[t.pos] == '`'
+		if !quoted {
+			switch kw := strings.ToLower(t.text); kw {
+			case "true", "false":
+				p.next()
+				return literalNode{BoolValue(kw == "true")}, nil
+			case "and", "or", "not", "in":
+				return nil, p.errorf("此处不应出现关键字 %q", t.text)
+			}
+		}
+		p.next()
+		if !quoted {
+			if _, ok := p.accept("("); ok {
+				return p.parseCall(t)
+			}
+		}
+		return identNode{name: t.text}, nil
+	case tokOp:
+		switch t.text {
+		case "(":
+			p.next()
+			x, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			return x, p.expect(")")
+		case "[":
+			p.next()
+			elems, err := p.parseArgs("]")
+			if err != nil {
+				return nil, err
+			}
+			return listNode{elems: elems}, nil
+		}
+	}
+	if t.kind == tokEOF {
+		return nil, p.errorf("缺少操作数")
+	}
+	return nil, p.errorf("此处不应出现 %q", p.src[t.pos:t.end])
+}
+
+func (p *exprParser) parseCall(name token) (node, error) {
+	fn, ok := functions[name.text]
+	if !ok {
+		return nil, syntaxError(p.src, name.pos, fmt.Sprintf("未知函数 %s（可用: %s）", name.text, functionNames()))
+	}
+	args, err := p.parseArgs(")")
+	if err != nil {
+		return nil, err
+	}
+	if len(args) < fn.minArgs || len(args) > fn.maxArgs {
+		want := strconv.Itoa(fn.minArgs)
+		if fn.maxArgs != fn.minArgs {
+			want += "~" + strconv.Itoa(fn.maxArgs)
+		}
+		return nil, syntaxError(p.src, name.pos, fmt.Sprintf("函数 %s 需要 %s 个参数，实际为 %d 个", name.text, want, len(args)))
+	}
+	return callNode{name: name.text, fn: fn, args: args}, nil
+}
+
+// parseArgs 解析以 closing 结尾、逗号分隔的表达式列表（左括号已被消耗）。
+func (p *exprParser) parseArgs(closing string) ([]node, error) {
+	var args []node
+	if _, ok := p.accept(closing); ok {
+		return args, nil
+	}
+	for {
+		a, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, a)
+		if _, ok := p.accept(","); ok {
+			continue
+		}
+		return args, p.expect(closing)
+	}
+}
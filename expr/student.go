@@ -0,0 +1,124 @@
+package expr
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/xianyudd/hanzi-data-kit/model"
+	"github.com/xianyudd/hanzi-data-kit/parser"
+)
+
+// studentColumns 为 “规范化列名/别名 -> 标准中文列名” 的查找表，别名与 CSV 表头别名一致。
+var studentColumns = func() map[string]string {
+	m := make(map[string]string)
+	aliases := parser.DefaultStudentHeaderAliases()
+	for _, col := range model.StudentHeadersCN() {
+		m[parser.NormalizeHeader(col)] = col
+		for _, a := range aliases[col] {
+			m[parser.NormalizeHeader(a)] = col
+		}
+	}
+	return m
+}()
+
+// StudentColumn 将列名（含英文别名，如 score）解析为标准中文列名；不是标准列时返回 ok=false。
+func StudentColumn(name string) (col string, ok bool) {
+	col, ok = studentColumns[parser.NormalizeHeader(name)]
+	return col, ok
+}
+
+// RecordEnv 以一条学生记录作为求值环境：标准列（及其别名）取自 Student 字段，其余列取自 Extra。
+// 年龄与得分为数值，其文本形式与 model.StudentToRowCN 一致（如得分 "95.0"）。
+type RecordEnv struct {
+	Record *parser.StudentRecord
+}
+
+// Lookup 实现 Env。
+func (e RecordEnv) Lookup(name string) (Value, bool) {
+	if col, ok := StudentColumn(name); ok {
+		s := e.Record.Student
+		switch col {
+		case "姓名":
+			return StringValue(s.Name), true
+		case "年龄":
+			return formattedNumber(float64(s.Age), strconv.Itoa(s.Age)), true
+		case "城市":
+			return StringValue(s.City), true
+		case "得分":
+			return formattedNumber(s.Score, model.StudentToRowCN(s)[3]), true
+		}
+	}
+	v, ok := e.Record.Extra[name]
+	return StringValue(v), ok
+}
+
+// Match 判断记录是否满足筛选条件 where。
+func (e *Expr) Match(rec *parser.StudentRecord) (bool, error) {
+	return e.EvalBool(RecordEnv{Record: rec})
+}
+
+// Apply 对记录执行赋值：标准列写回 Student 字段（年龄须为整数），其余列写入 Extra。
+func (a Assignment) Apply(rec *parser.StudentRecord) error {
+	v, err := a.Expr.Eval(RecordEnv{Record: rec})
+	if err != nil {
+		return err
+	}
+	col, ok := StudentColumn(a.Column)
+	if !ok {
+		if rec.Extra == nil {
+			rec.Extra = make(map[string]string)
+		}
+		rec.Extra[a.Column] = v.String()
+		return nil
+	}
+	switch col {
+	case "姓名":
+		rec.Name = v.String()
+	case "城市":
+		rec.City = v.String()
+	case "年龄":
+		n, ok := v.Number()
+		if !ok || n != math.Trunc(n) || math.Abs(n) > math.MaxInt32 {
+			return fmt.Errorf("赋值 %s 失败: %q 不是整数", col, v.String())
+		}
+		rec.Age = int(n)
+	case "得分":
+		n, ok := v.Number()
+		if !ok {
+			return fmt.Errorf("赋值 %s 失败: %q 不是数字", col, v.String())
+		}
+		rec.Score = n
+	}
+	return nil
+}
+
+// Filter 返回满足 where 的记录（保持原顺序）；where 为 nil 时返回全部记录。
+func Filter(records []parser.StudentRecord, where *Expr) ([]parser.StudentRecord, error) {
+	if where == nil {
+		return records, nil
+	}
+	var out []parser.StudentRecord
+	for i := range records {
+		ok, err := where.Match(&records[i])
+		if err != nil {
+			return nil, fmt.Errorf("第 %d 行: %w", records[i].Line, err)
+		}
+		if ok {
+			out = append(out, records[i])
+		}
+	}
+	return out, nil
+}
+
+// Map 依次对每条记录执行 sets 中的赋值（原地修改）；后面的赋值可以引用前面赋值的结果。
+func Map(records []parser.StudentRecord, sets []Assignment) error {
+	for i := range records {
+		for _, a := range sets {
+			if err := a.Apply(&records[i]); err != nil {
+				return fmt.Errorf("第 %d 行: %w", records[i].Line, err)
+			}
+		}
+	}
+	return nil
+}
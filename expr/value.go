@@ -0,0 +1,153 @@
+package expr
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Kind 为值的类型。
+type Kind int
+
+const (
+	// String 为字符串（零值）。
+	String Kind = iota
+	// Number 为 float64 数值。
+	Number
+	// Bool 为布尔值。
+	Bool
+	// List 为值列表（仅由列表字面量产生，用于 in）。
+	List
+)
+
+func (k Kind) String() string {
+	switch k {
+	case String:
+		return "字符串"
+	case Number:
+		return "数字"
+	case Bool:
+		return "布尔"
+	case List:
+		return "列表"
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// Value 是表达式求值的结果。零值为空字符串。
+type Value struct {
+	kind Kind
+	str  string
+	num  float64
+	b    bool
+	list []Value
+
+	// text 为数值的原始文本（如得分 "95.0"）；非空时 String 原样返回，以保持与 CSV 写出格式一致。
+	text string
+}
+
+// StringValue 构造字符串值。
+func StringValue(s string) Value { return Value{kind: String, str: s} }
+
+// NumberValue 构造数值。
+func NumberValue(n float64) Value { return Value{kind: Number, num: n} }
+
+// BoolValue 构造布尔值。
+func BoolValue(b bool) Value { return Value{kind: Bool, b: b} }
+
+// ListValue 构造列表值。
+func ListValue(vs ...Value) Value { return Value{kind: List, list: vs} }
+
+// formattedNumber 构造带原始文本的数值。
+func formattedNumber(n float64, text string) Value { return Value{kind: Number, num: n, text: text} }
+
+// Kind 返回值的类型。
+func (v Value) Kind() Kind { return v.kind }
+
+// Bool 返回布尔值；ok=false 表示 v 不是布尔值。
+func (v Value) Bool() (b bool, ok bool) { return v.b, v.kind == Bool }
+
+// List 返回列表元素；v 不是列表时返回 nil。
+func (v Value) List() []Value { return v.list }
+
+// Number 返回 v 的数值：数字直接返回，字符串尝试按数字解析。
+func (v Value) Number() (float64, bool) {
+	switch v.kind {
+	case Number:
+		return v.num, true
+	case String:
+		n, err := strconv.ParseFloat(strings.TrimSpace(v.str), 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// String 返回值的文本形式，即写出到 CSV 时的单元格内容。
+func (v Value) String() string {
+	switch v.kind {
+	case Number:
+		if v.text != "" {
+			return v.text
+		}
+		return strconv.FormatFloat(v.num, 'f', -1, 64)
+	case Bool:
+		return strconv.FormatBool(v.b)
+	case List:
+		parts := make([]string, len(v.list))
+		for i, e := range v.list {
+			parts[i] = e.String()
+		}
+		return "[" + strings.Join(parts, ",") + "]"
+	}
+	return v.str
+}
+
+// MarshalJSON 按值的类型输出 JSON 字符串、数字、布尔或数组。
+func (v Value) MarshalJSON() ([]byte, error) {
+	switch v.kind {
+	case Number:
+		return json.Marshal(v.num)
+	case Bool:
+		return json.Marshal(v.b)
+	case List:
+		return json.Marshal(v.list)
+	}
+	return json.Marshal(v.str)
+}
+
+// equal 判断两个值是否相等；数字与可解析为数字的字符串按数值比较。
+func equal(a, b Value) (bool, error) {
+	c, err := compare(a, b, true)
+	return c == 0, err
+}
+
+// compare 比较两个值，返回 -1/0/1。eqOnly=true 时允许布尔与列表之间做相等比较。
+func compare(a, b Value, eqOnly bool) (int, error) {
+	switch {
+	case a.kind == String && b.kind == String:
+		return strings.Compare(a.str, b.str), nil
+	case a.kind == Number || b.kind == Number:
+		x, okA := a.Number()
+		y, okB := b.Number()
+		if !okA || !okB {
+			if eqOnly && a.kind != b.kind && (a.kind == String || b.kind == String) {
+				return 1, nil // "abc" == 1 为 false
+			}
+			return 0, fmt.Errorf("无法比较 %s %q 与 %s %q", a.kind, a.String(), b.kind, b.String())
+		}
+		switch {
+		case x < y:
+			return -1, nil
+		case x > y:
+			return 1, nil
+		}
+		return 0, nil
+	case eqOnly && a.kind == Bool && b.kind == Bool:
+		if a.b == b.b {
+			return 0, nil
+		}
+		return 1, nil
+	}
+	return 0, fmt.Errorf("无法比较 %s 与 %s", a.kind, b.kind)
+}
@@ -0,0 +1,73 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/xianyudd/hanzi-data-kit/model"
+	"github.com/xianyudd/hanzi-data-kit/region"
+)
+
+// AddressGenConfig 定义地址生成策略。
+type AddressGenConfig struct {
+	// Seed 为随机种子；相同配置+相同 Seed 将生成相同序列的地址。
+	Seed int64
+
+	// Provinces 限定只生成这些省级区划（名称、简称或代码，如 "浙江"、"330000"）下的地址；为空时覆盖全部省份。
+	Provinces []string
+
+	// RNG 为随机数算法；为空时为 RNGMathRand。
+	RNG RNGAlgorithm
+}
+
+// AddressGenerator 按人口加权生成分级地址。
+type AddressGenerator struct {
+	rng     randSource
+	sampler *region.Sampler
+}
+
+// NewAddressGenerator 构造地址生成器；Provinces 中含未知省份或 RNG 未知时返回 error。
+func NewAddressGenerator(cfg AddressGenConfig) (*AddressGenerator, error) {
+	alg, err := ParseRNGAlgorithm(string(cfg.RNG))
+	if err != nil {
+		return nil, err
+	}
+	sampler, err := region.NewSampler(cfg.Provinces...)
+	if err != nil {
+		return nil, err
+	}
+	return &AddressGenerator{rng: newRand(alg, uint64(cfg.Seed)), sampler: sampler}, nil
+}
+
+// Next 生成一个地址：地级市按常住人口加权抽取，区县在其下辖区划中等概率抽取。
+func (g *AddressGenerator) Next() model.Address {
+	return nextAddress(g.rng, g.sampler)
+}
+
+// streetNames 为街道与道路名的候选词。
+var streetNames = []string{
+	"人民", "解放", "中山", "建设", "和平", "新华", "文化", "胜利", "朝阳", "长安",
+	"青年", "光明", "东风", "幸福", "滨江", "花园", "南湖", "北苑", "西园", "东湖",
+}
+
+// roadSuffixes 为道路名后缀。
+var roadSuffixes = []string{"路", "路", "路", "街", "大道"}
+
+func nextAddress(rng randSource, sampler *region.Sampler) model.Address {
+	province, city, county := sampler.Pick(rng)
+	return model.Address{
+		Province:    province.Name,
+		City:        city.Name,
+		District:    county.Name,
+		Code:        county.Code,
+		Street:      pickOne(rng, streetNames) + "街道",
+		HouseNumber: fmt.Sprintf("%s%s%d号", pickOne(rng, streetNames), pickOne(rng, roadSuffixes), randInt(rng, 1, 999)),
+	}
+}
+
+// cityName 返回地址中地级市的简称，与 model.Student.City 的写法一致（如 杭州市 → 杭州）。
+func cityName(a model.Address) string {
+	if c, ok := region.FindCity(a.City); ok {
+		return c.ShortName()
+	}
+	return a.City
+}
@@ -0,0 +1,50 @@
+package generator_test
+
+import (
+	"testing"
+
+	"github.com/xianyudd/hanzi-data-kit/generator"
+	"github.com/xianyudd/hanzi-data-kit/region"
+)
+
+func TestAddressGenerator(t *testing.T) {
+	g1, err := generator.NewAddressGenerator(generator.AddressGenConfig{Seed: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	g2, _ := generator.NewAddressGenerator(generator.AddressGenConfig{Seed: 3})
+	for i := range 200 {
+		a, b := g1.Next(), g2.Next()
+		if a != b {
+			t.Fatalf("第 %d 个地址不可复现: %v vs %v", i, a, b)
+		}
+		d, ok := region.Lookup(a.Code)
+		if !ok || d.Name != a.District {
+			t.Fatalf("区划代码 %s 与区县 %s 不符", a.Code, a.District)
+		}
+		if err := region.CheckCityDistrict(a.City, a.District); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := generator.NewAddressGenerator(generator.AddressGenConfig{Provinces: []string{"火星"}}); err == nil {
+		t.Error("未知省份应返回 error")
+	}
+}
+
+func TestStudentGenerator_UseRegions(t *testing.T) {
+	g := generator.NewStudentGenerator(generator.StudentGenConfig{Seed: 1, UseRegions: true})
+	for range 200 {
+		stu := g.Next()
+		if _, ok := region.FindCity(stu.City); !ok {
+			t.Fatalf("城市 %q 不在区划表中", stu.City)
+		}
+	}
+	g = generator.NewStudentGenerator(generator.StudentGenConfig{Seed: 1})
+	for range 200 {
+		stu, addr := g.NextWithAddress()
+		if err := region.CheckCityDistrict(stu.City, addr.District); err != nil {
+			t.Fatalf("学生城市与地址不一致: %v", err)
+		}
+	}
+}
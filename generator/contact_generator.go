@@ -0,0 +1,169 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/xianyudd/hanzi-data-kit/contact"
+	"github.com/xianyudd/hanzi-data-kit/hanzi"
+	"github.com/xianyudd/hanzi-data-kit/model"
+)
+
+// ContactGenConfig 定义联系方式的生成策略。
+type ContactGenConfig struct {
+	// Seed 为随机种子；相同配置+相同 Seed+相同姓名序列将生成相同的联系方式。
+	Seed int64
+
+	// Carriers 限定手机号所属的运营商；为空时在全部运营商的号段中等概率抽取。
+	Carriers []contact.Carrier
+
+	// MaskPhone 为 true 时输出脱敏手机号（如 138****1234）；唯一性仍按完整号码保证。
+	MaskPhone bool
+
+	// EmailDomains 为邮箱域名候选；为空时使用 qq.com/163.com/126.com/sina.com/outlook.com/gmail.com。
+	EmailDomains []string
+
+	// RNG 为随机数算法；为空时为 RNGMathRand。
+	RNG RNGAlgorithm
+}
+
+// ContactGenerator 根据姓名生成手机号、邮箱与用户名。
+// 同一生成器产生的手机号、邮箱、用户名各自唯一：用户名/邮箱冲突时追加递增数字（如 zhangsan2），手机号冲突时重新抽取。
+type ContactGenerator struct {
+	rng      randSource
+	cfg      ContactGenConfig
+	prefixes []string
+
+	phones    map[string]bool
+	emails    map[string]bool
+	usernames map[string]int
+}
+
+// NewContactGenerator 构造联系方式生成器；Carriers 含未知运营商或 RNG 未知时返回 error。
+func NewContactGenerator(cfg ContactGenConfig) (*ContactGenerator, error) {
+	alg, err := ParseRNGAlgorithm(string(cfg.RNG))
+	if err != nil {
+		return nil, err
+	}
+	return newContactGenerator(newRand(alg, uint64(cfg.Seed)), cfg)
+}
+
+// newContactGenerator 构造使用给定随机源的联系方式生成器（cfg.Seed 与 cfg.RNG 被忽略），供模板等需要共享随机序列的场景使用。
+func newContactGenerator(rng randSource, cfg ContactGenConfig) (*ContactGenerator, error) {
+	carriers := cfg.Carriers
+	if len(carriers) == 0 {
+		carriers = contact.Carriers()
+	}
+	var prefixes []string
+	for _, c := range carriers {
+		ps := contact.Prefixes(c)
+		if len(ps) == 0 {
+			return nil, fmt.Errorf("未知的运营商 %q", c)
+		}
+		prefixes = append(prefixes, ps...)
+	}
+	if len(cfg.EmailDomains) == 0 {
+		cfg.EmailDomains = []string{"qq.com", "163.com", "126.com", "sina.com", "outlook.com", "gmail.com"}
+	}
+	return &ContactGenerator{
+		rng:       rng,
+		cfg:       cfg,
+		prefixes:  prefixes,
+		phones:    map[string]bool{},
+		emails:    map[string]bool{},
+		usernames: map[string]int{},
+	}, nil
+}
+
+// Next 为姓名 name 生成一组联系方式。
+func (g *ContactGenerator) Next(name string) model.Contact {
+	return model.Contact{Phone: g.Phone(), Email: g.Email(name), Username: g.Username(name)}
+}
+
+// Phone 生成一个未出现过的手机号。
+func (g *ContactGenerator) Phone() string {
+	for {
+		p := pickOne(g.rng, g.prefixes) + fmt.Sprintf("%08d", g.rng.Intn(100_000_000))
+		if g.phones[p] {
+			continue
+		}
+		g.phones[p] = true
+		if g.cfg.MaskPhone {
+			return contact.MaskPhone(p)
+		}
+		return p
+	}
+}
+
+// Username 由姓名拼音生成一个未出现过的用户名，如 zhangsan、zhang.san、sanzhang、zhangs。
+func (g *ContactGenerator) Username(name string) string {
+	base := g.handle(name)
+	n := g.usernames[base] + 1
+	g.usernames[base] = n
+	if n == 1 {
+		return base
+	}
+	// 追加数字后仍可能与其他基名撞车（如 zhangsan2 本身就是基名），继续递增直到唯一。
+	for {
+		u := fmt.Sprintf("%s%d", base, n)
+		if g.usernames[u] == 0 {
+			g.usernames[u] = 1
+			return u
+		}
+		n++
+		g.usernames[base] = n
+	}
+}
+
+// Email 由姓名拼音生成一个未出现过的邮箱地址。
+func (g *ContactGenerator) Email(name string) string {
+	local := g.handle(name)
+	if g.rng.Float64() < 0.3 {
+		local += fmt.Sprint(randInt(g.rng, 1970, 2010))
+	}
+	domain := pickOne(g.rng, g.cfg.EmailDomains)
+	for n := 1; ; n++ {
+		addr := local + "@" + domain
+		if n > 1 {
+			addr = fmt.Sprintf("%s%d@%s", local, n, domain)
+		}
+		if !g.emails[addr] {
+			g.emails[addr] = true
+			return addr
+		}
+	}
+}
+
+// handle 按随机选择的样式把姓名拼音拼接为不含数字后缀的账号名；结果总是满足 contact.ValidateUsername。
+func (g *ContactGenerator) handle(name string) string {
+	py := hanzi.NamePinyin(name)
+	if len(py) == 0 {
+		py = []string{"user"}
+	}
+	surname, given := py[0], strings.Join(py[1:], "")
+	var h string
+	switch {
+	case given == "":
+		h = surname
+	default:
+		switch g.rng.Intn(5) {
+		case 0, 1:
+			h = surname + given
+		case 2:
+			h = surname + "." + given
+		case 3:
+			h = given + surname
+		default:
+			h = surname + hanzi.Initials(py[1:])
+		}
+	}
+	if h[0] < 'a' || h[0] > 'z' {
+		h = "u" + h
+	}
+	// 截断后可能以 . 或 _ 结尾，需去掉。
+	h = strings.TrimRight(h[:min(len(h), 28)], "._")
+	for len(h) < 3 {
+		h += "0"
+	}
+	return h
+}
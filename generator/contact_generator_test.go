@@ -0,0 +1,73 @@
+package generator_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/xianyudd/hanzi-data-kit/contact"
+	"github.com/xianyudd/hanzi-data-kit/generator"
+)
+
+func TestContactGenerator(t *testing.T) {
+	cfg := generator.ContactGenConfig{Seed: 9, Carriers: []contact.Carrier{contact.ChinaUnicom}}
+	g1, err := generator.NewContactGenerator(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g2, _ := generator.NewContactGenerator(cfg)
+
+	phones, emails, users := map[string]bool{}, map[string]bool{}, map[string]bool{}
+	for i := range 300 {
+		// 同名反复出现，以触发用户名/邮箱的冲突处理。
+		name := []string{"张三", "李小明", "欧阳娜"}[i%3]
+		a, b := g1.Next(name), g2.Next(name)
+		if a != b {
+			t.Fatalf("第 %d 组联系方式不可复现: %v vs %v", i, a, b)
+		}
+		if err := contact.ValidatePhone(a.Phone); err != nil {
+			t.Fatal(err)
+		}
+		if c, _ := contact.CarrierOf(a.Phone); c != contact.ChinaUnicom {
+			t.Fatalf("%s 不属于联通号段", a.Phone)
+		}
+		if err := contact.ValidateEmail(a.Email); err != nil {
+			t.Fatal(err)
+		}
+		if err := contact.ValidateUsername(a.Username); err != nil {
+			t.Fatal(err)
+		}
+		if phones[a.Phone] || emails[a.Email] || users[a.Username] {
+			t.Fatalf("第 %d 组出现重复: %v", i, a)
+		}
+		phones[a.Phone], emails[a.Email], users[a.Username] = true, true, true
+	}
+	if !users["zhangsan"] && !users["zhang.san"] && !users["sanzhang"] && !users["zhangs"] {
+		t.Errorf("用户名未由拼音生成: %v", users)
+	}
+
+	masked, _ := generator.NewContactGenerator(generator.ContactGenConfig{Seed: 1, MaskPhone: true})
+	if p := masked.Phone(); !strings.Contains(p, "****") || contact.ValidatePhone(p) != nil {
+		t.Errorf("脱敏手机号 = %q", p)
+	}
+	if _, err := generator.NewContactGenerator(generator.ContactGenConfig{Carriers: []contact.Carrier{"cmcc"}}); err == nil {
+		t.Error("未知运营商应返回 error")
+	}
+}
+
+func TestContactGenerator_LongName(t *testing.T) {
+	// 27 个字母的"姓"加 "." 样式恰好在截断处以 "." 结尾。
+	name := strings.Repeat("a", 27) + "张三"
+	g, _ := generator.NewContactGenerator(generator.ContactGenConfig{Seed: 3})
+	for range 50 {
+		u := g.Username(name)
+		if err := contact.ValidateUsername(u); err != nil {
+			t.Fatal(err)
+		}
+		if len(u) > 32 {
+			t.Fatalf("用户名过长: %q", u)
+		}
+		if err := contact.ValidateEmail(g.Email(name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
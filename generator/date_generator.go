@@ -0,0 +1,167 @@
+package generator
+
+import "time"
+
+// DateGenConfig 定义日期与时间戳的生成策略。
+// 约定：不读取系统时钟，“今天”由 Reference 指定，以保证相同配置+相同 Seed 生成相同的日期。
+type DateGenConfig struct {
+	// Seed 为随机种子。
+	Seed int64
+
+	// Reference 为计算年龄与学年时的参考日期（即“今天”）；零值为 2025-09-01。
+	Reference time.Time
+
+	// Location 为生成日期/时间戳所在的时区；为 nil 时使用东八区（UTC+8）。
+	// Reference 会先换算到该时区再取日期。
+	Location *time.Location
+
+	// SchoolYearStartMonth 为学年开始的月份；默认 9 月。
+	SchoolYearStartMonth time.Month
+
+	// EnrollmentAge 为入学年龄（以学年开始前一天计算周岁）；默认 18，与学生默认年龄范围一致。
+	EnrollmentAge int
+
+	// RegistrationDays 为开学报到的天数：入学日期在学年首月的第 1~RegistrationDays 天中抽取；默认 7。
+	RegistrationDays int
+
+	// RNG 为随机数算法；为空时为 RNGMathRand。
+	// 未知的算法会导致 NewDateGenerator panic，请先用 ParseRNGAlgorithm 校验。
+	RNG RNGAlgorithm
+}
+
+// chinaStandardTime 为东八区；使用固定时区以免依赖系统的时区数据库。
+var chinaStandardTime = time.FixedZone("CST", 8*3600)
+
+func applyDateDefaults(cfg *DateGenConfig) {
+	if cfg.Location == nil {
+		cfg.Location = chinaStandardTime
+	}
+	if cfg.Reference.IsZero() {
+		cfg.Reference = time.Date(2025, 9, 1, 0, 0, 0, 0, cfg.Location)
+	}
+	cfg.Reference = truncateDay(cfg.Reference.In(cfg.Location))
+	if cfg.SchoolYearStartMonth < time.January || cfg.SchoolYearStartMonth > time.December {
+		cfg.SchoolYearStartMonth = time.September
+	}
+	if cfg.EnrollmentAge <= 0 {
+		cfg.EnrollmentAge = 18
+	}
+	if cfg.RegistrationDays <= 0 {
+		cfg.RegistrationDays = 7
+	}
+}
+
+// DateGenerator 生成与年龄、学年一致的日期以及落在给定窗口内的时间戳。
+type DateGenerator struct {
+	rng randSource
+	cfg DateGenConfig
+}
+
+// NewDateGenerator 构造日期生成器；会对 cfg 做默认值补全。
+func NewDateGenerator(cfg DateGenConfig) *DateGenerator {
+	applyDateDefaults(&cfg)
+	return &DateGenerator{rng: newRand(cfg.RNG, uint64(cfg.Seed)), cfg: cfg}
+}
+
+// MarshalBinary 保存随机数生成器的当前位置（不含配置）。
+func (g *DateGenerator) MarshalBinary() ([]byte, error) { return g.rng.(randState).MarshalBinary() }
+
+// UnmarshalBinary 恢复 MarshalBinary 保存的位置；g 须以相同的配置（含 Seed）构造，
+// 此后生成的日期与保存时的生成器继续生成的相同。
+func (g *DateGenerator) UnmarshalBinary(data []byte) error {
+	return g.rng.(randState).UnmarshalBinary(data)
+}
+
+// Reference 返回补全默认值后的参考日期。
+func (g *DateGenerator) Reference() time.Time { return g.cfg.Reference }
+
+// BirthDate 随机生成一个出生日期，使其在参考日期时恰好为 age 周岁。
+func (g *DateGenerator) BirthDate(age int) time.Time {
+	ref := g.cfg.Reference
+	// 满足条件的出生日期区间为 (ref - (age+1) 年, ref - age 年]。
+	latest := addYears(ref, -age)
+	earliest := addYears(ref, -age-1).AddDate(0, 0, 1)
+	days := daysBetween(earliest, latest)
+	return earliest.AddDate(0, 0, g.rng.Intn(days+1))
+}
+
+// EnrollmentDate 返回出生于 birth 的学生的入学日期：在学年开始前一天满 EnrollmentAge 周岁的首个学年报到，
+// 但不晚于参考日期所在的学年（即不会生成尚未发生的入学）。
+func (g *DateGenerator) EnrollmentDate(birth time.Time) time.Time {
+	year := birth.Year() + g.cfg.EnrollmentAge
+	if AgeAt(birth, g.schoolYearStart(year).AddDate(0, 0, -1)) < g.cfg.EnrollmentAge {
+		year++
+	}
+	return g.registration(min(year, SchoolYear(g.cfg.Reference, g.cfg.SchoolYearStartMonth)))
+}
+
+// EnrollmentDateForGrade 返回参考日期时就读于 grade 年级（从 1 开始）的学生的入学日期。
+func (g *DateGenerator) EnrollmentDateForGrade(grade int) time.Time {
+	return g.registration(SchoolYear(g.cfg.Reference, g.cfg.SchoolYearStartMonth) - max(grade, 1) + 1)
+}
+
+// Timestamp 返回 [from, to) 内均匀分布、精确到秒的时间戳，换算到配置的时区；from 与 to 颠倒时自动交换，相等时返回 from。
+func (g *DateGenerator) Timestamp(from, to time.Time) time.Time {
+	if to.Before(from) {
+		from, to = to, from
+	}
+	span := int64(to.Sub(from) / time.Second)
+	if span <= 0 {
+		return from.In(g.cfg.Location)
+	}
+	return from.Add(time.Duration(g.rng.Int63n(span)) * time.Second).In(g.cfg.Location)
+}
+
+// registration 返回学年 year 的开学报到日期（首月第 1~RegistrationDays 天），且不晚于参考日期。
+func (g *DateGenerator) registration(year int) time.Time {
+	start := g.schoolYearStart(year)
+	d := start.AddDate(0, 0, g.rng.Intn(g.cfg.RegistrationDays))
+	if d.After(g.cfg.Reference) && !start.After(g.cfg.Reference) {
+		d = g.cfg.Reference
+	}
+	return d
+}
+
+func (g *DateGenerator) schoolYearStart(year int) time.Time {
+	return time.Date(year, g.cfg.SchoolYearStartMonth, 1, 0, 0, 0, 0, g.cfg.Location)
+}
+
+// AgeAt 返回出生于 birth 的人在 t 时的周岁（2 月 29 日出生者在平年的 3 月 1 日满岁）。
+func AgeAt(birth, t time.Time) int {
+	age := t.Year() - birth.Year()
+	if t.Month() < birth.Month() || (t.Month() == birth.Month() && t.Day() < birth.Day()) {
+		age--
+	}
+	return age
+}
+
+// SchoolYear 返回 t 所在学年的起始年份，如学年从 9 月开始时 2025-10-01 与 2026-06-30 都属于 2025 学年。
+func SchoolYear(t time.Time, startMonth time.Month) int {
+	if t.Month() < startMonth {
+		return t.Year() - 1
+	}
+	return t.Year()
+}
+
+// addYears 将 t 平移 n 年；2 月 29 日落到平年时取 2 月 28 日，以免 AddDate 溢出到 3 月。
+func addYears(t time.Time, n int) time.Time {
+	y := t.Year() + n
+	d := t.Day()
+	if t.Month() == time.February && d == 29 && !isLeap(y) {
+		d = 28
+	}
+	return time.Date(y, t.Month(), d, 0, 0, 0, 0, t.Location())
+}
+
+func isLeap(y int) bool { return y%4 == 0 && (y%100 != 0 || y%400 == 0) }
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// daysBetween 返回从 a 到 b 的整天数（二者均为当日零点）。
+func daysBetween(a, b time.Time) int {
+	ua := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	ub := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(ub.Sub(ua).Hours() / 24)
+}
@@ -0,0 +1,90 @@
+package generator_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/xianyudd/hanzi-data-kit/generator"
+)
+
+func TestDateGenerator_BirthAndEnrollment(t *testing.T) {
+	cst := time.FixedZone("CST", 8*3600)
+	for _, ref := range []time.Time{
+		time.Date(2025, 9, 1, 0, 0, 0, 0, cst),
+		time.Date(2024, 2, 29, 0, 0, 0, 0, cst),
+		time.Date(2026, 3, 15, 0, 0, 0, 0, cst),
+	} {
+		g1 := generator.NewDateGenerator(generator.DateGenConfig{Seed: 5, Reference: ref})
+		g2 := generator.NewDateGenerator(generator.DateGenConfig{Seed: 5, Reference: ref})
+		for i := range 500 {
+			age := 18 + i%13
+			birth := g1.BirthDate(age)
+			if !birth.Equal(g2.BirthDate(age)) {
+				t.Fatalf("出生日期不可复现")
+			}
+			if got := generator.AgeAt(birth, ref); got != age {
+				t.Fatalf("ref=%s: 出生于 %s 的年龄 = %d, want %d", ref.Format(time.DateOnly), birth.Format(time.DateOnly), got, age)
+			}
+			enroll := g1.EnrollmentDate(birth)
+			g2.EnrollmentDate(birth)
+			if enroll.After(ref) || enroll.Month() != time.September || enroll.Day() > 7 {
+				t.Fatalf("ref=%s: 入学日期 %s 不合法", ref.Format(time.DateOnly), enroll.Format(time.DateOnly))
+			}
+			// 未到入学年龄的学生被限制在参考日期所在的学年入学，不检查其年龄。
+			clamped := enroll.Year() == generator.SchoolYear(ref, time.September)
+			if a := generator.AgeAt(birth, enroll.AddDate(0, 0, -enroll.Day())); a < 18 && !clamped {
+				t.Fatalf("出生于 %s 的学生在 %s 入学时未满 18 周岁", birth.Format(time.DateOnly), enroll.Format(time.DateOnly))
+			}
+		}
+	}
+}
+
+func TestDateGenerator_GradeAndTimestamp(t *testing.T) {
+	g := generator.NewDateGenerator(generator.DateGenConfig{Seed: 1, Reference: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)})
+	for grade, year := range map[int]int{1: 2025, 2: 2024, 3: 2023} {
+		if d := g.EnrollmentDateForGrade(grade); d.Year() != year || d.Month() != time.September {
+			t.Errorf("%d 年级入学日期 = %s, want %d 学年", grade, d.Format(time.DateOnly), year)
+		}
+	}
+
+	from := time.Date(2025, 6, 7, 1, 0, 0, 0, time.UTC)
+	to := from.Add(2 * time.Hour)
+	for range 200 {
+		ts := g.Timestamp(to, from) // 颠倒的区间会被交换
+		if ts.Before(from) || !ts.Before(to) {
+			t.Fatalf("时间戳 %s 不在窗口内", ts)
+		}
+		if _, off := ts.Zone(); off != 8*3600 {
+			t.Fatalf("时间戳应换算到东八区, offset = %d", off)
+		}
+	}
+}
+
+func TestExamSubmittedAt(t *testing.T) {
+	ds := generator.GenerateSchoolDataset(generator.SchoolGenConfig{Seed: 2, Schools: 1, ClassesPerSchool: 1, StudentsPerClass: 20})
+	plain, err := generator.GenerateExam(ds, generator.ExamGenConfig{Seed: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2025, 6, 7, 9, 0, 0, 0, time.FixedZone("CST", 8*3600))
+	timed, err := generator.GenerateExam(ds, generator.ExamGenConfig{Seed: 2, StartAt: start, Duration: 150 * time.Minute})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, r := range timed.Results {
+		if !plain.Results[i].SubmittedAt.IsZero() {
+			t.Fatal("未设置 StartAt 时不应生成交卷时间")
+		}
+		if r.Scores[0] != plain.Results[i].Scores[0] {
+			t.Fatal("生成交卷时间不应改变成绩")
+		}
+		if r.SubmittedAt.Before(start.Add(75*time.Minute)) || r.SubmittedAt.After(start.Add(150*time.Minute)) {
+			t.Fatalf("交卷时间 %s 不在考试后半程内", r.SubmittedAt)
+		}
+	}
+	for _, s := range ds.Students {
+		if generator.AgeAt(s.BirthDate, time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)) != s.Age {
+			t.Fatalf("%s 的出生日期 %s 与年龄 %d 不一致", s.ID, s.BirthDate.Format(time.DateOnly), s.Age)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/xianyudd/hanzi-data-kit/model"
+)
+
+// ExamSubjectSpec 定义一个科目的满分与成绩分布（截断正态分布）。
+type ExamSubjectSpec struct {
+	Name string
+
+	// FullMarks 为满分；默认 100。
+	FullMarks float64
+
+	// Min/Max 为成绩闭区间；均为 0 时为 [0, FullMarks]。
+	Min, Max float64
+
+	// Mean/StdDev 为截断前正态分布的均值与标准差；默认分别为 0.7*FullMarks 与 0.15*FullMarks。
+	Mean, StdDev float64
+}
+
+// ExamGenConfig 定义多科考试成绩的生成策略。
+type ExamGenConfig struct {
+	// Seed 为随机种子；相同配置+相同 Seed 将生成相同序列的成绩。
+	Seed int64
+
+	// Subjects 为科目列表；为空时使用 语文/数学/英语（满分 150）与 物理/化学（满分 100）。
+	Subjects []ExamSubjectSpec
+
+	// Correlation 为任意两科之间的相关系数，取值 (-1, 1)；零值表示各科独立。
+	Correlation float64
+
+	// Correlations 为完整的相关矩阵（len(Subjects) 阶、对称、对角线为 1、正定）；非空时覆盖 Correlation。
+	Correlations [][]float64
+
+	// ScoreStep 为成绩步长；默认 0.5。
+	ScoreStep float64
+
+	// StartAt 为考试开始时间；非零时 GenerateExam 为每名考生生成 [StartAt+Duration/2, StartAt+Duration) 内的交卷时间。
+	StartAt time.Time
+
+	// Duration 为考试时长；默认 2 小时。
+	Duration time.Duration
+
+	// Location 为交卷时间的时区；为 nil 时使用东八区（UTC+8）。
+	Location *time.Location
+
+	// RNG 为随机数算法；为空时为 RNGMathRand。
+	RNG RNGAlgorithm
+}
+
+func applyExamDefaults(cfg *ExamGenConfig) {
+	if len(cfg.Subjects) == 0 {
+		cfg.Subjects = []ExamSubjectSpec{
+			{Name: "语文", FullMarks: 150}, {Name: "数学", FullMarks: 150}, {Name: "英语", FullMarks: 150},
+			{Name: "物理", FullMarks: 100}, {Name: "化学", FullMarks: 100},
+		}
+	} else {
+		cfg.Subjects = append([]ExamSubjectSpec(nil), cfg.Subjects...)
+	}
+	for i := range cfg.Subjects {
+		s := &cfg.Subjects[i]
+		if s.FullMarks <= 0 {
+			s.FullMarks = 100
+		}
+		if s.Min == 0 && s.Max == 0 {
+			s.Max = s.FullMarks
+		}
+		if s.Mean == 0 {
+			s.Mean = 0.7 * s.FullMarks
+		}
+		if s.StdDev <= 0 {
+			s.StdDev = 0.15 * s.FullMarks
+		}
+	}
+	if cfg.ScoreStep == 0 {
+		cfg.ScoreStep = 0.5
+	}
+	if cfg.Duration <= 0 {
+		cfg.Duration = 2 * time.Hour
+	}
+}
+
+// ExamScoreGenerator 按 ExamGenConfig 逐个生成考生的各科成绩。
+type ExamScoreGenerator struct {
+	rng  randSource
+	cfg  ExamGenConfig
+	chol [][]float64
+}
+
+// NewExamScoreGenerator 构造多科成绩生成器；科目区间非法、相关矩阵不是正定矩阵或 RNG 未知时返回 error。
+func NewExamScoreGenerator(cfg ExamGenConfig) (*ExamScoreGenerator, error) {
+	applyExamDefaults(&cfg)
+	alg, err := ParseRNGAlgorithm(string(cfg.RNG))
+	if err != nil {
+		return nil, err
+	}
+	cfg.RNG = alg
+	for _, s := range cfg.Subjects {
+		if s.Min > s.Max || s.Min < 0 || s.Max > s.FullMarks {
+			return nil, fmt.Errorf("科目 %s 的成绩区间 [%v, %v] 不在 [0, %v] 内", s.Name, s.Min, s.Max, s.FullMarks)
+		}
+	}
+	corr, err := correlationMatrix(cfg)
+	if err != nil {
+		return nil, err
+	}
+	chol, err := cholesky(corr)
+	if err != nil {
+		return nil, err
+	}
+	return &ExamScoreGenerator{rng: newRand(alg, uint64(cfg.Seed)), cfg: cfg, chol: chol}, nil
+}
+
+// Subjects 返回补全默认值后的科目及满分。
+func (g *ExamScoreGenerator) Subjects() []model.ExamSubject {
+	out := make([]model.ExamSubject, len(g.cfg.Subjects))
+	for i, s := range g.cfg.Subjects {
+		out[i] = model.ExamSubject{Name: s.Name, FullMarks: s.FullMarks}
+	}
+	return out
+}
+
+// Next 生成一名考生的各科成绩，顺序与 Subjects 一致。
+// 先由相关矩阵的 Cholesky 分解得到相关的标准正态变量，再按各科均值/标准差缩放、截断到区间并量化到步长。
+func (g *ExamScoreGenerator) Next() []float64 {
+	k := len(g.cfg.Subjects)
+	z := make([]float64, k)
+	for i := range z {
+		z[i] = g.rng.NormFloat64()
+	}
+	scores := make([]float64, k)
+	for i, s := range g.cfg.Subjects {
+		x := 0.0
+		for j := 0; j <= i; j++ {
+			x += g.chol[i][j] * z[j]
+		}
+		v := math.Min(math.Max(s.Mean+s.StdDev*x, s.Min), s.Max)
+		scores[i] = quantizeStep(v, g.cfg.ScoreStep)
+	}
+	return scores
+}
+
+// GenerateExam 为 ds 中的每名学生生成一次考试的各科成绩（cfg.StartAt 非零时另生成交卷时间）；
+// 派生字段（总分、排名等）由 rank.ScoreExam 计算。
+func GenerateExam(ds *model.SchoolDataset, cfg ExamGenConfig) (*model.Exam, error) {
+	g, err := NewExamScoreGenerator(cfg)
+	if err != nil {
+		return nil, err
+	}
+	classSchool := make(map[string]string, len(ds.Classes))
+	for _, c := range ds.Classes {
+		classSchool[c.ID] = c.SchoolID
+	}
+	var dates *DateGenerator
+	if !g.cfg.StartAt.IsZero() {
+		// 交卷时间使用独立的随机序列，是否生成都不影响成绩。
+		dates = NewDateGenerator(DateGenConfig{Seed: StreamSeed(cfg.Seed, "submitted_at"), Location: cfg.Location, RNG: g.cfg.RNG})
+	}
+	exam := &model.Exam{Subjects: g.Subjects(), Results: make([]model.ExamResult, len(ds.Students))}
+	for i, s := range ds.Students {
+		exam.Results[i] = model.ExamResult{
+			StudentID: s.ID,
+			Name:      s.Name,
+			ClassID:   s.ClassID,
+			SchoolID:  classSchool[s.ClassID],
+			Scores:    g.Next(),
+		}
+		if dates != nil {
+			end := g.cfg.StartAt.Add(g.cfg.Duration)
+			exam.Results[i].SubmittedAt = dates.Timestamp(end.Add(-g.cfg.Duration/2), end)
+		}
+	}
+	return exam, nil
+}
+
+// correlationMatrix 返回配置对应的相关矩阵并校验其形状与取值。
+func correlationMatrix(cfg ExamGenConfig) ([][]float64, error) {
+	k := len(cfg.Subjects)
+	if cfg.Correlations == nil {
+		if cfg.Correlation <= -1 || cfg.Correlation >= 1 {
+			return nil, fmt.Errorf("相关系数必须在 (-1, 1) 内, 实际为 %v", cfg.Correlation)
+		}
+		m := make([][]float64, k)
+		for i := range m {
+			m[i] = make([]float64, k)
+			for j := range m[i] {
+				m[i][j] = cfg.Correlation
+			}
+			m[i][i] = 1
+		}
+		return m, nil
+	}
+
+	m := cfg.Correlations
+	if len(m) != k {
+		return nil, fmt.Errorf("相关矩阵应为 %d 阶, 实际有 %d 行", k, len(m))
+	}
+	for i := range m {
+		if len(m[i]) != k {
+			return nil, fmt.Errorf("相关矩阵第 %d 行应有 %d 列, 实际为 %d", i+1, k, len(m[i]))
+		}
+		if m[i][i] != 1 {
+			return nil, fmt.Errorf("相关矩阵对角线必须为 1, 第 %d 行为 %v", i+1, m[i][i])
+		}
+		for j := range m[i] {
+			if m[i][j] < -1 || m[i][j] > 1 || m[i][j] != m[j][i] {
+				return nil, fmt.Errorf("相关矩阵必须对称且取值在 [-1, 1] 内: [%d][%d]=%v", i+1, j+1, m[i][j])
+			}
+		}
+	}
+	return m, nil
+}
+
+var errNotPositiveDefinite = errors.New("相关矩阵不是正定矩阵(例如相关系数过高或互相矛盾)")
+
+// cholesky 返回正定矩阵 a 的下三角分解 L（a = L·Lᵀ）。
+func cholesky(a [][]float64) ([][]float64, error) {
+	n := len(a)
+	l := make([][]float64, n)
+	for i := range l {
+		l[i] = make([]float64, n)
+		for j := 0; j <= i; j++ {
+			sum := a[i][j]
+			for k := 0; k < j; k++ {
+				sum -= l[i][k] * l[j][k]
+			}
+			if i == j {
+				if sum <= 1e-12 {
+					return nil, errNotPositiveDefinite
+				}
+				l[i][i] = math.Sqrt(sum)
+			} else {
+				l[i][j] = sum / l[j][j]
+			}
+		}
+	}
+	return l, nil
+}
@@ -0,0 +1,130 @@
+package generator_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/xianyudd/hanzi-data-kit/generator"
+)
+
+// pearson 返回两组数据的样本相关系数。
+func pearson(x, y []float64) float64 {
+	n := float64(len(x))
+	var sx, sy, sxx, syy, sxy float64
+	for i := range x {
+		sx += x[i]
+		sy += y[i]
+		sxx += x[i] * x[i]
+		syy += y[i] * y[i]
+		sxy += x[i] * y[i]
+	}
+	return (n*sxy - sx*sy) / math.Sqrt((n*sxx-sx*sx)*(n*syy-sy*sy))
+}
+
+func TestExamScoreGenerator_Correlation(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  generator.ExamGenConfig
+		want [3]float64 // 语文-数学, 语文-英语, 数学-英语
+	}{
+		{"独立", generator.ExamGenConfig{}, [3]float64{0, 0, 0}},
+		{"统一相关", generator.ExamGenConfig{Correlation: 0.6}, [3]float64{0.6, 0.6, 0.6}},
+		{"相关矩阵", generator.ExamGenConfig{Correlations: [][]float64{
+			{1, 0.8, -0.3},
+			{0.8, 1, 0},
+			{-0.3, 0, 1},
+		}}, [3]float64{0.8, -0.3, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.Seed = 1
+			tt.cfg.Subjects = []generator.ExamSubjectSpec{
+				{Name: "语文", FullMarks: 150, Mean: 90, StdDev: 15},
+				{Name: "数学", FullMarks: 150, Mean: 90, StdDev: 15},
+				{Name: "英语", FullMarks: 150, Mean: 90, StdDev: 15},
+			}
+			g, err := generator.NewExamScoreGenerator(tt.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cols := make([][]float64, 3)
+			for range 20000 {
+				for j, s := range g.Next() {
+					if s < 0 || s > 150 {
+						t.Fatalf("成绩越界: %v", s)
+					}
+					cols[j] = append(cols[j], s)
+				}
+			}
+			got := [3]float64{pearson(cols[0], cols[1]), pearson(cols[0], cols[2]), pearson(cols[1], cols[2])}
+			for i := range got {
+				if math.Abs(got[i]-tt.want[i]) > 0.05 {
+					t.Errorf("相关系数 = %.3f, want %.3f", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestExamScoreGenerator_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  generator.ExamGenConfig
+	}{
+		{"相关系数为 1", generator.ExamGenConfig{Correlation: 1}},
+		{"非正定", generator.ExamGenConfig{
+			Subjects:     []generator.ExamSubjectSpec{{Name: "a"}, {Name: "b"}, {Name: "c"}},
+			Correlations: [][]float64{{1, 0.9, -0.9}, {0.9, 1, 0.9}, {-0.9, 0.9, 1}},
+		}},
+		{"阶数不符", generator.ExamGenConfig{Correlations: [][]float64{{1}}}},
+		{"区间超出满分", generator.ExamGenConfig{Subjects: []generator.ExamSubjectSpec{{Name: "a", FullMarks: 100, Max: 120}}}},
+	}
+	for _, tt := range tests {
+		if _, err := generator.NewExamScoreGenerator(tt.cfg); err == nil {
+			t.Errorf("%s: 应返回 error", tt.name)
+		}
+	}
+}
+
+func TestGenerateExam(t *testing.T) {
+	ds := generator.GenerateSchoolDataset(generator.SchoolGenConfig{Seed: 5, StudentsPerClass: 3})
+	exam, err := generator.GenerateExam(ds, generator.ExamGenConfig{Seed: 5, Correlation: 0.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(exam.Results) != len(ds.Students) || len(exam.Subjects) != 5 {
+		t.Fatalf("results = %d, subjects = %d", len(exam.Results), len(exam.Subjects))
+	}
+	for _, r := range exam.Results {
+		if r.SchoolID == "" || len(r.Scores) != 5 {
+			t.Fatalf("result = %+v", r)
+		}
+	}
+}
+
+func TestGenerateExam_SubmittedAtUsesOwnStream(t *testing.T) {
+	const seed = 5
+	start := time.Date(2025, 6, 7, 9, 0, 0, 0, time.UTC)
+	ds := generator.GenerateSchoolDataset(generator.SchoolGenConfig{Seed: seed, StudentsPerClass: 3})
+	exam, err := generator.GenerateExam(ds, generator.ExamGenConfig{Seed: seed, StartAt: start, Location: time.UTC})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 交卷时间使用由 Seed 派生的独立序列，而不是重放成绩序列。
+	from, to := start.Add(time.Hour), start.Add(2*time.Hour)
+	own := generator.NewDateGenerator(generator.DateGenConfig{Seed: generator.StreamSeed(seed, "submitted_at"), Location: time.UTC})
+	replay := generator.NewDateGenerator(generator.DateGenConfig{Seed: seed, Location: time.UTC})
+	same := true
+	for _, r := range exam.Results[:5] {
+		if want := own.Timestamp(from, to); !r.SubmittedAt.Equal(want) {
+			t.Fatalf("%s 的交卷时间 = %v, want %v", r.StudentID, r.SubmittedAt, want)
+		}
+		same = same && r.SubmittedAt.Equal(replay.Timestamp(from, to))
+	}
+	if same {
+		t.Fatal("交卷时间序列与以 Seed 直接初始化的序列相同")
+	}
+}
@@ -0,0 +1,154 @@
+package generator_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"flag"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/xianyudd/hanzi-data-kit/generator"
+	"github.com/xianyudd/hanzi-data-kit/model"
+)
+
+var update = flag.Bool("update", false, "重新生成 testdata/golden 下的文件")
+
+// TestGolden 保证相同 Fingerprint 生成逐字节相同的数据。
+// 若有意改变了生成结果，应递增 generator.GeneratorVersion，再用 go test ./generator -run TestGolden -update 更新文件。
+func TestGolden(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     generator.StudentGenConfig
+		address bool
+	}{
+		{"pcg", generator.StudentGenConfig{Seed: 42, RNG: generator.RNGPCG}, false},
+		{"chacha8", generator.StudentGenConfig{Seed: 42, RNG: generator.RNGChaCha8}, false},
+		{"splitmix64", generator.StudentGenConfig{Seed: 42, RNG: generator.RNGSplitMix64}, false},
+		{"pcg_per_row", generator.StudentGenConfig{Seed: 7, RNG: generator.RNGPCG, PerRow: true}, false},
+		{"chacha8_address", generator.StudentGenConfig{Seed: 7, RNG: generator.RNGChaCha8}, true},
+		{"splitmix64_custom", generator.StudentGenConfig{
+			Seed: -1, RNG: generator.RNGSplitMix64, AgeMin: 6, AgeMax: 12, ScoreMin: 0, ScoreMax: 150,
+			Cities: []string{"拉萨", "喀什"}, TwoCharNameProb: 0.9,
+		}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := generator.NewStudentGenerator(tt.cfg)
+			var buf bytes.Buffer
+			buf.WriteString("# " + g.Fingerprint().String() + "\n")
+			w := csv.NewWriter(&buf)
+			for range 40 {
+				if tt.address {
+					stu, a := g.NextWithAddress()
+					w.Write(append(model.StudentToRowCN(stu), a.Province, a.District, a.Code, a.Street, a.HouseNumber))
+					continue
+				}
+				w.Write(model.StudentToRowCN(g.Next()))
+			}
+			w.Flush()
+			checkGolden(t, tt.name, buf.Bytes())
+		})
+	}
+}
+
+// TestGoldenExtras 覆盖联系方式、日期、地址与考试成绩生成器：它们与学生生成器取相同的 RNG 时同样逐字节稳定。
+func TestGoldenExtras(t *testing.T) {
+	for _, alg := range []generator.RNGAlgorithm{generator.RNGPCG, generator.RNGChaCha8, generator.RNGSplitMix64} {
+		t.Run(string(alg), func(t *testing.T) {
+			const seed = 42
+			g := generator.NewStudentGenerator(generator.StudentGenConfig{Seed: seed, RNG: alg})
+			contacts, err := generator.NewContactGenerator(generator.ContactGenConfig{Seed: seed, RNG: alg})
+			if err != nil {
+				t.Fatal(err)
+			}
+			dates := generator.NewDateGenerator(generator.DateGenConfig{Seed: seed, RNG: alg})
+			addrs, err := generator.NewAddressGenerator(generator.AddressGenConfig{Seed: seed, RNG: alg})
+			if err != nil {
+				t.Fatal(err)
+			}
+			exams, err := generator.NewExamScoreGenerator(generator.ExamGenConfig{Seed: seed, Correlation: 0.5, RNG: alg})
+			if err != nil {
+				t.Fatal(err)
+			}
+			start := time.Date(2025, 6, 7, 9, 0, 0, 0, time.UTC)
+
+			var buf bytes.Buffer
+			buf.WriteString("# " + g.Fingerprint().String() + "\n")
+			w := csv.NewWriter(&buf)
+			for range 40 {
+				stu := g.Next()
+				c := contacts.Next(stu.Name)
+				birth := dates.BirthDate(stu.Age)
+				row := []string{stu.Name, c.Phone, c.Email, c.Username,
+					birth.Format(time.DateOnly), dates.EnrollmentDate(birth).Format(time.DateOnly),
+					dates.Timestamp(start, start.Add(2*time.Hour)).Format(time.RFC3339)}
+				a := addrs.Next()
+				row = append(row, a.Province, a.City, a.District, a.Code, a.Street, a.HouseNumber)
+				for _, v := range exams.Next() {
+					row = append(row, strconv.FormatFloat(v, 'f', -1, 64))
+				}
+				w.Write(row)
+			}
+			w.Flush()
+			checkGolden(t, string(alg)+"_extras", buf.Bytes())
+		})
+	}
+}
+
+// checkGolden 比较 got 与 testdata/golden/<name>.csv；带 -update 时先以 got 覆盖该文件。
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", "golden", name+".csv")
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("读取 golden 文件失败(首次运行请加 -update): %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("%s 的输出与 golden 文件不一致；若为有意修改，请递增 GeneratorVersion 并加 -update 重新生成", name)
+	}
+}
+
+func TestRNGAlgorithms(t *testing.T) {
+	seen := map[model.Student]generator.RNGAlgorithm{}
+	for _, alg := range generator.RNGAlgorithms() {
+		if got, err := generator.ParseRNGAlgorithm(" " + string(alg) + " "); err != nil || got != alg {
+			t.Fatalf("ParseRNGAlgorithm(%s) = %v, %v", alg, got, err)
+		}
+		g := generator.NewStudentGenerator(generator.StudentGenConfig{Seed: 1, RNG: alg})
+		first := g.Next()
+		if prev, dup := seen[first]; dup {
+			t.Errorf("%s 与 %s 生成了相同的首条记录", alg, prev)
+		}
+		seen[first] = alg
+		for range 500 {
+			s := g.Next()
+			if s.Age < 18 || s.Age > 30 || s.Score < 0 || s.Score > 100 {
+				t.Fatalf("%s: 记录超出范围 %#v", alg, s)
+			}
+		}
+	}
+	if _, err := generator.ParseRNGAlgorithm("mt19937"); err == nil {
+		t.Error("未知算法应返回 error")
+	}
+
+	a := generator.NewStudentGenerator(generator.StudentGenConfig{Seed: 1, RNG: generator.RNGPCG}).Fingerprint()
+	b := generator.NewStudentGenerator(generator.StudentGenConfig{Seed: 1, RNG: generator.RNGPCG, AgeMin: 18, AgeMax: 30}).Fingerprint()
+	c := generator.NewStudentGenerator(generator.StudentGenConfig{Seed: 1, RNG: generator.RNGPCG, AgeMax: 31}).Fingerprint()
+	if a != b {
+		t.Errorf("显式写出默认值不应改变指纹: %s vs %s", a, b)
+	}
+	if a == c {
+		t.Errorf("配置不同指纹应不同: %s", a)
+	}
+}
@@ -0,0 +1,192 @@
+package generator
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+	"math/rand"
+	randv2 "math/rand/v2"
+	"strings"
+)
+
+// RNGAlgorithm 为生成器使用的随机数算法。
+//
+// 除 RNGMathRand 外，其余算法的抽样方法（整数区间、浮点、正态分布）都由本包实现，
+// 不依赖 math/rand 的内部细节；同一算法+Seed+配置（见 Fingerprint）在各版本间保证生成逐字节相同的数据，
+// 一旦需要改变输出，会递增 GeneratorVersion。
+//
+// 地址、日期、联系方式、考试等生成器与学生生成器一起使用时应取相同的算法（见 StudentGenConfig.RNG）。
+type RNGAlgorithm string
+
+const (
+	// RNGMathRand 为 math/rand.NewSource（默认），保留以兼容已有数据。
+	RNGMathRand RNGAlgorithm = "mathrand"
+	// RNGPCG 为 math/rand/v2 的 PCG-DXSM。
+	RNGPCG RNGAlgorithm = "pcg"
+	// RNGChaCha8 为 math/rand/v2 的 ChaCha8（密码学强度，较慢）。
+	RNGChaCha8 RNGAlgorithm = "chacha8"
+	// RNGSplitMix64 为 SplitMix64（最快，状态只有 64 位）。
+	RNGSplitMix64 RNGAlgorithm = "splitmix64"
+)
+
+// RNGAlgorithms 返回全部可选的算法。
+func RNGAlgorithms() []RNGAlgorithm {
+	return []RNGAlgorithm{RNGMathRand, RNGPCG, RNGChaCha8, RNGSplitMix64}
+}
+
+// ParseRNGAlgorithm 解析算法名称：mathrand|pcg|chacha8|splitmix64；空串为 mathrand。
+func ParseRNGAlgorithm(s string) (RNGAlgorithm, error) {
+	a := RNGAlgorithm(strings.ToLower(strings.TrimSpace(s)))
+	switch a {
+	case "":
+		return RNGMathRand, nil
+	case RNGMathRand, RNGPCG, RNGChaCha8, RNGSplitMix64:
+		return a, nil
+	}
+	return "", fmt.Errorf("未知的随机数算法 %q(可选 mathrand|pcg|chacha8|splitmix64)", s)
+}
+
+// randSource 为生成器所需的随机数方法；*rand.Rand 与 stableRand 都实现它。
+type randSource interface {
+	Intn(n int) int
+	Int63n(n int64) int64
+	Float64() float64
+	NormFloat64() float64
+}
+
+// randState 为可快照的随机数生成器：MarshalBinary 保存当前位置，UnmarshalBinary 恢复到该位置。
+// newRand 返回的生成器都实现它；恢复时须先以相同的算法与 seed 构造。
+type randState interface {
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+}
+
+// newRand 返回以 seed 初始化的算法 alg 的随机数生成器；alg 未知时 panic（应先经 ParseRNGAlgorithm 校验）。
+func newRand(alg RNGAlgorithm, seed uint64) randSource {
+	switch alg {
+	case RNGMathRand, "":
+		src := &countingSource{seed: int64(seed)}
+		src.Seed(src.seed)
+		return &legacyRand{Rand: rand.New(src), src: src}
+	case RNGPCG:
+		return &stableRand{src: randv2.NewPCG(seed, mix64(seed))}
+	case RNGChaCha8:
+		// 用 SplitMix64 将 64 位种子扩展为 32 字节密钥。
+		var key [32]byte
+		sm := splitMix64{state: seed}
+		for i := 0; i < len(key); i += 8 {
+			binary.LittleEndian.PutUint64(key[i:], sm.Uint64())
+		}
+		return &stableRand{src: randv2.NewChaCha8(key)}
+	case RNGSplitMix64:
+		return &stableRand{src: &splitMix64{state: seed}}
+	}
+	panic(fmt.Sprintf("未知的随机数算法 %q", alg))
+}
+
+// stableRand 在 64 位随机源之上实现 randSource。各方法的算法固定，不随 Go 版本变化：
+// 区间整数使用 Lemire 无偏乘法，浮点数取高 53 位，正态分布使用 Marsaglia 极坐标法。
+type stableRand struct {
+	src stableSource
+}
+
+// stableSource 为 stableRand 的随机源；math/rand/v2 的 PCG、ChaCha8 与 splitMix64 都实现它。
+type stableSource interface {
+	Uint64() uint64
+	randState
+}
+
+func (r *stableRand) MarshalBinary() ([]byte, error) { return r.src.MarshalBinary() }
+
+func (r *stableRand) UnmarshalBinary(data []byte) error { return r.src.UnmarshalBinary(data) }
+
+func (r *stableRand) Uint64() uint64 { return r.src.Uint64() }
+
+// uint64n 返回 [0, n) 内的均匀整数（n > 0）。
+func (r *stableRand) uint64n(n uint64) uint64 {
+	hi, lo := bits.Mul64(r.src.Uint64(), n)
+	if lo < n {
+		thresh := -n % n
+		for lo < thresh {
+			hi, lo = bits.Mul64(r.src.Uint64(), n)
+		}
+	}
+	return hi
+}
+
+func (r *stableRand) Int63n(n int64) int64 {
+	if n <= 0 {
+		panic("invalid argument to Int63n")
+	}
+	return int64(r.uint64n(uint64(n)))
+}
+
+func (r *stableRand) Intn(n int) int {
+	if n <= 0 {
+		panic("invalid argument to Intn")
+	}
+	return int(r.uint64n(uint64(n)))
+}
+
+func (r *stableRand) Float64() float64 {
+	return float64(r.src.Uint64()>>11) / (1 << 53)
+}
+
+func (r *stableRand) NormFloat64() float64 {
+	for {
+		u := 2*r.Float64() - 1
+		v := 2*r.Float64() - 1
+		if s := u*u + v*v; s > 0 && s < 1 {
+			return u * math.Sqrt(-2*math.Log(s)/s)
+		}
+	}
+}
+
+// legacyRand 为 RNGMathRand 使用的 *rand.Rand，额外记录随机源被抽取的次数，使其位置也可以快照。
+type legacyRand struct {
+	*rand.Rand
+	src *countingSource
+}
+
+// countingSource 包装 math/rand.NewSource 并记录随机源被调用的次数。Float64、Int31n、NormFloat64
+// 等抽样可能在内部重试而多次调用随机源，但计数发生在随机源这一层，且 Int63 与 Uint64 各推进一步，
+// 所以恢复时按 draws 重放即可回到相同位置；包装本身不改变生成的序列。
+type countingSource struct {
+	src   rand.Source64
+	seed  int64
+	draws uint64
+}
+
+func (s *countingSource) Int63() int64 { s.draws++; return s.src.Int63() }
+
+func (s *countingSource) Uint64() uint64 { s.draws++; return s.src.Uint64() }
+
+func (s *countingSource) Seed(seed int64) {
+	s.src, s.seed, s.draws = rand.NewSource(seed).(rand.Source64), seed, 0
+}
+
+const legacyStatePrefix = "mathrand:"
+
+// MarshalBinary 保存 seed 与已抽取次数；math/rand 的内部状态不可导出。
+func (r *legacyRand) MarshalBinary() ([]byte, error) {
+	b := []byte(legacyStatePrefix)
+	b = binary.BigEndian.AppendUint64(b, uint64(r.src.seed))
+	return binary.BigEndian.AppendUint64(b, r.src.draws), nil
+}
+
+// UnmarshalBinary 以记录的 seed 重新初始化并空转相同次数，耗时与已抽取次数成正比（每百万次约数毫秒）。
+func (r *legacyRand) UnmarshalBinary(data []byte) error {
+	rest, ok := bytes.CutPrefix(data, []byte(legacyStatePrefix))
+	if !ok || len(rest) != 16 {
+		return errors.New("无效的 mathrand 随机数状态")
+	}
+	r.src.Seed(int64(binary.BigEndian.Uint64(rest)))
+	for range binary.BigEndian.Uint64(rest[8:]) {
+		r.src.Int63()
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+)
+
+// golden 为 2^64 / φ，SplitMix64 的步长。
+const golden = 0x9E3779B97F4A7C15
+
+// splitMix64 为 SplitMix64 随机源：状态只有一个 uint64，构造开销极小，适合为每一行单独创建。
+// 实现 rand.Source64。
+type splitMix64 struct{ state uint64 }
+
+func (s *splitMix64) Uint64() uint64 {
+	s.state += golden
+	return mix64(s.state)
+}
+
+func (s *splitMix64) Int63() int64 { return int64(s.Uint64() >> 1) }
+
+func (s *splitMix64) Seed(seed int64) { s.state = uint64(seed) }
+
+const splitMix64StatePrefix = "splitmix64:"
+
+func (s *splitMix64) MarshalBinary() ([]byte, error) {
+	return binary.BigEndian.AppendUint64([]byte(splitMix64StatePrefix), s.state), nil
+}
+
+func (s *splitMix64) UnmarshalBinary(data []byte) error {
+	rest, ok := bytes.CutPrefix(data, []byte(splitMix64StatePrefix))
+	if !ok || len(rest) != 8 {
+		return errors.New("无效的 splitmix64 随机数状态")
+	}
+	s.state = binary.BigEndian.Uint64(rest)
+	return nil
+}
+
+// mix64 为 SplitMix64 的输出混合函数（雪崩性好，相邻输入得到互不相关的输出）。
+func mix64(z uint64) uint64 {
+	z = (z ^ z>>30) * 0xBF58476D1CE4E5B9
+	z = (z ^ z>>27) * 0x94D049BB133111EB
+	return z ^ z>>31
+}
+
+// StreamSeed 由 seed 与随机序列的名称（表名、"dates" 等）派生出该序列的随机种子。
+// 与主生成器共用同一 Seed 的辅助生成器应使用派生种子，否则会与主生成器抽到相同的随机数。
+func StreamSeed(seed int64, stream string) int64 {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d/%s", seed, stream)
+	return int64(h.Sum64())
+}
+
+// rowSeed 即 hash(seed, i)：第 i 行随机源的初始状态。
+func rowSeed(seed int64, i int) uint64 {
+	return mix64(mix64(uint64(seed)) + uint64(i)*golden)
+}
+
+// rowRand 返回第 i 行专用的随机数生成器；只由 alg、seed 与 i 决定。
+// RNGMathRand 时沿用 SplitMix64 配合 math/rand 的抽样方法，以保持已有数据不变。
+func rowRand(alg RNGAlgorithm, seed int64, i int) randSource {
+	if alg == RNGMathRand {
+		return rand.New(&splitMix64{state: rowSeed(seed, i)})
+	}
+	return newRand(alg, rowSeed(seed, i))
+}
@@ -0,0 +1,186 @@
+package generator
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/xianyudd/hanzi-data-kit/model"
+)
+
+// SchoolGenConfig 定义学校关系数据的规模与生成策略。所有表由同一个 Seed 驱动。
+type SchoolGenConfig struct {
+	// Seed 为随机种子；相同配置+相同 Seed 将生成完全相同的各表数据。
+	Seed int64
+
+	// Schools 为学校数；默认 2。
+	Schools int
+
+	// ClassesPerSchool 为每所学校的班级数；默认 4。
+	ClassesPerSchool int
+
+	// StudentsPerClass 为每个班级的学生数；默认 30。
+	StudentsPerClass int
+
+	// TeachersPerSchool 为每所学校的教师数，按科目轮流分配；默认且至少为 len(Subjects)，保证每个科目都有人教。
+	TeachersPerSchool int
+
+	// GradeLevels 为年级数，班级按顺序轮流分配到各年级；默认 3。
+	GradeLevels int
+
+	// Subjects 为科目列表，每个班级每个科目开一门课；为空时使用 语文/数学/英语/物理/化学。
+	Subjects []string
+
+	// ScoreSpread 为单科成绩围绕学生能力值的标准差；默认 10。
+	ScoreSpread float64
+
+	// Student 控制学生（及教师）的姓名、年龄与成绩范围及随机数算法（RNG）；其中 Seed 被忽略，
+	// Cities 用作学校所在城市的候选列表。
+	Student StudentGenConfig
+
+	// Dates 控制学生出生日期与入学日期的生成（参考日期、时区、学年起始月份）；其中 Seed 与 RNG 被忽略
+	// （Seed 取 StreamSeed(Seed, "dates")，RNG 取 Student.RNG）。
+	// 日期使用独立的随机序列，不影响其他各列的取值。
+	Dates DateGenConfig
+}
+
+func applySchoolDefaults(cfg *SchoolGenConfig) {
+	if cfg.Schools <= 0 {
+		cfg.Schools = 2
+	}
+	if cfg.ClassesPerSchool <= 0 {
+		cfg.ClassesPerSchool = 4
+	}
+	if cfg.StudentsPerClass <= 0 {
+		cfg.StudentsPerClass = 30
+	}
+	if cfg.GradeLevels <= 0 {
+		cfg.GradeLevels = 3
+	}
+	if len(cfg.Subjects) == 0 {
+		cfg.Subjects = []string{"语文", "数学", "英语", "物理", "化学"}
+	}
+	cfg.TeachersPerSchool = max(cfg.TeachersPerSchool, len(cfg.Subjects))
+	if cfg.ScoreSpread <= 0 {
+		cfg.ScoreSpread = 10
+	}
+	cfg.Student.Seed = cfg.Seed
+	applyDefaults(&cfg.Student)
+	cfg.Dates.Seed = StreamSeed(cfg.Seed, "dates")
+	cfg.Dates.RNG = cfg.Student.RNG
+}
+
+// GenerateSchoolDataset 按配置生成一组外键一致的学校数据：
+// 每个班级属于一所学校，每名学生属于一个班级，每个班级每个科目一门课（由本校教该科目的教师任教），
+// 每名学生在本班每门课上有一条成绩。学生的 Student.Score 为其各科成绩的平均分。
+//
+// 编号格式：学校 SCH001、班级 CLS0001、教师 TCH0001、课程 CRS00001、学生 STU000001。
+func GenerateSchoolDataset(cfg SchoolGenConfig) *model.SchoolDataset {
+	applySchoolDefaults(&cfg)
+	g := NewStudentGenerator(cfg.Student)
+	rng := g.rng
+	dates := NewDateGenerator(cfg.Dates)
+	ds := &model.SchoolDataset{}
+
+	cityCount := map[string]int{}
+	for s := 1; s <= cfg.Schools; s++ {
+		city := pickOne(rng, cfg.Student.Cities)
+		cityCount[city]++
+		school := model.School{
+			ID:   fmt.Sprintf("SCH%03d", s),
+			Name: fmt.Sprintf("%s第%s中学", city, chineseNumber(cityCount[city])),
+			City: city,
+		}
+		ds.Schools = append(ds.Schools, school)
+
+		// 教师按科目轮流分配；bySubject 记录每个科目的任课教师。
+		bySubject := make(map[string][]string, len(cfg.Subjects))
+		for i := range cfg.TeachersPerSchool {
+			subject := cfg.Subjects[i%len(cfg.Subjects)]
+			t := model.Teacher{
+				ID:       fmt.Sprintf("TCH%04d", len(ds.Teachers)+1),
+				SchoolID: school.ID,
+				Name:     g.genName(),
+				Subject:  subject,
+			}
+			ds.Teachers = append(ds.Teachers, t)
+			bySubject[subject] = append(bySubject[subject], t.ID)
+		}
+
+		perLevel := map[int]int{}
+		for c := range cfg.ClassesPerSchool {
+			level := c%cfg.GradeLevels + 1
+			perLevel[level]++
+			class := model.Class{
+				ID:         fmt.Sprintf("CLS%04d", len(ds.Classes)+1),
+				SchoolID:   school.ID,
+				Name:       fmt.Sprintf("%s年级%d班", chineseNumber(level), perLevel[level]),
+				GradeLevel: level,
+			}
+			ds.Classes = append(ds.Classes, class)
+
+			// 同一科目有多名教师时，按班级轮流任教以平衡工作量。
+			courses := make([]model.Course, len(cfg.Subjects))
+			for i, subject := range cfg.Subjects {
+				teachers := bySubject[subject]
+				courses[i] = model.Course{
+					ID:        fmt.Sprintf("CRS%05d", len(ds.Courses)+i+1),
+					ClassID:   class.ID,
+					TeacherID: teachers[c%len(teachers)],
+					Subject:   subject,
+				}
+			}
+			ds.Courses = append(ds.Courses, courses...)
+
+			for range cfg.StudentsPerClass {
+				ds.Students = append(ds.Students, g.nextSchoolStudent(&cfg, dates, &ds.Enrollments, school, class, courses, len(ds.Students)+1))
+			}
+		}
+	}
+	return ds
+}
+
+// nextSchoolStudent 生成一名学生及其各科成绩：先抽取能力值，各科成绩在能力值附近正态波动。
+// 出生日期与年龄一致，入学日期由班级所在年级推算。
+func (g *StudentGenerator) nextSchoolStudent(cfg *SchoolGenConfig, dates *DateGenerator, enrollments *[]model.Enrollment, school model.School, class model.Class, courses []model.Course, seq int) model.SchoolStudent {
+	stu := g.Next()
+	stu.City = school.City
+	id := fmt.Sprintf("STU%06d", seq)
+
+	lo, hi := min(g.cfg.ScoreMin, g.cfg.ScoreMax), max(g.cfg.ScoreMin, g.cfg.ScoreMax)
+	ability := stu.Score
+	sum := 0.0
+	for _, c := range courses {
+		score := ability + g.rng.NormFloat64()*cfg.ScoreSpread
+		score = quantizeStep(math.Min(math.Max(score, lo), hi), g.cfg.ScoreStep)
+		*enrollments = append(*enrollments, model.Enrollment{StudentID: id, CourseID: c.ID, Score: score})
+		sum += score
+	}
+	stu.Score = quantizeStep(sum/float64(len(courses)), g.cfg.ScoreStep)
+	return model.SchoolStudent{
+		ID:             id,
+		ClassID:        class.ID,
+		Student:        stu,
+		BirthDate:      dates.BirthDate(stu.Age),
+		EnrollmentDate: dates.EnrollmentDateForGrade(class.GradeLevel),
+	}
+}
+
+// chineseNumber 将 1~99 转换为中文数字，如 3 → 三、12 → 十二、20 → 二十；其他值使用阿拉伯数字。
+func chineseNumber(n int) string {
+	const digits = "零一二三四五六七八九"
+	d := []rune(digits)
+	switch {
+	case n <= 0 || n >= 100:
+		return fmt.Sprint(n)
+	case n < 10:
+		return string(d[n])
+	case n == 10:
+		return "十"
+	case n < 20:
+		return "十" + string(d[n%10])
+	case n%10 == 0:
+		return string(d[n/10]) + "十"
+	default:
+		return string(d[n/10]) + "十" + string(d[n%10])
+	}
+}
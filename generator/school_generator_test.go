@@ -0,0 +1,135 @@
+package generator_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/xianyudd/hanzi-data-kit/generator"
+)
+
+func TestGenerateSchoolDataset_Cardinalities(t *testing.T) {
+	cfg := generator.SchoolGenConfig{
+		Seed:              1,
+		Schools:           3,
+		ClassesPerSchool:  5,
+		StudentsPerClass:  7,
+		TeachersPerSchool: 2, // 少于科目数时自动提升到科目数
+		Subjects:          []string{"语文", "数学", "英语"},
+	}
+	ds := generator.GenerateSchoolDataset(cfg)
+
+	tests := []struct {
+		table string
+		got   int
+		want  int
+	}{
+		{"schools", len(ds.Schools), 3},
+		{"classes", len(ds.Classes), 15},
+		{"teachers", len(ds.Teachers), 9},
+		{"courses", len(ds.Courses), 45},
+		{"students", len(ds.Students), 105},
+		{"enrollments", len(ds.Enrollments), 315},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %d, want %d", tt.table, tt.got, tt.want)
+		}
+	}
+}
+
+func TestGenerateSchoolDataset_ForeignKeys(t *testing.T) {
+	ds := generator.GenerateSchoolDataset(generator.SchoolGenConfig{Seed: 42})
+
+	schoolCity := map[string]string{}
+	for _, s := range ds.Schools {
+		schoolCity[s.ID] = s.City
+	}
+	classSchool := map[string]string{}
+	for _, c := range ds.Classes {
+		if _, ok := schoolCity[c.SchoolID]; !ok {
+			t.Fatalf("班级 %s 引用了不存在的学校 %s", c.ID, c.SchoolID)
+		}
+		classSchool[c.ID] = c.SchoolID
+	}
+	teachers := map[string][2]string{} // 教师编号 -> {学校编号, 科目}
+	for _, tc := range ds.Teachers {
+		teachers[tc.ID] = [2]string{tc.SchoolID, tc.Subject}
+	}
+	courseClass := map[string]string{}
+	for _, c := range ds.Courses {
+		tc, ok := teachers[c.TeacherID]
+		if !ok || tc[0] != classSchool[c.ClassID] || tc[1] != c.Subject {
+			t.Fatalf("课程 %+v 的教师 %v 不属于本校或不教该科目", c, tc)
+		}
+		courseClass[c.ID] = c.ClassID
+	}
+
+	studentClass := map[string]string{}
+	for _, s := range ds.Students {
+		school, ok := classSchool[s.ClassID]
+		if !ok {
+			t.Fatalf("学生 %s 引用了不存在的班级 %s", s.ID, s.ClassID)
+		}
+		if s.City != schoolCity[school] {
+			t.Errorf("学生 %s 城市 = %s, want %s", s.ID, s.City, schoolCity[school])
+		}
+		studentClass[s.ID] = s.ClassID
+	}
+
+	perStudent := map[string][]float64{}
+	for _, e := range ds.Enrollments {
+		if courseClass[e.CourseID] != studentClass[e.StudentID] {
+			t.Fatalf("成绩 %+v: 学生与课程不在同一班级", e)
+		}
+		perStudent[e.StudentID] = append(perStudent[e.StudentID], e.Score)
+	}
+	// 学生的得分为各科平均分（量化到 0.5）。
+	for _, s := range ds.Students {
+		scores := perStudent[s.ID]
+		sum := 0.0
+		for _, v := range scores {
+			sum += v
+		}
+		if mean := sum / float64(len(scores)); s.Score < mean-0.25 || s.Score > mean+0.25 {
+			t.Errorf("学生 %s 得分 = %v, 各科平均 = %v", s.ID, s.Score, mean)
+		}
+	}
+}
+
+func TestGenerateSchoolDataset_Reproducible(t *testing.T) {
+	cfg := generator.SchoolGenConfig{Seed: 9, Schools: 2, StudentsPerClass: 5}
+	a, b := generator.GenerateSchoolDataset(cfg), generator.GenerateSchoolDataset(cfg)
+	if !reflect.DeepEqual(a, b) {
+		t.Fatal("相同配置生成的数据不同")
+	}
+	cfg.Seed = 10
+	if c := generator.GenerateSchoolDataset(cfg); reflect.DeepEqual(a.Students, c.Students) {
+		t.Fatal("不同 Seed 生成了相同的学生")
+	}
+}
+
+func TestGenerateSchoolDataset_DatesUseOwnStream(t *testing.T) {
+	const seed = 42
+	ds := generator.GenerateSchoolDataset(generator.SchoolGenConfig{Seed: seed, Student: generator.StudentGenConfig{RNG: generator.RNGPCG}})
+
+	// 日期使用由 Seed 派生的独立序列，而不是重放学生序列。
+	own := generator.NewDateGenerator(generator.DateGenConfig{Seed: generator.StreamSeed(seed, "dates"), RNG: generator.RNGPCG})
+	replay := generator.NewDateGenerator(generator.DateGenConfig{Seed: seed, RNG: generator.RNGPCG})
+	grade := map[string]int{}
+	for _, c := range ds.Classes {
+		grade[c.ID] = c.GradeLevel
+	}
+	same := true
+	for _, s := range ds.Students[:5] {
+		want := own.BirthDate(s.Age)
+		if !s.BirthDate.Equal(want) {
+			t.Fatalf("%s 的出生日期 = %v, want %v", s.ID, s.BirthDate, want)
+		}
+		same = same && s.BirthDate.Equal(replay.BirthDate(s.Age))
+		own.EnrollmentDateForGrade(grade[s.ClassID])
+		replay.EnrollmentDateForGrade(grade[s.ClassID])
+	}
+	if same {
+		t.Fatal("日期序列与以 Seed 直接初始化的序列相同")
+	}
+}
@@ -0,0 +1,123 @@
+package generator_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/xianyudd/hanzi-data-kit/generator"
+	"github.com/xianyudd/hanzi-data-kit/model"
+)
+
+func TestStudentGeneratorSnapshot(t *testing.T) {
+	for _, alg := range generator.RNGAlgorithms() {
+		for _, perRow := range []bool{false, true} {
+			cfg := generator.StudentGenConfig{Seed: 11, RNG: alg, PerRow: perRow, UseRegions: true}
+			name := string(alg)
+			if perRow {
+				name += "/per_row"
+			}
+			t.Run(name, func(t *testing.T) {
+				type rec struct {
+					stu  model.Student
+					addr model.Address
+				}
+				next := func(g *generator.StudentGenerator, i int) rec {
+					if i%3 == 0 {
+						s, a := g.NextWithAddress()
+						return rec{s, a}
+					}
+					return rec{stu: g.Next()}
+				}
+				ref := generator.NewStudentGenerator(cfg)
+				var want []rec
+				for i := range 200 {
+					want = append(want, next(ref, i))
+				}
+
+				g := generator.NewStudentGenerator(cfg)
+				for i := range 120 {
+					next(g, i)
+				}
+				snap, err := g.Snapshot()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if snap.Count != 120 {
+					t.Errorf("Count = %d, want 120", snap.Count)
+				}
+				// 经 JSON 往返，模拟写入伴随文件后再读出。
+				data, err := json.Marshal(snap)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var decoded generator.StudentGenState
+				if err := json.Unmarshal(data, &decoded); err != nil {
+					t.Fatal(err)
+				}
+				restored, err := generator.RestoreStudentGenerator(decoded)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if restored.Fingerprint() != g.Fingerprint() {
+					t.Errorf("指纹不一致: %s vs %s", restored.Fingerprint(), g.Fingerprint())
+				}
+				for i := 120; i < 200; i++ {
+					if got := next(restored, i); !reflect.DeepEqual(got, want[i]) {
+						t.Fatalf("第 %d 条: got %+v, want %+v", i, got, want[i])
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestRestoreStudentGeneratorErrors(t *testing.T) {
+	snap, err := generator.NewStudentGenerator(generator.StudentGenConfig{Seed: 1, RNG: generator.RNGPCG}).Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		modify func(*generator.StudentGenState)
+	}{
+		{"version", func(s *generator.StudentGenState) { s.Version++ }},
+		{"rng algorithm", func(s *generator.StudentGenState) { s.Config.RNG = "mt19937" }},
+		{"missing rng state", func(s *generator.StudentGenState) { s.RNG = nil }},
+		{"rng state of another algorithm", func(s *generator.StudentGenState) { s.Config.RNG = generator.RNGSplitMix64 }},
+		{"negative count", func(s *generator.StudentGenState) { s.Count = -1 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := snap
+			tt.modify(&s)
+			if _, err := generator.RestoreStudentGenerator(s); err == nil {
+				t.Error("应返回 error")
+			}
+		})
+	}
+}
+
+func TestDateGeneratorMarshalBinary(t *testing.T) {
+	cfg := generator.DateGenConfig{Seed: 5}
+	ref := generator.NewDateGenerator(cfg)
+	g := generator.NewDateGenerator(cfg)
+	for age := range 50 {
+		ref.BirthDate(18 + age%10)
+		g.BirthDate(18 + age%10)
+	}
+	state, err := g.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := generator.NewDateGenerator(cfg)
+	if err := restored.UnmarshalBinary(state); err != nil {
+		t.Fatal(err)
+	}
+	for age := range 50 {
+		want := ref.BirthDate(18 + age%10)
+		if got := restored.BirthDate(18 + age%10); !got.Equal(want) {
+			t.Fatalf("恢复后第 %d 个日期为 %s, want %s", age, got, want)
+		}
+	}
+}
@@ -2,87 +2,177 @@ package generator
 
 import (
 	"github.com/xianyudd/hanzi-data-kit/model"
+	"github.com/xianyudd/hanzi-data-kit/region"
 	"math"
-	"math/rand"
+	"slices"
+	"sync"
 )
 
 // StudentGenConfig 定义学生数据生成策略。
 // 约定：所有随机数由 Seed 驱动，以便测试场景可复现。
 type StudentGenConfig struct {
 	// Seed 为随机种子；相同配置+相同 Seed 将生成相同序列的数据。
-	Seed int64
+	Seed int64 `json:"seed"`
 
 	// AgeMin/AgeMax 为闭区间 [min, max]。
-	AgeMin int
-	AgeMax int
+	AgeMin int `json:"age_min"`
+	AgeMax int `json:"age_max"`
 
 	// ScoreMin/ScoreMax 为闭区间 [min, max]。
-	ScoreMin float64
-	ScoreMax float64
+	ScoreMin float64 `json:"score_min"`
+	ScoreMax float64 `json:"score_max"`
 
 	// Cities 为候选城市列表；为空时会使用内置默认列表。
-	Cities []string
+	Cities []string `json:"cities,omitempty"`
 
 	// Surnames 为候选姓氏列表；为空时会使用内置默认列表。
-	Surnames []string
+	Surnames []string `json:"surnames,omitempty"`
 
 	// GivenNames1 为单字名候选；为空时会使用内置默认列表。
-	GivenNames1 []string
+	GivenNames1 []string `json:"given_names1,omitempty"`
 
 	// GivenNames2 用于拼双字名（从中抽两个字拼接）；为空时默认复用 GivenNames1。
-	GivenNames2 []string
+	GivenNames2 []string `json:"given_names2,omitempty"`
 
 	// TwoCharNameProb 控制生成双字名的概率（0~1）。默认 0.3。
-	TwoCharNameProb float64
+	TwoCharNameProb float64 `json:"two_char_name_prob"`
+
+	// UseRegions 为 true 时忽略 Cities，改为从行政区划表按人口加权抽取城市，
+	// 使 City 与 NextWithAddress 返回的地址层级一致。
+	UseRegions bool `json:"use_regions,omitzero"`
 
 	// ScoreStep 为得分步长；0.5 => 只会生成 x.0 或 x.5。
-	ScoreStep float64
+	ScoreStep float64 `json:"score_step"`
+
+	// RNG 为随机数算法；为空时为 RNGMathRand。需要跨 Go 版本稳定的数据（如 golden 文件）时应显式选择其他算法。
+	// 未知的算法会导致 NewStudentGenerator panic，请先用 ParseRNGAlgorithm 校验。
+	RNG RNGAlgorithm `json:"rng,omitempty"`
+
+	// PerRow 为 true 时按行确定：Next 依次返回 At(0)、At(1)……，
+	// 即第 i 条记录只由 hash(Seed, i) 决定，与按任意顺序、任意分段（如并行或断点续写）生成的结果一致。
+	// 注意：两种模式使用不同的随机序列，相同 Seed 下 PerRow 与否生成的数据不同。
+	PerRow bool `json:"per_row,omitzero"`
 }
 
 // StudentGenerator 是一个基于 StudentGenConfig 的学生数据生成器。
 type StudentGenerator struct {
-	rng *rand.Rand
+	rng randSource
 	cfg StudentGenConfig
+
+	// next 为 Next/NextWithAddress 已生成的记录数，PerRow 模式下即将要返回的行号。
+	next int
 }
 
+// defaultRegions 为不限省份的行政区划抽样器，在首次需要地址时创建，可被多个生成器并发使用。
+var defaultRegions = sync.OnceValue(func() *region.Sampler {
+	s, _ := region.NewSampler() // 不限省份时不会出错
+	return s
+})
+
 // NewStudentGenerator 构造一个学生数据生成器。
 // 注意：该函数会对 cfg 做“默认值补全”，以避免调用方遗漏配置导致 panic。
 func NewStudentGenerator(cfg StudentGenConfig) *StudentGenerator {
 	applyDefaults(&cfg)
 
 	return &StudentGenerator{
-		rng: rand.New(rand.NewSource(cfg.Seed)),
+		rng: newRand(cfg.RNG, uint64(cfg.Seed)),
 		cfg: cfg,
 	}
 }
 
+// Config 返回补全默认值后的配置；用它构造的生成器与 g 生成相同的数据。
+func (g *StudentGenerator) Config() StudentGenConfig {
+	cfg := g.cfg
+	cfg.Cities = slices.Clone(cfg.Cities)
+	cfg.Surnames = slices.Clone(cfg.Surnames)
+	cfg.GivenNames1 = slices.Clone(cfg.GivenNames1)
+	cfg.GivenNames2 = slices.Clone(cfg.GivenNames2)
+	return cfg
+}
+
 // Next 生成一条新的学生记录。
 // 生成的字段满足 cfg 中指定的范围与候选列表约束。
 func (g *StudentGenerator) Next() model.Student {
-	score := randFloat(g.rng, g.cfg.ScoreMin, g.cfg.ScoreMax)
-	score = quantizeStep(score, 0.5) // 关键：步长 0.5 => 只会有 .0 或 .5
+	if g.cfg.PerRow {
+		g.next++
+		return g.At(g.next - 1)
+	}
+	if g.cfg.UseRegions {
+		stu, _ := g.NextWithAddress()
+		return stu
+	}
+	g.next++
+	return g.student(g.rng)
+}
+
+// NextWithAddress 生成一条学生记录及其地址，City 取地址中地级市的简称（如 杭州）。
+// 无论是否设置 UseRegions，城市都来自行政区划表而非 Cities。
+func (g *StudentGenerator) NextWithAddress() (model.Student, model.Address) {
+	g.next++
+	if g.cfg.PerRow {
+		return g.AtWithAddress(g.next - 1)
+	}
+	return g.studentWithAddress(g.rng)
+}
+
+// At 返回第 i 条（从 0 开始）学生记录：只由 hash(Seed, i) 决定，不依赖也不改变 Next 的状态，可并发调用。
+// 因此任意行或任意区间都可以单独生成，例如直接作为 parser.WriteLargeCSV 的行回调：
+//
+//	parser.WriteLargeCSV(path, headers, n, func(i int) []string { return model.StudentToRowCN(g.At(i - 1)) })
+func (g *StudentGenerator) At(i int) model.Student {
+	if g.cfg.UseRegions {
+		stu, _ := g.AtWithAddress(i)
+		return stu
+	}
+	return g.student(rowRand(g.cfg.RNG, g.cfg.Seed, i))
+}
+
+// AtWithAddress 与 At 相同，但同时返回地址（见 NextWithAddress）。
+func (g *StudentGenerator) AtWithAddress(i int) (model.Student, model.Address) {
+	return g.studentWithAddress(rowRand(g.cfg.RNG, g.cfg.Seed, i))
+}
+
+// student 用 rng 生成一条学生记录。
+func (g *StudentGenerator) student(rng randSource) model.Student {
+	score := randFloat(rng, g.cfg.ScoreMin, g.cfg.ScoreMax)
+	score = quantizeStep(score, g.cfg.ScoreStep) // 默认步长 0.5 => 只会有 .0 或 .5
 
 	return model.Student{
-		Name:  g.genName(),
-		Age:   randInt(g.rng, g.cfg.AgeMin, g.cfg.AgeMax),
-		City:  pickOne(g.rng, g.cfg.Cities),
+		Name:  g.nameFrom(rng),
+		Age:   randInt(rng, g.cfg.AgeMin, g.cfg.AgeMax),
+		City:  pickOne(rng, g.cfg.Cities),
 		Score: score,
 	}
 }
 
+// studentWithAddress 用 rng 生成一条学生记录及其地址。
+func (g *StudentGenerator) studentWithAddress(rng randSource) (model.Student, model.Address) {
+	score := quantizeStep(randFloat(rng, g.cfg.ScoreMin, g.cfg.ScoreMax), g.cfg.ScoreStep)
+	name := g.nameFrom(rng)
+	age := randInt(rng, g.cfg.AgeMin, g.cfg.AgeMax)
+	addr := nextAddress(rng, defaultRegions())
+	return model.Student{Name: name, Age: age, City: cityName(addr), Score: score}, addr
+}
+
 // genName 生成中文姓名：姓 +（单字名|双字名）。
-// 双字名通过从 GivenNames2 中抽取两个字拼接而成。
-func (g *StudentGenerator) genName() string {
-	surname := pickOne(g.rng, g.cfg.Surnames)
-	if g.rng.Float64() < g.cfg.TwoCharNameProb {
-		a := pickOne(g.rng, g.cfg.GivenNames2)
-		b := pickOne(g.rng, g.cfg.GivenNames2)
+func (g *StudentGenerator) genName() string { return g.nameFrom(g.rng) }
+
+// nameFrom 用 rng 生成中文姓名；双字名通过从 GivenNames2 中抽取两个字拼接而成。
+func (g *StudentGenerator) nameFrom(rng randSource) string {
+	surname := pickOne(rng, g.cfg.Surnames)
+	if rng.Float64() < g.cfg.TwoCharNameProb {
+		a := pickOne(rng, g.cfg.GivenNames2)
+		b := pickOne(rng, g.cfg.GivenNames2)
 		return surname + a + b
 	}
-	return surname + pickOne(g.rng, g.cfg.GivenNames1)
+	return surname + pickOne(rng, g.cfg.GivenNames1)
 }
 
 func applyDefaults(cfg *StudentGenConfig) {
+	if cfg.RNG == "" {
+		cfg.RNG = RNGMathRand
+	}
+
 	// 默认年龄范围：更贴近“学生”语义；调用方可自行覆盖。
 	if cfg.AgeMin == 0 && cfg.AgeMax == 0 {
 		cfg.AgeMin, cfg.AgeMax = 18, 30
@@ -118,13 +208,13 @@ func applyDefaults(cfg *StudentGenConfig) {
 
 // pickOne 从非空切片中随机取一个元素。
 // 该函数假设 slice 非空（由 applyDefaults 保证）。
-func pickOne(rng *rand.Rand, xs []string) string {
+func pickOne(rng randSource, xs []string) string {
 	return xs[rng.Intn(len(xs))]
 }
 
 // randInt 返回闭区间 [min, max] 的随机整数。
 // 若 min/max 颠倒则自动交换，避免调用方配置错误导致异常。
-func randInt(rng *rand.Rand, min, max int) int {
+func randInt(rng randSource, min, max int) int {
 	if max < min {
 		min, max = max, min
 	}
@@ -136,7 +226,7 @@ func randInt(rng *rand.Rand, min, max int) int {
 
 // randFloat 返回闭区间 [min, max] 内的随机浮点。
 // 若 min/max 颠倒则自动交换。
-func randFloat(rng *rand.Rand, min, max float64) float64 {
+func randFloat(rng randSource, min, max float64) float64 {
 	if max < min {
 		min, max = max, min
 	}
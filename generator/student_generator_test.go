@@ -63,6 +63,20 @@ func TestStudentGenerator_ScoreIsStepOfHalf(t *testing.T) {
 	}
 }
 
+func TestStudentGenerator_ScoreStepIsHonoured(t *testing.T) {
+	for _, step := range []float64{1, 5} {
+		g := generator.NewStudentGenerator(generator.StudentGenConfig{Seed: 7, ScoreStep: step})
+		for i := 0; i < 200; i++ {
+			stu, _ := g.NextWithAddress()
+			for _, score := range []float64{g.At(i).Score, stu.Score} {
+				if q := score / step; q != float64(int64(q)) {
+					t.Fatalf("step=%v i=%d: score=%v 不是步长的整数倍", step, i, score)
+				}
+			}
+		}
+	}
+}
+
 func TestStudentToRowCN_ScoreOneDecimalAndHalfStep(t *testing.T) {
 	students := []model.Student{
 		{Name: "张三", Age: 22, City: "北京", Score: 62.0},
@@ -103,3 +117,49 @@ func TestStudentToRowCN_ScoreOneDecimalAndHalfStep(t *testing.T) {
 		}
 	}
 }
+
+func TestStudentGenerator_At(t *testing.T) {
+	cfg := generator.StudentGenConfig{Seed: 42, PerRow: true}
+	g := generator.NewStudentGenerator(cfg)
+
+	// 倒序、跳跃访问与顺序 Next 的结果一致。
+	const n = 200
+	want := make([]model.Student, n)
+	for i := n - 1; i >= 0; i-- {
+		want[i] = g.At(i)
+	}
+	seq := generator.NewStudentGenerator(cfg)
+	for i := range n {
+		if got := seq.Next(); got != want[i] {
+			t.Fatalf("Next 与 At(%d) 不一致:\n  got=%#v\n  want=%#v", i, got, want[i])
+		}
+	}
+	if g.At(5_000_000) != generator.NewStudentGenerator(cfg).At(5_000_000) {
+		t.Fatal("At 应只由 Seed 与行号决定")
+	}
+	if g.At(0) == g.At(1) && g.At(1) == g.At(2) {
+		t.Fatal("相邻行不应相同")
+	}
+	if other := generator.NewStudentGenerator(generator.StudentGenConfig{Seed: 43}); other.At(0) == g.At(0) && other.At(1) == g.At(1) {
+		t.Fatal("不同 Seed 应生成不同的数据")
+	}
+
+	// 并发访问安全（配合 -race 运行时有意义），地址与学生城市一致。
+	done := make(chan bool)
+	for w := range 4 {
+		go func() {
+			ok := true
+			for i := w; i < 400; i += 4 {
+				stu, addr := g.AtWithAddress(i)
+				again, _ := g.AtWithAddress(i)
+				ok = ok && stu == again && strings.HasPrefix(addr.City, stu.City)
+			}
+			done <- ok
+		}()
+	}
+	for range 4 {
+		if !<-done {
+			t.Fatal("AtWithAddress 结果不稳定或城市与地址不一致")
+		}
+	}
+}
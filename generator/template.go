@@ -0,0 +1,245 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// TemplateSpec 为模板文件（JSON）描述的一组数据表，用于在不写 Go 代码的情况下生成任意结构的测试数据。
+//
+// 示例：
+//
+//	{"tables": [
+//	  {"name": "classes", "rows": 4, "columns": [
+//	    {"name": "id", "gen": "seq:CLS%03d"},
+//	    {"name": "年级", "gen": "int:1..3"}]},
+//	  {"name": "students", "rows": 100, "columns": [
+//	    {"name": "姓名", "gen": "name:cn"},
+//	    {"name": "城市", "gen": "enum:北京=3|上海=2|杭州"},
+//	    {"name": "得分", "gen": "float:normal(75,10) step 0.5 in 0..100"},
+//	    {"name": "班级", "gen": "ref:classes.id"}]}]}
+//
+// 列的 gen 语法见 ColumnSpec。
+type TemplateSpec struct {
+	Tables []TableSpec `json:"tables"`
+}
+
+// TableSpec 描述模板中的一张表。
+type TableSpec struct {
+	// Name 为表名，也是 ref 引用与输出文件名使用的名称；不能为空，也不能含有路径分隔符或 ".."。
+	Name string `json:"name"`
+
+	// Rows 为行数，必须为正数。
+	Rows int `json:"rows"`
+
+	Columns []ColumnSpec `json:"columns"`
+}
+
+// ColumnSpec 描述一列：Name 为表头，Gen 为“类型:参数”形式的生成规则：
+//
+//	name:cn                                  中文姓名（与学生生成器使用相同的姓氏/名字表）
+//	int:18..30                               闭区间内的均匀整数
+//	float:60..100 [step 0.5]                 闭区间内的均匀浮点数
+//	float:normal(75,10) [step 0.5] [in 0..100]  正态分布，可按 in 截断
+//	enum:北京|上海  或  enum:北京=3|上海=1      等概率或按权重取值
+//	seq[:STU%06d]                            从 1 开始的序号，可带格式
+//	date:2000-01-01..2010-12-31              闭区间内的均匀日期（2006-01-02 格式）
+//	city                                     内置默认城市列表中的城市
+//	idcard[:18..30]                          18 位身份证号（真实区县代码、出生日期对应的周岁、校验位正确）
+//	phone[:masked]                           同列唯一的手机号（真实号段，可脱敏）
+//	email:<列名> / username:<列名>            由本行此前某个姓名列的拼音生成，同列唯一
+//	ref:<表名>.<列名>                        从另一张表的该列中随机取值（外键）
+//
+// float 未指定 step 时保留 2 位小数，指定时按 step 的小数位数输出。
+type ColumnSpec struct {
+	Name string `json:"name"`
+	Gen  string `json:"gen"`
+}
+
+// Table 为按模板生成的一张表。
+type Table struct {
+	Name    string
+	Headers []string
+	Rows    [][]string
+}
+
+// LoadTemplate 从 r 读取 JSON 模板并检查其结构（表名/列名重复、未知字段、gen 语法与 ref 引用等）。
+func LoadTemplate(r io.Reader) (TemplateSpec, error) {
+	var spec TemplateSpec
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&spec); err != nil {
+		return TemplateSpec{}, fmt.Errorf("解析模板失败: %w", err)
+	}
+	if _, err := compileTemplate(spec); err != nil {
+		return TemplateSpec{}, err
+	}
+	return spec, nil
+}
+
+// TemplateGenConfig 定义按模板生成数据时的随机性。
+type TemplateGenConfig struct {
+	// Seed 为随机种子；相同模板+相同 Seed 将生成相同的各表数据。
+	Seed int64
+
+	// RNG 为随机数算法；为空时为 RNGMathRand。
+	RNG RNGAlgorithm
+}
+
+// GenerateTemplate 按 spec 生成各表，返回顺序与 spec.Tables 一致；cfg.RNG 未知时返回 error。
+// 被 ref 引用的表先生成；每张表使用由 cfg.Seed 与表名派生的独立随机序列，
+// 因此相同 Seed 生成相同数据，且增删其他表不影响本表的取值（引用其他表的列除外）。
+func GenerateTemplate(spec TemplateSpec, cfg TemplateGenConfig) ([]Table, error) {
+	alg, err := ParseRNGAlgorithm(string(cfg.RNG))
+	if err != nil {
+		return nil, err
+	}
+	order, err := compileTemplate(spec)
+	if err != nil {
+		return nil, err
+	}
+	tables := make([]Table, len(spec.Tables))
+	index := make(map[string]int, len(spec.Tables))
+	for i, t := range spec.Tables {
+		index[t.Name] = i
+	}
+	for _, i := range order {
+		ts := spec.Tables[i]
+		g := NewStudentGenerator(StudentGenConfig{Seed: StreamSeed(cfg.Seed, ts.Name), RNG: alg})
+		cols := make([]columnGenerator, len(ts.Columns))
+		headers := make([]string, len(ts.Columns))
+		for j, c := range ts.Columns {
+			headers[j] = c.Name
+			cg, err := compileColumn(g, ts, c, func(table, col string) []string {
+				return tables[index[table]].column(col)
+			})
+			if err != nil {
+				return nil, err
+			}
+			cols[j] = cg
+		}
+		rows := make([][]string, ts.Rows)
+		for r := range rows {
+			row := make([]string, len(cols))
+			for j, cg := range cols {
+				row[j] = cg(r+1, row[:j])
+			}
+			rows[r] = row
+		}
+		tables[i] = Table{Name: ts.Name, Headers: headers, Rows: rows}
+	}
+	return tables, nil
+}
+
+// column 返回列 col 的全部取值；列不存在时返回 nil。
+func (t Table) column(col string) []string {
+	j := -1
+	for k, h := range t.Headers {
+		if h == col {
+			j = k
+		}
+	}
+	if j < 0 {
+		return nil
+	}
+	out := make([]string, len(t.Rows))
+	for i, r := range t.Rows {
+		out[i] = r[j]
+	}
+	return out
+}
+
+// compileTemplate 检查 spec，返回满足 ref 依赖的生成顺序（被引用的表在前，其余保持声明顺序）。
+func compileTemplate(spec TemplateSpec) ([]int, error) {
+	if len(spec.Tables) == 0 {
+		return nil, fmt.Errorf("模板中没有任何表")
+	}
+	index := make(map[string]int, len(spec.Tables))
+	for i, t := range spec.Tables {
+		if strings.TrimSpace(t.Name) == "" {
+			return nil, fmt.Errorf("第 %d 张表缺少名称", i+1)
+		}
+		// 表名会被用作输出文件名，不能借此写到输出目录之外。
+		if strings.ContainsAny(t.Name, `/\`) || strings.Contains(t.Name, "..") || !filepath.IsLocal(t.Name+".csv") {
+			return nil, fmt.Errorf("表名 %q 不能含有路径分隔符或 ..", t.Name)
+		}
+		if _, dup := index[t.Name]; dup {
+			return nil, fmt.Errorf("表名重复: %s", t.Name)
+		}
+		index[t.Name] = i
+	}
+
+	deps := make([][]int, len(spec.Tables))
+	checker := NewStudentGenerator(StudentGenConfig{})
+	for i, t := range spec.Tables {
+		if t.Rows <= 0 {
+			return nil, fmt.Errorf("表 %s: rows 必须为正数, 实际为 %d", t.Name, t.Rows)
+		}
+		if len(t.Columns) == 0 {
+			return nil, fmt.Errorf("表 %s: 没有任何列", t.Name)
+		}
+		seen := map[string]bool{}
+		for _, c := range t.Columns {
+			if c.Name == "" || seen[c.Name] {
+				return nil, fmt.Errorf("表 %s: 列名为空或重复: %q", t.Name, c.Name)
+			}
+			seen[c.Name] = true
+			// 仅做语法检查；ref 的目标在下面单独检查。
+			if _, err := compileColumn(checker, t, c, nil); err != nil {
+				return nil, err
+			}
+			target, col, ok := refTarget(c.Gen)
+			if !ok {
+				continue
+			}
+			j, exists := index[target]
+			if !exists {
+				return nil, fmt.Errorf("表 %s 列 %s: 引用的表 %s 不存在", t.Name, c.Name, target)
+			}
+			if !hasColumn(spec.Tables[j], col) {
+				return nil, fmt.Errorf("表 %s 列 %s: 表 %s 中没有列 %s", t.Name, c.Name, target, col)
+			}
+			deps[i] = append(deps[i], j)
+		}
+	}
+
+	// 深度优先拓扑排序；state: 0 未访问，1 访问中，2 已完成。
+	state := make([]int, len(spec.Tables))
+	var order []int
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case 1:
+			return fmt.Errorf("表之间存在循环引用: %s", spec.Tables[i].Name)
+		case 2:
+			return nil
+		}
+		state[i] = 1
+		for _, j := range deps[i] {
+			if err := visit(j); err != nil {
+				return err
+			}
+		}
+		state[i] = 2
+		order = append(order, i)
+		return nil
+	}
+	for i := range spec.Tables {
+		if err := visit(i); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+func hasColumn(t TableSpec, col string) bool {
+	for _, c := range t.Columns {
+		if c.Name == col {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,298 @@
+package generator
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/xianyudd/hanzi-data-kit/model"
+	"github.com/xianyudd/hanzi-data-kit/region"
+)
+
+// columnGenerator 生成某一列在第 row 行（1-based）的取值；prev 为本行此前各列已生成的值。
+type columnGenerator func(row int, prev []string) string
+
+// compileColumn 将列的 gen 规则编译为 columnGenerator，随机数取自 g.rng。
+// lookup 返回被 ref 引用的列的全部取值；为 nil 时（仅做语法检查）ref 列生成空串。
+func compileColumn(g *StudentGenerator, t TableSpec, c ColumnSpec, lookup func(table, col string) []string) (columnGenerator, error) {
+	cg, err := compileGen(g, t, c, lookup)
+	if err != nil {
+		return nil, fmt.Errorf("表 %s 列 %s: gen %q: %w", t.Name, c.Name, c.Gen, err)
+	}
+	return cg, nil
+}
+
+func compileGen(g *StudentGenerator, t TableSpec, c ColumnSpec, lookup func(table, col string) []string) (columnGenerator, error) {
+	rng := g.rng
+	kind, arg, _ := strings.Cut(strings.TrimSpace(c.Gen), ":")
+	arg = strings.TrimSpace(arg)
+	switch kind {
+	case "name":
+		if arg != "" && arg != "cn" {
+			return nil, fmt.Errorf("name 只支持 cn")
+		}
+		return func(int, []string) string { return g.genName() }, nil
+
+	case "int":
+		lo, hi, err := parseIntRange(arg)
+		if err != nil {
+			return nil, err
+		}
+		return func(int, []string) string { return strconv.Itoa(randInt(rng, lo, hi)) }, nil
+
+	case "float":
+		return compileFloat(rng, arg)
+
+	case "enum":
+		return compileEnum(rng, arg)
+
+	case "seq":
+		format := arg
+		if format == "" {
+			format = "%d"
+		}
+		if strings.Count(format, "%") != 1 || strings.Contains(fmt.Sprintf(format, 1), "%!") {
+			return nil, fmt.Errorf("seq 的格式须恰好包含一个整数占位符(如 STU%%06d)")
+		}
+		return func(row int, _ []string) string { return fmt.Sprintf(format, row) }, nil
+
+	case "date":
+		from, to, ok := strings.Cut(arg, "..")
+		if !ok {
+			return nil, fmt.Errorf("date 应形如 2000-01-01..2010-12-31")
+		}
+		a, err1 := time.Parse(model.DateLayout, strings.TrimSpace(from))
+		b, err2 := time.Parse(model.DateLayout, strings.TrimSpace(to))
+		if err1 != nil || err2 != nil || b.Before(a) {
+			return nil, fmt.Errorf("date 的起止日期无效(应为 2006-01-02 格式且起始不晚于结束)")
+		}
+		days := daysBetween(a, b)
+		return func(int, []string) string { return a.AddDate(0, 0, rng.Intn(days+1)).Format(model.DateLayout) }, nil
+
+	case "city":
+		return func(int, []string) string { return pickOne(rng, g.cfg.Cities) }, nil
+
+	case "idcard":
+		lo, hi := 18, 30
+		if arg != "" {
+			var err error
+			if lo, hi, err = parseIntRange(arg); err != nil {
+				return nil, err
+			}
+			if lo < 0 {
+				return nil, fmt.Errorf("idcard 的年龄不能为负数")
+			}
+		}
+		regions, _ := region.NewSampler()
+		dates := &DateGenerator{rng: rng}
+		applyDateDefaults(&dates.cfg)
+		return func(int, []string) string {
+			_, _, county := regions.Pick(rng)
+			birth := dates.BirthDate(randInt(rng, lo, hi))
+			return idCardNumber(county.Code, birth, rng.Intn(1000))
+		}, nil
+
+	case "phone":
+		if arg != "" && arg != "masked" {
+			return nil, fmt.Errorf("phone 只支持 masked 参数")
+		}
+		cg, err := newContactGenerator(rng, ContactGenConfig{MaskPhone: arg == "masked"})
+		if err != nil {
+			return nil, err
+		}
+		return func(int, []string) string { return cg.Phone() }, nil
+
+	case "email", "username":
+		src := -1
+		for j, other := range t.Columns {
+			if other.Name == c.Name {
+				break
+			}
+			if other.Name == arg {
+				src = j
+			}
+		}
+		if src < 0 {
+			return nil, fmt.Errorf("%s 须引用本表中位于其前面的姓名列, 找不到列 %q", kind, arg)
+		}
+		cg, err := newContactGenerator(rng, ContactGenConfig{})
+		if err != nil {
+			return nil, err
+		}
+		if kind == "email" {
+			return func(_ int, prev []string) string { return cg.Email(prev[src]) }, nil
+		}
+		return func(_ int, prev []string) string { return cg.Username(prev[src]) }, nil
+
+	case "ref":
+		table, col, ok := refTarget(c.Gen)
+		if !ok {
+			return nil, fmt.Errorf("ref 应形如 表名.列名")
+		}
+		if table == t.Name {
+			return nil, fmt.Errorf("ref 不能引用本表")
+		}
+		if lookup == nil {
+			return func(int, []string) string { return "" }, nil
+		}
+		values := lookup(table, col)
+		return func(int, []string) string { return pickOne(rng, values) }, nil
+
+	default:
+		return nil, fmt.Errorf("未知的类型 %q(可选 name|int|float|enum|seq|date|city|idcard|phone|email|username|ref)", kind)
+	}
+}
+
+// refTarget 解析 ref:<表名>.<列名>。
+func refTarget(gen string) (table, col string, ok bool) {
+	kind, arg, _ := strings.Cut(strings.TrimSpace(gen), ":")
+	if kind != "ref" {
+		return "", "", false
+	}
+	table, col, ok = strings.Cut(strings.TrimSpace(arg), ".")
+	return table, col, ok && table != "" && col != ""
+}
+
+// compileFloat 解析 "<分布> [step S] [in A..B]"，分布为 A..B（均匀）或 normal(均值,标准差)。
+func compileFloat(rng randSource, arg string) (columnGenerator, error) {
+	// normal(均值, 标准差) 的括号内可以有空格，因此先整体切出分布，再按空白切分修饰。
+	arg = strings.TrimSpace(arg)
+	dist, rest := arg, ""
+	if strings.HasPrefix(arg, "normal") {
+		if i := strings.Index(arg, ")"); i >= 0 {
+			dist, rest = arg[:i+1], arg[i+1:]
+		}
+	} else if i := strings.IndexFunc(arg, unicode.IsSpace); i >= 0 {
+		dist, rest = arg[:i], arg[i:]
+	}
+	if dist == "" {
+		return nil, fmt.Errorf("float 缺少取值范围或分布")
+	}
+	fields := strings.Fields(rest)
+	step, decimals := 0.0, 2
+	clampLo, clampHi := math.Inf(-1), math.Inf(1)
+	for i := 0; i < len(fields); i += 2 {
+		if i+1 >= len(fields) {
+			return nil, fmt.Errorf("%s 缺少参数", fields[i])
+		}
+		switch v := fields[i+1]; fields[i] {
+		case "step":
+			s, err := strconv.ParseFloat(v, 64)
+			if err != nil || s <= 0 {
+				return nil, fmt.Errorf("step 须为正数, 实际为 %q", v)
+			}
+			step, decimals = s, 0
+			if _, frac, ok := strings.Cut(v, "."); ok {
+				decimals = len(frac)
+			}
+		case "in":
+			lo, hi, err := parseFloatRange(v)
+			if err != nil {
+				return nil, err
+			}
+			clampLo, clampHi = lo, hi
+		default:
+			return nil, fmt.Errorf("未知的修饰 %q(可选 step|in)", fields[i])
+		}
+	}
+
+	var draw func() float64
+	if params, ok := strings.CutPrefix(dist, "normal"); ok {
+		params = strings.TrimSpace(params)
+		m, sd, ok := strings.Cut(strings.TrimSuffix(strings.TrimPrefix(params, "("), ")"), ",")
+		mean, err1 := strconv.ParseFloat(strings.TrimSpace(m), 64)
+		stddev, err2 := strconv.ParseFloat(strings.TrimSpace(sd), 64)
+		if !ok || !strings.HasPrefix(params, "(") || !strings.HasSuffix(params, ")") || err1 != nil || err2 != nil || stddev < 0 {
+			return nil, fmt.Errorf("normal 应形如 normal(75,10), 且标准差非负, 实际为 %q", dist)
+		}
+		draw = func() float64 { return mean + stddev*rng.NormFloat64() }
+	} else {
+		lo, hi, err := parseFloatRange(dist)
+		if err != nil {
+			return nil, err
+		}
+		draw = func() float64 { return randFloat(rng, lo, hi) }
+	}
+	return func(int, []string) string {
+		v := quantizeStep(math.Min(math.Max(draw(), clampLo), clampHi), step)
+		return strconv.FormatFloat(v, 'f', decimals, 64)
+	}, nil
+}
+
+// compileEnum 解析 "A|B|C" 或带权重的 "A=3|B=1"。
+func compileEnum(rng randSource, arg string) (columnGenerator, error) {
+	var values []string
+	var cum []float64
+	total := 0.0
+	for _, item := range strings.Split(arg, "|") {
+		v, w := strings.TrimSpace(item), 1.0
+		if name, weight, ok := strings.Cut(v, "="); ok {
+			f, err := strconv.ParseFloat(strings.TrimSpace(weight), 64)
+			if err != nil || f <= 0 {
+				return nil, fmt.Errorf("enum 的权重须为正数, 实际为 %q", weight)
+			}
+			v, w = strings.TrimSpace(name), f
+		}
+		if v == "" {
+			return nil, fmt.Errorf("enum 含有空的取值")
+		}
+		total += w
+		values = append(values, v)
+		cum = append(cum, total)
+	}
+	return func(int, []string) string {
+		x := rng.Float64() * total
+		for i, c := range cum {
+			if x < c {
+				return values[i]
+			}
+		}
+		return values[len(values)-1]
+	}, nil
+}
+
+func parseIntRange(s string) (int, int, error) {
+	a, b, ok := strings.Cut(s, "..")
+	lo, err1 := strconv.Atoi(strings.TrimSpace(a))
+	hi, err2 := strconv.Atoi(strings.TrimSpace(b))
+	if !ok || err1 != nil || err2 != nil || hi < lo {
+		return 0, 0, fmt.Errorf("整数区间应形如 18..30, 实际为 %q", s)
+	}
+	return lo, hi, nil
+}
+
+func parseFloatRange(s string) (float64, float64, error) {
+	a, b, ok := strings.Cut(s, "..")
+	lo, err1 := strconv.ParseFloat(strings.TrimSpace(a), 64)
+	hi, err2 := strconv.ParseFloat(strings.TrimSpace(b), 64)
+	if !ok || err1 != nil || err2 != nil || hi < lo {
+		return 0, 0, fmt.Errorf("数值区间应形如 60..100, 实际为 %q", s)
+	}
+	return lo, hi, nil
+}
+
+// idCardNumber 按 GB 11643 拼接 18 位公民身份号码：6 位区县代码 + 8 位出生日期 + 3 位顺序码 + 1 位校验码。
+func idCardNumber(county string, birth time.Time, seq int) string {
+	body := fmt.Sprintf("%s%s%03d", county, birth.Format("20060102"), seq)
+	return body + string(idCardCheckDigit(body))
+}
+
+// idCardCheckDigit 返回 17 位身份号码本体码的校验码（0~9 或 X）；body 长度不为 17 或含非数字时返回 0。
+func idCardCheckDigit(body string) byte {
+	weights := [17]int{7, 9, 10, 5, 8, 4, 2, 1, 6, 3, 7, 9, 10, 5, 8, 4, 2}
+	if len(body) != 17 {
+		return 0
+	}
+	sum := 0
+	for i := range 17 {
+		d := body[i]
+		if d < '0' || d > '9' {
+			return 0
+		}
+		sum += int(d-'0') * weights[i]
+	}
+	return "10X98765432"[sum%11]
+}
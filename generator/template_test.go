@@ -0,0 +1,198 @@
+package generator_test
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/xianyudd/hanzi-data-kit/contact"
+	"github.com/xianyudd/hanzi-data-kit/generator"
+	"github.com/xianyudd/hanzi-data-kit/region"
+)
+
+const testTemplate = `{"tables": [
+  {"name": "students", "rows": 300, "columns": [
+    {"name": "学号", "gen": "seq:STU%04d"},
+    {"name": "姓名", "gen": "name:cn"},
+    {"name": "年龄", "gen": "int:18..30"},
+    {"name": "城市", "gen": "enum:北京=3|上海"},
+    {"name": "得分", "gen": "float:normal(75,10) step 0.5 in 0..100"},
+    {"name": "身份证号", "gen": "idcard:20..20"},
+    {"name": "手机号", "gen": "phone"},
+    {"name": "用户名", "gen": "username:姓名"},
+    {"name": "班级", "gen": "ref:classes.id"}]},
+  {"name": "classes", "rows": 3, "columns": [
+    {"name": "id", "gen": "seq:CLS%03d"},
+    {"name": "入学", "gen": "date:2023-09-01..2023-09-07"}]}
+]}`
+
+func TestGenerateTemplate(t *testing.T) {
+	spec, err := generator.LoadTemplate(strings.NewReader(testTemplate))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tables, err := generator.GenerateTemplate(spec, generator.TemplateGenConfig{Seed: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, _ := generator.GenerateTemplate(spec, generator.TemplateGenConfig{Seed: 7})
+	for i := range tables {
+		for r := range tables[i].Rows {
+			if !slices.Equal(tables[i].Rows[r], again[i].Rows[r]) {
+				t.Fatalf("表 %s 第 %d 行不可复现", tables[i].Name, r+1)
+			}
+		}
+	}
+
+	students, classes := tables[0], tables[1]
+	if students.Name != "students" || len(students.Rows) != 300 || len(classes.Rows) != 3 {
+		t.Fatalf("表的顺序或行数错误: %s %d, %s %d", students.Name, len(students.Rows), classes.Name, len(classes.Rows))
+	}
+	ids := map[string]bool{}
+	for _, r := range classes.Rows {
+		ids[r[0]] = true
+		if r[1] < "2023-09-01" || r[1] > "2023-09-07" {
+			t.Fatalf("日期 %s 超出区间", r[1])
+		}
+	}
+	beijing := 0
+	phones := map[string]bool{}
+	for i, r := range students.Rows {
+		if want := "STU" + leftPad(i+1); r[0] != want {
+			t.Fatalf("学号 = %s, want %s", r[0], want)
+		}
+		if age, _ := strconv.Atoi(r[2]); age < 18 || age > 30 {
+			t.Fatalf("年龄 %s 超出区间", r[2])
+		}
+		if r[3] == "北京" {
+			beijing++
+		}
+		score, _ := strconv.ParseFloat(r[4], 64)
+		if score < 0 || score > 100 || score*2 != float64(int(score*2)) {
+			t.Fatalf("得分 %s 不满足区间或步长", r[4])
+		}
+		checkIDCard(t, r[5])
+		if err := contact.ValidatePhone(r[6]); err != nil || phones[r[6]] {
+			t.Fatalf("手机号 %s 非法或重复: %v", r[6], err)
+		}
+		phones[r[6]] = true
+		if err := contact.ValidateUsername(r[7]); err != nil {
+			t.Fatal(err)
+		}
+		if !ids[r[8]] {
+			t.Fatalf("外键 %s 不在 classes.id 中", r[8])
+		}
+	}
+	if beijing < 180 || beijing > 270 {
+		t.Errorf("权重 3:1 时北京出现 %d/300 次", beijing)
+	}
+}
+
+func leftPad(n int) string {
+	s := strconv.Itoa(n)
+	return strings.Repeat("0", 4-len(s)) + s
+}
+
+// checkIDCard 校验 18 位身份证号：区县代码真实存在、出生于 2004-09-02~2005-09-01（参考日期 2025-09-01 时 20 周岁）、校验码正确。
+func checkIDCard(t *testing.T, id string) {
+	t.Helper()
+	if len(id) != 18 {
+		t.Fatalf("身份证号 %s 长度错误", id)
+	}
+	// 东莞、中山等不设区的地级市以地级代码为前缀。
+	if d, ok := region.Lookup(id[:6]); !ok || d.Level == region.Province {
+		t.Fatalf("身份证号 %s 的区县代码不存在", id)
+	}
+	if birth := id[6:14]; birth < "20040902" || birth > "20050901" {
+		t.Fatalf("身份证号 %s 的出生日期与年龄不符", id)
+	}
+	weights := []int{7, 9, 10, 5, 8, 4, 2, 1, 6, 3, 7, 9, 10, 5, 8, 4, 2}
+	sum := 0
+	for i, w := range weights {
+		sum += int(id[i]-'0') * w
+	}
+	if want := "10X98765432"[sum%11]; id[17] != want {
+		t.Fatalf("身份证号 %s 的校验码应为 %c", id, want)
+	}
+}
+
+func TestGenerateTemplate_RNG(t *testing.T) {
+	spec, err := generator.LoadTemplate(strings.NewReader(testTemplate))
+	if err != nil {
+		t.Fatal(err)
+	}
+	def, _ := generator.GenerateTemplate(spec, generator.TemplateGenConfig{Seed: 7})
+	pcg, err := generator.GenerateTemplate(spec, generator.TemplateGenConfig{Seed: 7, RNG: generator.RNGPCG})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if slices.Equal(def[0].Rows[0], pcg[0].Rows[0]) && slices.Equal(def[0].Rows[1], pcg[0].Rows[1]) {
+		t.Fatal("RNG 未生效：pcg 与 mathrand 输出相同")
+	}
+	if _, err := generator.GenerateTemplate(spec, generator.TemplateGenConfig{RNG: "nope"}); err == nil {
+		t.Fatal("未知 RNG 应返回 error")
+	}
+}
+
+func TestLoadTemplate_Errors(t *testing.T) {
+	table := func(name, cols string) string {
+		return `{"name": "` + name + `", "rows": 2, "columns": [` + cols + `]}`
+	}
+	tests := []struct {
+		name string
+		tpl  string
+		want string
+	}{
+		{"未知类型", table("a", `{"name": "x", "gen": "uuid"}`), "未知的类型"},
+		{"整数区间颠倒", table("a", `{"name": "x", "gen": "int:30..18"}`), "整数区间"},
+		{"float 修饰未知", table("a", `{"name": "x", "gen": "float:1..2 round 1"}`), "未知的修饰"},
+		{"enum 权重非法", table("a", `{"name": "x", "gen": "enum:a=0|b"}`), "权重"},
+		{"seq 格式错误", table("a", `{"name": "x", "gen": "seq:%s"}`), "占位符"},
+		{"email 引用后面的列", table("a", `{"name": "e", "gen": "email:n"}, {"name": "n", "gen": "name:cn"}`), "姓名列"},
+		{"列名重复", table("a", `{"name": "x", "gen": "city"}, {"name": "x", "gen": "city"}`), "重复"},
+		{"引用不存在的表", table("a", `{"name": "x", "gen": "ref:b.id"}`), "不存在"},
+		{"引用不存在的列", table("a", `{"name": "x", "gen": "ref:b.id"}`) + "," + table("b", `{"name": "y", "gen": "seq"}`), "没有列"},
+		{"循环引用", table("a", `{"name": "x", "gen": "ref:b.y"}`) + "," + table("b", `{"name": "y", "gen": "ref:a.x"}`), "循环引用"},
+		{"normal 缺少右括号", table("a", `{"name": "x", "gen": "float:normal(75, 10 step 1"}`), "normal 应形如"},
+		{"表名为空", table(" ", `{"name": "x", "gen": "seq"}`), "缺少名称"},
+		{"表名跳出输出目录", table("../x", `{"name": "x", "gen": "seq"}`), "路径分隔符"},
+		{"表名含斜杠", table("a/b", `{"name": "x", "gen": "seq"}`), "路径分隔符"},
+		{"表名含反斜杠", table(`a\\b`, `{"name": "x", "gen": "seq"}`), "路径分隔符"},
+		{"表名为 ..", table("..", `{"name": "x", "gen": "seq"}`), "路径分隔符"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := generator.LoadTemplate(strings.NewReader(`{"tables": [` + tt.tpl + `]}`))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("err = %v, want 包含 %q", err, tt.want)
+			}
+		})
+	}
+	if _, err := generator.LoadTemplate(strings.NewReader(`{"tables": [], "seed": 1}`)); err == nil {
+		t.Error("未知字段应返回 error")
+	}
+}
+
+// normal(均值, 标准差) 的括号内允许空格，结果与不带空格的写法相同。
+func TestGenerateTemplate_NormalWithSpaces(t *testing.T) {
+	gen := func(spec string) [][]string {
+		t.Helper()
+		tpl := `{"tables": [{"name": "t", "rows": 20, "columns": [{"name": "得分", "gen": "` + spec + `"}]}]}`
+		s, err := generator.LoadTemplate(strings.NewReader(tpl))
+		if err != nil {
+			t.Fatalf("%s: %v", spec, err)
+		}
+		tables, err := generator.GenerateTemplate(s, generator.TemplateGenConfig{Seed: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tables[0].Rows
+	}
+	want := gen("float:normal(75,10) step 0.5 in 0..100")
+	for _, spec := range []string{"float:normal(75, 10) step 0.5 in 0..100", "float: normal( 75 , 10 )  step 0.5  in 0..100"} {
+		if got := gen(spec); !slices.EqualFunc(got, want, slices.Equal[[]string]) {
+			t.Errorf("%s: got %v, want %v", spec, got, want)
+		}
+	}
+}
@@ -0,0 +1,3 @@
+// Package hanzi 提供处理中文文本的小工具：繁体转简体（常用字）、姓名规范化与汉字转拼音。
+// 拼音基于 github.com/mozillazg/go-pinyin 的逐字读音，并对常见多音姓氏（如 曾/单/解）做了修正。
+package hanzi
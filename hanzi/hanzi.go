@@ -0,0 +1,112 @@
+package hanzi
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/mozillazg/go-pinyin"
+	"golang.org/x/text/width"
+)
+
+// ToSimplified 将 s 中收录于内置表的繁体字转换为简体字，其余字符保持不变。
+func ToSimplified(s string) string {
+	return strings.Map(func(r rune) rune {
+		if sr, ok := t2s[r]; ok {
+			return sr
+		}
+		return r
+	}, s)
+}
+
+// NormalizeName 返回用于比较的姓名形式：全角转半角、去掉所有空白、统一间隔号为 ·、繁体转简体、ASCII 转小写。
+// 例如 " 張 三 " 与 "张三" 规范化后相同。
+func NormalizeName(s string) string {
+	s = width.Fold.String(s)
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsSpace(r):
+			return -1
+		case r == '•' || r == '・' || r == '‧' || r == '.':
+			return '·'
+		}
+		return unicode.ToLower(r)
+	}, s)
+	return ToSimplified(s)
+}
+
+// pinyinArgs 为无声调、非多音模式的转换参数。
+var pinyinArgs = pinyin.NewArgs()
+
+// Pinyin 返回 s 的无声调拼音音节序列：每个汉字一个音节（多音字取最常用读音），
+// 连续的 ASCII 字母/数字作为一个小写音节保留，其余字符忽略。繁体字会先转为简体。
+func Pinyin(s string) []string {
+	var (
+		out  []string
+		word strings.Builder
+	)
+	flush := func() {
+		if word.Len() > 0 {
+			out = append(out, word.String())
+			word.Reset()
+		}
+	}
+	for _, r := range ToSimplified(s) {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			flush()
+			if py := pinyin.SinglePinyin(r, pinyinArgs); len(py) > 0 {
+				out = append(out, py[0])
+			}
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			word.WriteRune(unicode.ToLower(r))
+		default:
+			flush()
+		}
+	}
+	flush()
+	return out
+}
+
+// surnameReadings 为作姓氏时读音与常用读音不同的字。
+var surnameReadings = map[rune]string{
+	'曾': "zeng", '单': "shan", '解': "xie", '仇': "qiu", '区': "ou", '朴': "piao",
+	'查': "zha", '盖': "ge", '乐': "yue", '覃': "qin", '翟': "zhai", '缪': "miao",
+	'种': "chong", '繁': "po", '员': "yun", '召': "shao", '万': "wan", '尉': "yu",
+}
+
+// NamePinyin 与 Pinyin 相同，但首字按姓氏读音处理（如 曾 → zeng、单 → shan）。
+func NamePinyin(name string) []string {
+	py := Pinyin(name)
+	simplified := []rune(ToSimplified(strings.TrimSpace(name)))
+	if len(py) > 0 && len(simplified) > 0 {
+		if r, ok := surnameReadings[simplified[0]]; ok {
+			py[0] = r
+		}
+	}
+	return py
+}
+
+// Initials 返回每个音节的首字母，如 "张三" → "zs"。
+func Initials(syllables []string) string {
+	var b strings.Builder
+	for _, s := range syllables {
+		if s != "" {
+			b.WriteByte(s[0])
+		}
+	}
+	return b.String()
+}
+
+// DisplayWidth 返回 s 在终端中的显示宽度：东亚宽字符与全角字符计 2，其余计 1。
+func DisplayWidth(s string) int {
+	n := 0
+	for _, r := range s {
+		switch width.LookupRune(r).Kind() {
+		case width.EastAsianWide, width.EastAsianFullwidth:
+			n += 2
+		default:
+			n++
+		}
+	}
+	return n
+}
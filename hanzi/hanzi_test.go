@@ -0,0 +1,66 @@
+package hanzi_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/xianyudd/hanzi-data-kit/hanzi"
+)
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{" 張 三 ", "张三"},
+		{"陳　靜", "陈静"},
+		{"买买提•艾力", "买买提·艾力"},
+		{"ＡＢＣ", "abc"},
+	}
+	for _, tt := range tests {
+		if got := hanzi.NormalizeName(tt.in); got != tt.want {
+			t.Fatalf("NormalizeName(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestPinyin(t *testing.T) {
+	tests := []struct {
+		in   string
+		name bool
+		want []string
+	}{
+		{in: "张三", want: []string{"zhang", "san"}},
+		{in: "劉偉", want: []string{"liu", "wei"}},
+		{in: "Tom 张", want: []string{"tom", "zhang"}},
+		{in: "曾桂英", name: true, want: []string{"zeng", "gui", "ying"}},
+		{in: "单磊", name: true, want: []string{"shan", "lei"}},
+	}
+	for _, tt := range tests {
+		got := hanzi.Pinyin(tt.in)
+		if tt.name {
+			got = hanzi.NamePinyin(tt.in)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Fatalf("pinyin(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+	if got := hanzi.Initials(hanzi.Pinyin("张桂英")); got != "zgy" {
+		t.Fatalf("Initials = %q", got)
+	}
+}
+
+func TestDisplayWidth(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"", 0},
+		{"abc", 3},
+		{"张三", 4},
+		{"[60, 70)", 8},
+		{"ＡＢ", 4},
+	}
+	for _, tt := range tests {
+		if got := hanzi.DisplayWidth(tt.in); got != tt.want {
+			t.Errorf("DisplayWidth(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
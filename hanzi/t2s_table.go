@@ -0,0 +1,32 @@
+package hanzi
+
+// t2sPairs 为常用繁体字到简体字的映射，按“繁简”两两相邻排列。
+// 覆盖范围以姓名、城市/地名及常见字为主，不是完整的繁简转换表。
+const t2sPairs = "" +
+	"亞亚來来個个們们偉伟傑杰傳传儀仪儲储內内凱凯別别剛刚劉刘劍剑動动勝胜勞劳勳勋區区" +
+	"厙厍厲厉吳吴呂吕員员問问喬乔單单嚴严國国園园報报場场壩坝壽寿夢梦婁娄婭娅嫻娴嬋婵" +
+	"孫孙學学宮宫實实寧宁寫写寶宝將将專专對对層层島岛峽峡崑昆嵐岚嶺岭嶽岳師师幹干庫库" +
+	"廈厦廠厂廣广廳厅張张強强彥彦後后從从悅悦愛爱態态慶庆憲宪應应懷怀戶户揚扬撫抚據据" +
+	"數数時时晉晋暉晖曉晓書书會会東东楊杨楓枫業业榮荣樂乐樓楼樣样橋桥機机檔档權权欒栾" +
+	"歐欧歡欢歲岁歷历氣气湯汤溫温滄沧滿满漢汉潔洁澤泽濟济濤涛濰潍濱滨瀅滢瀋沈瀾澜灝灏" +
+	"灣湾為为烏乌無无煙烟燁烨營营燦灿爾尔現现琿珲瑋玮瑤瑶瑩莹環环瓊琼畢毕畫画當当發发" +
+	"盤盘盧卢碼码祿禄禎祯禮礼種种穎颖筆笔範范簡简粵粤紀纪紅红紈纨純纯紙纸級级紳绅紹绍" +
+	"終终綏绥經经維维網网綺绮線线縣县總总績绩繆缪羅罗義义習习聖圣聞闻聰聪聶聂聽听肅肃" +
+	"臉脸臨临臺台與与興兴艷艳荊荆莊庄華华萬万葉叶蒼苍蓋盖蓮莲蔣蒋蕪芜蕭萧薊蓟藍蓝藝艺" +
+	"藥药藺蔺蘆芦蘇苏蘭兰號号衛卫裏里裡里複复見见親亲覺觉計计許许詠咏試试話话認认語语" +
+	"誠诚說说課课談谈諸诸謝谢證证譚谭護护讀读豐丰豔艳貝贝貞贞財财貢贡貴贵買买費费賀贺" +
+	"賁贲賈贾賢贤賣卖賴赖贛赣趙赵車车軍军輝辉農农這这連连進进運运達达遠远遼辽還还邊边" +
+	"郟郏郵邮鄉乡鄒邹鄔邬鄧邓鄭郑鄲郸酈郦醫医鈄钭鈕钮鈺钰銀银銘铭鋒锋鋼钢錄录錢钱錦锦" +
+	"錫锡鍾钟鎮镇鐘钟鐵铁長长門门開开間间閔闵閩闽閻阎闕阙關关闞阚陝陕陰阴陳陈陸陆陽阳" +
+	"隊队險险隴陇雙双雜杂雞鸡雲云電电霽霁靄霭靜静鞏巩韋韦韓韩韜韬韻韵項项順顺須须頭头" +
+	"題题顏颜顧顾風风飄飘飛飞養养館馆饒饶馬马馮冯駐驻駱骆騰腾驍骁驗验體体髮发鬱郁魚鱼" +
+	"魯鲁鮑鲍鳥鸟鳳凤鴨鸭鴻鸿鵬鹏鶯莺鶴鹤鹽盐麗丽麼么黃黄點点黨党齊齐龍龙龐庞龔龚"
+
+var t2s = func() map[rune]rune {
+	rs := []rune(t2sPairs)
+	m := make(map[rune]rune, len(rs)/2)
+	for i := 0; i+1 < len(rs); i += 2 {
+		m[rs[i]] = rs[i+1]
+	}
+	return m
+}()
@@ -0,0 +1,146 @@
+package cliutil
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/xianyudd/hanzi-data-kit/contact"
+	"github.com/xianyudd/hanzi-data-kit/parser"
+)
+
+// CSVInputFlags 保存读取学生 CSV 时常用的解析参数。
+type CSVInputFlags struct {
+	SkipBadRows    bool
+	TrimSpace      bool
+	AllowBOM       bool
+	Delimiter      string
+	Comment        string
+	LazyQuotes     bool
+	VariableFields bool
+	Sniff          bool
+	DupHeaders     string
+	Encoding       string
+	DistrictColumn string
+	Validate       string
+	DateColumns    string
+	DateLayouts    string
+	DateTZ         string
+}
+
+// RegisterCSVInputFlags 在 fs 上注册解析参数（与 parse_students 的同名参数含义一致）。
+func RegisterCSVInputFlags(fs *flag.FlagSet) *CSVInputFlags {
+	f := &CSVInputFlags{}
+	fs.BoolVar(&f.SkipBadRows, "skip-bad-rows", true, "遇到坏行是否跳过（否则严格报错）")
+	fs.BoolVar(&f.TrimSpace, "trim-space", true, "是否对字段做TrimSpace")
+	fs.BoolVar(&f.AllowBOM, "allow-bom", true, "是否剥离UTF-8 BOM")
+	fs.StringVar(&f.Delimiter, "delimiter", ",", "字段分隔符(如 , ; | tab)")
+	fs.StringVar(&f.Comment, "comment", "", "注释行起始字符(如 #，空表示不识别注释)")
+	fs.BoolVar(&f.LazyQuotes, "lazy-quotes", false, "是否允许不规范的引号")
+	fs.BoolVar(&f.VariableFields, "variable-fields", false, "是否允许每行字段数不同")
+	fs.BoolVar(&f.Sniff, "sniff", false, "根据文件开头自动推断分隔符/注释符等(忽略上述方言参数)")
+	fs.StringVar(&f.DupHeaders, "dup-headers", "first", "重复列策略: first|error|last|merge|warn")
+	fs.StringVar(&f.Encoding, "encoding", "utf-8", "输入文件编码: utf-8|gbk|gb18030|utf-16|auto")
+	fs.StringVar(&f.Validate, "validate", "", "按格式校验列: 列=phone|email|username，逗号分隔(如 手机号=phone,邮箱=email)")
+	fs.StringVar(&f.DateColumns, "date-columns", "", "日期列表头，逗号分隔(如 出生日期,入学日期)；值须为可识别的日期，并被规范化为 2006-01-02")
+	fs.StringVar(&f.DateLayouts, "date-layouts", "", "日期列依次尝试的格式(Go 布局，逗号分隔；excel 表示 Excel 序列号)；为空时使用内置的常见中文日期格式")
+	fs.StringVar(&f.DateTZ, "date-tz", "", "不含时区的日期所在时区(如 Asia/Shanghai、+08:00)；为空时为 UTC")
+	fs.StringVar(&f.DistrictColumn, "district-column", "", "区县列表头(如 区县)；设置后校验每行的 城市/区县 组合是否真实存在")
+	return f
+}
+
+// Options 将参数组装为 parser.CSVParseOptions。
+func (f *CSVInputFlags) Options(logger *slog.Logger) (parser.CSVParseOptions, error) {
+	dialect, err := DialectFromFlags(f.Delimiter, f.Comment, f.LazyQuotes, f.VariableFields)
+	if err != nil {
+		return parser.CSVParseOptions{}, err
+	}
+	dup, err := parser.ParseDuplicateHeaderPolicy(f.DupHeaders)
+	if err != nil {
+		return parser.CSVParseOptions{}, fmt.Errorf("-dup-headers: %w", err)
+	}
+	validators, err := ParseColumnValidators(f.Validate)
+	if err != nil {
+		return parser.CSVParseOptions{}, fmt.Errorf("-validate: %w", err)
+	}
+	loc, err := ParseLocation(f.DateTZ)
+	if err != nil {
+		return parser.CSVParseOptions{}, fmt.Errorf("-date-tz: %w", err)
+	}
+	return parser.CSVParseOptions{
+		TrimSpace:        f.TrimSpace,
+		AllowBOM:         f.AllowBOM,
+		SkipBadRows:      f.SkipBadRows,
+		Dialect:          dialect,
+		SniffDialect:     f.Sniff,
+		DuplicateHeaders: dup,
+		Logger:           logger,
+		Encoding:         f.Encoding,
+		DistrictColumn:   f.DistrictColumn,
+		ColumnValidators: validators,
+		DateColumns:      SplitList(f.DateColumns),
+		DateLayouts:      SplitList(f.DateLayouts),
+		DateLocation:     loc,
+	}, nil
+}
+
+// formatValidators 为 -validate 支持的格式名。
+var formatValidators = map[string]func(string) error{
+	"phone":    contact.ValidatePhone,
+	"email":    contact.ValidateEmail,
+	"username": contact.ValidateUsername,
+}
+
+// ParseColumnValidators 解析形如 "手机号=phone,邮箱=email" 的列格式说明；spec 为空时返回 nil。
+func ParseColumnValidators(spec string) (map[string]func(string) error, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+	out := map[string]func(string) error{}
+	for _, item := range strings.Split(spec, ",") {
+		col, format, ok := strings.Cut(strings.TrimSpace(item), "=")
+		col, format = strings.TrimSpace(col), strings.TrimSpace(format)
+		if !ok || col == "" {
+			return nil, fmt.Errorf("应为 列=格式, 实际为 %q", item)
+		}
+		v, ok := formatValidators[format]
+		if !ok {
+			return nil, fmt.Errorf("未知的格式 %q(可选 phone|email|username)", format)
+		}
+		out[col] = v
+	}
+	return out, nil
+}
+
+// DialectFromFlags 将命令行参数组装为 parser.Dialect。
+func DialectFromFlags(delimiter, comment string, lazyQuotes, variableFields bool) (parser.Dialect, error) {
+	delim, err := parser.ParseDelimiter(delimiter)
+	if err != nil {
+		return parser.Dialect{}, fmt.Errorf("-delimiter: %w", err)
+	}
+	commentRune, err := parser.ParseDelimiter(comment)
+	if err != nil {
+		return parser.Dialect{}, fmt.Errorf("-comment: %w", err)
+	}
+	d := parser.Dialect{
+		Delimiter:  delim,
+		Comment:    commentRune,
+		LazyQuotes: lazyQuotes,
+	}
+	if variableFields {
+		d.FieldsPerRecord = -1
+	}
+	return d, d.Validate()
+}
+
+// SplitList 将逗号分隔的列表拆分为去除首尾空白的非空项；全部为空时返回 nil。
+func SplitList(s string) []string {
+	var out []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
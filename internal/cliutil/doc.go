@@ -0,0 +1,3 @@
+// Package cliutil 提供 cmd/ 下各命令行工具共用的辅助代码（如日志参数的注册与解析）。
+// 它位于 internal 下，仅供本仓库的命令行工具使用，不属于对外 API。
+package cliutil
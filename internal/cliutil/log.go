@@ -0,0 +1,41 @@
+package cliutil
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// LogFlags 保存 -log-level / -log-format 两个命令行参数的值。
+type LogFlags struct {
+	Level  string
+	Format string
+}
+
+// RegisterLogFlags 在 fs 上注册 -log-level 与 -log-format 参数。
+func RegisterLogFlags(fs *flag.FlagSet) *LogFlags {
+	f := &LogFlags{}
+	fs.StringVar(&f.Level, "log-level", "info", "日志级别: debug|info|warn|error")
+	fs.StringVar(&f.Format, "log-format", "text", "日志格式: text|json")
+	return f
+}
+
+// Logger 按参数构造一个写到 w（通常为 os.Stderr）的 slog.Logger。
+func (f *LogFlags) Logger(w io.Writer) (*slog.Logger, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(f.Level)); err != nil {
+		return nil, fmt.Errorf("-log-level: 未知的日志级别 %q", f.Level)
+	}
+	opts := &slog.HandlerOptions{Level: level}
+
+	switch strings.ToLower(f.Format) {
+	case "text":
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	default:
+		return nil, fmt.Errorf("-log-format: 只支持 text|json, 实际为 %q", f.Format)
+	}
+}
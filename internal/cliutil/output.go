@@ -0,0 +1,33 @@
+package cliutil
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// CreateOutput 打开输出目标：path 为 "-" 或空时写到标准输出，否则创建文件（自动创建父目录）。
+// 返回的 closeFn 会刷新缓冲并关闭文件，调用方必须调用。
+func CreateOutput(path string) (w io.Writer, closeFn func() error, err error) {
+	if path == "" || path == "-" {
+		bw := bufio.NewWriter(os.Stdout)
+		return bw, bw.Flush, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, nil, fmt.Errorf("创建输出目录失败: %w", err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("创建输出文件失败: %w", err)
+	}
+	bw := bufio.NewWriter(f)
+	return bw, func() error {
+		if err := bw.Flush(); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	}, nil
+}
@@ -0,0 +1,220 @@
+package cliutil
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xianyudd/hanzi-data-kit/hanzi"
+)
+
+// RowFormats 为 RowWriter 支持的输出格式。
+const RowFormats = "csv|jsonl|json|table"
+
+// RowWriter 以流式方式输出一张结果表：先调用一次 WriteHeader，再逐行 WriteRow，最后 Close。
+// 单元格可以是 string、数值、bool、nil、[]byte、time.Time，或实现了 fmt.Stringer 的值；
+// json/jsonl 格式下实现了 json.Marshaler 的值按其自身的 JSON 形式输出。
+type RowWriter interface {
+	WriteHeader(cols []string) error
+	WriteRow(vals []any) error
+	Close() error
+}
+
+// NewRowWriter 按格式名创建 RowWriter；comma 为 csv 格式的分隔符（0 表示逗号）。
+// table 格式需要对齐列宽，会在 Close 时一次性输出；其余格式逐行写出。
+func NewRowWriter(w io.Writer, format string, comma rune) (RowWriter, error) {
+	switch format {
+	case "csv":
+		cw := csv.NewWriter(w)
+		if comma != 0 {
+			cw.Comma = comma
+		}
+		return &csvRowWriter{w: cw}, nil
+	case "jsonl":
+		return &jsonRowWriter{w: w, lines: true}, nil
+	case "json":
+		return &jsonRowWriter{w: w}, nil
+	case "table":
+		return &tableRowWriter{w: w}, nil
+	}
+	return nil, fmt.Errorf("不支持的输出格式: %q（可选: %s）", format, RowFormats)
+}
+
+// FormatCell 返回单元格的文本形式（csv/table 格式使用）。
+func FormatCell(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case fmt.Stringer:
+		return v.String()
+	}
+	return fmt.Sprint(v)
+}
+
+type csvRowWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvRowWriter) WriteHeader(cols []string) error { return c.w.Write(cols) }
+
+func (c *csvRowWriter) WriteRow(vals []any) error {
+	row := make([]string, len(vals))
+	for i, v := range vals {
+		row[i] = FormatCell(v)
+	}
+	return c.w.Write(row)
+}
+
+func (c *csvRowWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// jsonRowWriter 输出以列名为键的 JSON 对象：jsonl 为每行一个对象，json 为一个数组。
+// 键按列顺序输出（而不是 map 的字典序）。
+type jsonRowWriter struct {
+	w     io.Writer
+	lines bool
+	cols  []string
+	count int
+}
+
+func (j *jsonRowWriter) WriteHeader(cols []string) error {
+	j.cols = cols
+	return nil
+}
+
+func (j *jsonRowWriter) WriteRow(vals []any) error {
+	var b strings.Builder
+	switch {
+	case j.lines:
+	case j.count == 0:
+		b.WriteString("[\n  ")
+	default:
+		b.WriteString(",\n  ")
+	}
+	j.count++
+	b.WriteByte('{')
+	for i, col := range j.cols {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		if err := encodeJSON(&b, col); err != nil {
+			return err
+		}
+		b.WriteByte(':')
+		var v any
+		if i < len(vals) {
+			v = vals[i]
+		}
+		if t, ok := v.(time.Time); ok {
+			v = t.Format(time.RFC3339)
+		} else if bs, ok := v.([]byte); ok {
+			v = string(bs)
+		}
+		if err := encodeJSON(&b, v); err != nil {
+			return err
+		}
+	}
+	b.WriteByte('}')
+	if j.lines {
+		b.WriteByte('\n')
+	}
+	_, err := io.WriteString(j.w, b.String())
+	return err
+}
+
+func (j *jsonRowWriter) Close() error {
+	if j.lines {
+		return nil
+	}
+	s := "\n]\n"
+	if j.count == 0 {
+		s = "[]\n"
+	}
+	_, err := io.WriteString(j.w, s)
+	return err
+}
+
+func encodeJSON(b *strings.Builder, v any) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	// Encode 总会追加换行，去掉它以便拼接在同一行。
+	b.Write(bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+	return nil
+}
+
+// tableRowWriter 输出按显示宽度对齐的文本表格（中文字符按 2 列宽计算）。
+type tableRowWriter struct {
+	w    io.Writer
+	rows [][]string
+}
+
+func (t *tableRowWriter) WriteHeader(cols []string) error {
+	t.rows = append(t.rows, cols)
+	return nil
+}
+
+func (t *tableRowWriter) WriteRow(vals []any) error {
+	row := make([]string, len(vals))
+	for i, v := range vals {
+		row[i] = FormatCell(v)
+	}
+	t.rows = append(t.rows, row)
+	return nil
+}
+
+func (t *tableRowWriter) Close() error {
+	var widths []int
+	for _, row := range t.rows {
+		for i, cell := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], hanzi.DisplayWidth(cell))
+		}
+	}
+	var b strings.Builder
+	for r, row := range t.rows {
+		for i, cell := range row {
+			if i > 0 {
+				b.WriteString("  ")
+			}
+			b.WriteString(cell)
+			if i < len(row)-1 {
+				b.WriteString(strings.Repeat(" ", widths[i]-hanzi.DisplayWidth(cell)))
+			}
+		}
+		b.WriteByte('\n')
+		if r == 0 {
+			for i, w := range widths {
+				if i > 0 {
+					b.WriteString("  ")
+				}
+				b.WriteString(strings.Repeat("-", w))
+			}
+			b.WriteByte('\n')
+		}
+	}
+	_, err := io.WriteString(t.w, b.String())
+	return err
+}
@@ -0,0 +1,41 @@
+package cliutil
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	_ "time/tzdata" // 内嵌时区数据库，使 -tz Asia/Shanghai 在没有系统时区数据的环境中也可用。
+
+	"github.com/xianyudd/hanzi-data-kit/parser"
+)
+
+// ParseLocation 解析时区参数：IANA 名称（如 Asia/Shanghai、UTC）或固定偏移（如 +08:00、-0530）；空串返回 nil。
+func ParseLocation(s string) (*time.Location, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	if s[0] == '+' || s[0] == '-' {
+		for _, layout := range []string{"-07:00", "-0700", "-07"} {
+			if t, err := time.Parse(layout, s); err == nil {
+				_, offset := t.Zone()
+				return time.FixedZone("UTC"+s, offset), nil
+			}
+		}
+		return nil, fmt.Errorf("无法识别的时区偏移 %q(应形如 +08:00)", s)
+	}
+	loc, err := time.LoadLocation(s)
+	if err != nil {
+		return nil, fmt.Errorf("未知的时区 %q: %w", s, err)
+	}
+	return loc, nil
+}
+
+// ParseTimeFlag 按 parser.DefaultDateLayouts() 解析命令行中的日期/时间参数（如 -ref-date 2025-09-01），
+// 不含时区的值按 loc 解释；空串返回零值。
+func ParseTimeFlag(s string, loc *time.Location) (time.Time, error) {
+	if strings.TrimSpace(s) == "" {
+		return time.Time{}, nil
+	}
+	return parser.ParseDate(s, nil, loc)
+}
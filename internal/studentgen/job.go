@@ -0,0 +1,346 @@
+// Package studentgen 实现 gen_students 的学生CSV生成，以及记录其来源的 .meta.json 伴随文件，
+// 供 gen_students 与 hanzi reproduce 共用，以保证二者生成逐字节相同的文件。
+// 它位于 internal 下，不属于对外 API。
+package studentgen
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/xianyudd/hanzi-data-kit/contact"
+	"github.com/xianyudd/hanzi-data-kit/generator"
+	"github.com/xianyudd/hanzi-data-kit/internal/cliutil"
+	"github.com/xianyudd/hanzi-data-kit/model"
+	"github.com/xianyudd/hanzi-data-kit/parser"
+)
+
+// Job 描述一次学生CSV生成中所有影响输出内容的参数；相同的 Job 生成相同的文件。
+// 并发数、日志等不影响内容的参数不在其中。
+type Job struct {
+	// Student 为学生生成器配置；写入伴随文件时为补全默认值后的配置。
+	Student generator.StudentGenConfig `json:"student"`
+
+	// Rows 为生成的行数（不含表头）。
+	Rows int `json:"rows"`
+
+	// Offset 为首行对应的记录序号（从 0 开始），仅 Student.PerRow 时可非零。
+	Offset int `json:"offset,omitzero"`
+
+	// Address 为 true 时追加 省份/区县/区划代码/街道/门牌号 列。
+	Address bool `json:"address,omitzero"`
+
+	// Contact 非 nil 时追加 手机号/邮箱/用户名 列。
+	Contact *ContactSpec `json:"contact,omitempty"`
+
+	// Dates 非 nil 时追加 出生日期/入学日期 列。
+	Dates *DateSpec `json:"dates,omitempty"`
+
+	// Headers 为表头语言：cn 或 en。
+	Headers string `json:"headers"`
+
+	Dialect DialectSpec `json:"dialect"`
+}
+
+// ContactSpec 为联系方式列的参数。
+type ContactSpec struct {
+	// Carriers 为限定的运营商（contact.Carrier 的名称）；为空表示全部。
+	Carriers  []string `json:"carriers,omitempty"`
+	MaskPhone bool     `json:"mask_phone,omitzero"`
+}
+
+// DateSpec 为日期列的参数。
+type DateSpec struct {
+	// Reference 为参考日期，按 Location 解释。
+	Reference string `json:"reference"`
+
+	// Location 为时区，格式同 -tz（如 Asia/Shanghai、+08:00）。
+	Location string `json:"location"`
+
+	EnrollmentAge int `json:"enrollment_age"`
+}
+
+// DialectSpec 为输出方言；Delimiter 为单个字符（如 ","、";"、"\t"）。
+type DialectSpec struct {
+	Delimiter   string `json:"delimiter"`
+	CRLF        bool   `json:"crlf,omitzero"`
+	AlwaysQuote bool   `json:"always_quote,omitzero"`
+}
+
+// plan 为由 Job 构造出的生成器与表头。
+type plan struct {
+	job      Job
+	students *generator.StudentGenerator
+	contacts *generator.ContactGenerator
+	dates    *generator.DateGenerator
+	headers  []string
+	dialect  parser.Dialect
+
+	// written 为文件中已有的行数；追加时新行从第 written+1 行开始。
+	written int
+}
+
+// Validate 检查 j 的参数是否有效。
+func (j Job) Validate() error {
+	_, err := j.compile()
+	return err
+}
+
+// compile 校验 j 并构造生成器；返回的 error 均为参数错误。
+func (j Job) compile() (*plan, error) {
+	if j.Rows <= 0 {
+		return nil, fmt.Errorf("行数必须 > 0, 实际为 %d", j.Rows)
+	}
+	if j.Offset < 0 {
+		return nil, fmt.Errorf("offset 不能为负数")
+	}
+	if j.Offset != 0 && !j.Student.PerRow {
+		return nil, fmt.Errorf("offset 需配合 per-row")
+	}
+	if j.Offset != 0 && (j.Contact != nil || j.Dates != nil) {
+		// 联系方式要跨行去重、日期生成器按顺序抽取，二者都依赖此前各行。
+		return nil, fmt.Errorf("offset 不能与联系方式/日期列同时使用")
+	}
+	if j.Student.RNG != "" {
+		if _, err := generator.ParseRNGAlgorithm(string(j.Student.RNG)); err != nil {
+			return nil, err
+		}
+	}
+
+	p := &plan{job: j, students: generator.NewStudentGenerator(j.Student)}
+
+	delim := []rune(j.Dialect.Delimiter)
+	if len(delim) != 1 {
+		return nil, fmt.Errorf("分隔符须为单个字符, 实际为 %q", j.Dialect.Delimiter)
+	}
+	p.dialect = parser.Dialect{Delimiter: delim[0], UseCRLF: j.Dialect.CRLF, AlwaysQuote: j.Dialect.AlwaysQuote}
+	if err := p.dialect.Validate(); err != nil {
+		return nil, err
+	}
+
+	// 联系方式与日期使用由 seed 派生的独立随机序列，不会重放学生序列。
+	seed := j.Student.Seed
+	if j.Contact != nil {
+		var cs []contact.Carrier
+		for _, name := range j.Contact.Carriers {
+			c, err := contact.ParseCarrier(name)
+			if err != nil {
+				return nil, err
+			}
+			cs = append(cs, c)
+		}
+		cg, err := generator.NewContactGenerator(generator.ContactGenConfig{Seed: generator.StreamSeed(seed, "contacts"), Carriers: cs, MaskPhone: j.Contact.MaskPhone, RNG: j.Student.RNG})
+		if err != nil {
+			return nil, err
+		}
+		p.contacts = cg
+	}
+	if j.Dates != nil {
+		loc, err := cliutil.ParseLocation(j.Dates.Location)
+		if err != nil {
+			return nil, err
+		}
+		ref, err := cliutil.ParseTimeFlag(j.Dates.Reference, loc)
+		if err != nil {
+			return nil, err
+		}
+		p.dates = generator.NewDateGenerator(generator.DateGenConfig{Seed: generator.StreamSeed(seed, "dates"), Reference: ref, Location: loc, EnrollmentAge: j.Dates.EnrollmentAge, RNG: j.Student.RNG})
+	}
+
+	en := j.Headers == "en"
+	switch {
+	case en:
+		p.headers = model.StudentHeadersEN()
+	case j.Headers == "cn":
+		p.headers = model.StudentHeadersCN()
+	default:
+		return nil, fmt.Errorf("表头只支持 cn|en, 实际为 %q", j.Headers)
+	}
+	if j.Address {
+		// 城市列已由学生表头提供，地址只追加其余列。
+		if en {
+			p.headers = append(p.headers, "Province", "District", "DivisionCode", "Street", "HouseNumber")
+		} else {
+			p.headers = append(p.headers, "省份", "区县", "区划代码", "街道", "门牌号")
+		}
+	}
+	if p.contacts != nil {
+		if en {
+			p.headers = append(p.headers, "Phone", "Email", "Username")
+		} else {
+			p.headers = append(p.headers, model.ContactHeadersCN()...)
+		}
+	}
+	if p.dates != nil {
+		if en {
+			p.headers = append(p.headers, "BirthDate", "EnrollmentDate")
+		} else {
+			p.headers = append(p.headers, "出生日期", "入学日期")
+		}
+	}
+	return p, nil
+}
+
+// row 生成第 i 行（1-based）。只有 PerRow 模式下才会被乱序/并发调用，此时每行只依赖行号；
+// 其余情况下按行号顺序调用恰好一次，与顺序生成的结果一致。
+func (p *plan) row(i int) []string {
+	var stu model.Student
+	var addr model.Address
+	perRow := p.job.Student.PerRow
+	switch {
+	case perRow && p.job.Address:
+		stu, addr = p.students.AtWithAddress(p.job.Offset + p.written + i - 1)
+	case perRow:
+		stu = p.students.At(p.job.Offset + p.written + i - 1)
+	case p.job.Address:
+		stu, addr = p.students.NextWithAddress()
+	default:
+		stu = p.students.Next()
+	}
+	row := model.StudentToRowCN(stu)
+	if p.job.Address {
+		row = append(row, addr.Province, addr.District, addr.Code, addr.Street, addr.HouseNumber)
+	}
+	if p.contacts != nil {
+		row = append(row, model.ContactToRowCN(p.contacts.Next(stu.Name))...)
+	}
+	if p.dates != nil {
+		birth := p.dates.BirthDate(stu.Age)
+		row = append(row, model.FormatDate(birth), model.FormatDate(p.dates.EnrollmentDate(birth)))
+	}
+	return row
+}
+
+// Generate 按 j 将学生数据写入 path，并返回描述该文件来源的 Meta（含补全默认值后的配置、文件校验和与生成器的最终状态）。
+// workers 大于 1 时并发生成（需 Student.PerRow，且不能有联系方式/日期列），输出与单线程一致。
+func Generate(path string, j Job, workers int, logger *slog.Logger) (Meta, error) {
+	if err := checkWorkers(j, workers); err != nil {
+		return Meta{}, err
+	}
+	p, err := j.compile()
+	if err != nil {
+		return Meta{}, err
+	}
+	return p.write(path, j.Rows, workers, false, logger)
+}
+
+// Append 在 m 所描述的文件 path 末尾继续生成，使其共有 rows 行，并返回更新后的 Meta。
+// 结果与以相同参数一次生成 rows 行的文件逐字节相同。path 自生成以来不能被修改过（按 m 中的校验和检查）；
+// 联系方式列需要跨行去重，不支持追加。
+func Append(path string, m Meta, rows, workers int, logger *slog.Logger) (Meta, error) {
+	j := m.Job
+	if rows <= j.Rows {
+		return Meta{}, fmt.Errorf("文件已有 %d 行, 追加后的总行数须大于它, 实际为 %d", j.Rows, rows)
+	}
+	if j.Contact != nil {
+		return Meta{}, fmt.Errorf("联系方式列需要与此前所有行去重, 不支持追加")
+	}
+	if err := checkWorkers(j, workers); err != nil {
+		return Meta{}, err
+	}
+	sum, _, err := FileSHA256(path)
+	if err != nil {
+		return Meta{}, err
+	}
+	if sum != m.Output.SHA256 {
+		return Meta{}, fmt.Errorf("%s 自生成以来已被修改(sha256 与伴随文件不一致), 无法接续", path)
+	}
+
+	p, err := j.compile()
+	if err != nil {
+		return Meta{}, err
+	}
+	p.written = j.Rows
+	if needsState(j) && m.State == nil {
+		return Meta{}, fmt.Errorf("伴随文件缺少生成器状态, 无法接续")
+	}
+	if !j.Student.PerRow {
+		if m.State.Student == nil {
+			return Meta{}, fmt.Errorf("伴随文件缺少学生生成器状态, 无法接续")
+		}
+		if m.State.Student.Count != j.Rows {
+			return Meta{}, fmt.Errorf("生成器状态的记录数 %d 与文件行数 %d 不一致", m.State.Student.Count, j.Rows)
+		}
+		if p.students, err = generator.RestoreStudentGenerator(*m.State.Student); err != nil {
+			return Meta{}, err
+		}
+		if p.students.Fingerprint().String() != m.Fingerprint {
+			return Meta{}, fmt.Errorf("生成器状态的指纹 %s 与伴随文件记录的 %s 不一致", p.students.Fingerprint(), m.Fingerprint)
+		}
+	}
+	if p.dates != nil {
+		// 日期生成器总是按顺序抽取，PerRow 模式下也需要恢复其位置。
+		if err := p.dates.UnmarshalBinary(m.State.Dates); err != nil {
+			return Meta{}, fmt.Errorf("恢复日期生成器失败: %w", err)
+		}
+	}
+	return p.write(path, rows, workers, true, logger)
+}
+
+// needsState 报告接续 j 生成的文件是否需要保存生成器的位置：按行确定的学生列只需行号，
+// 顺序生成的学生列与日期列（无论是否 PerRow 都按顺序抽取）则需要。
+func needsState(j Job) bool { return !j.Student.PerRow || j.Dates != nil }
+
+func checkWorkers(j Job, workers int) error {
+	if workers > 1 && (!j.Student.PerRow || j.Contact != nil || j.Dates != nil) {
+		return fmt.Errorf("并发生成需要 per-row, 且不能与联系方式/日期列同时使用")
+	}
+	return nil
+}
+
+// write 写出第 p.written+1 至 total 行（appending 为 false 时 p.written 须为 0），返回文件的 Meta。
+func (p *plan) write(path string, total, workers int, appending bool, logger *slog.Logger) (Meta, error) {
+	opts := parser.CSVWriteOptions{Dialect: p.dialect, Logger: logger, Workers: workers, Append: appending}
+	if err := parser.WriteLargeCSVWithOptions(path, p.headers, total-p.written, p.row, opts); err != nil {
+		return Meta{}, err
+	}
+	sum, size, err := FileSHA256(path)
+	if err != nil {
+		return Meta{}, err
+	}
+
+	j := p.job
+	j.Student = p.students.Config()
+	j.Rows = total
+	m := Meta{
+		Tool:        currentTool(),
+		GeneratedAt: time.Now().UTC().Truncate(time.Second),
+		Fingerprint: p.students.Fingerprint().String(),
+		Generator:   p.students.Fingerprint(),
+		Job:         j,
+		Output:      Output{SHA256: sum, Bytes: size, Rows: total},
+	}
+	if needsState(j) {
+		m.State = &State{}
+		if !j.Student.PerRow {
+			st, err := p.students.Snapshot()
+			if err != nil {
+				return Meta{}, err
+			}
+			m.State.Student = &st
+		}
+		if p.dates != nil {
+			if m.State.Dates, err = p.dates.MarshalBinary(); err != nil {
+				return Meta{}, err
+			}
+		}
+	}
+	return m, nil
+}
+
+// ParseCarriers 解析逗号分隔的运营商列表，返回规范化的名称（忽略空白项）。
+func ParseCarriers(s string) ([]string, error) {
+	var out []string
+	for _, name := range strings.Split(s, ",") {
+		if strings.TrimSpace(name) == "" {
+			continue
+		}
+		c, err := contact.ParseCarrier(name)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, string(c))
+	}
+	return out, nil
+}
@@ -0,0 +1,163 @@
+package studentgen
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"runtime/debug"
+	"time"
+
+	"github.com/xianyudd/hanzi-data-kit/generator"
+)
+
+// MetaSuffix 为伴随文件的后缀：students.csv 的伴随文件为 students.csv.meta.json。
+const MetaSuffix = ".meta.json"
+
+// ErrMismatch 表示重新生成的文件与伴随文件记录的校验和不一致。
+var ErrMismatch = errors.New("重新生成的文件与记录的校验和不一致")
+
+// Meta 为伴随文件的内容：由哪个版本的工具、以什么参数生成了怎样的文件。
+type Meta struct {
+	Tool        Tool      `json:"tool"`
+	GeneratedAt time.Time `json:"generated_at"`
+
+	// Fingerprint 为 Generator 的紧凑表示，便于肉眼比对。
+	Fingerprint string                `json:"fingerprint"`
+	Generator   generator.Fingerprint `json:"generator"`
+
+	Job    Job    `json:"job"`
+	Output Output `json:"output"`
+
+	// State 为写完最后一行时各生成器的状态，供 Append 接续；Student.PerRow 且没有日期列时只需行号，为空。
+	State *State `json:"state,omitempty"`
+}
+
+// State 为学生CSV写完时各顺序生成器的状态。
+type State struct {
+	// Student 为学生生成器的状态；Student.PerRow 时每行只由行号决定，为空。
+	Student *generator.StudentGenState `json:"student,omitempty"`
+
+	// Dates 为日期生成器的随机数状态（见 generator.DateGenerator.MarshalBinary）。
+	Dates []byte `json:"dates,omitempty"`
+}
+
+// Tool 为生成文件的工具的构建信息（取自 runtime/debug.ReadBuildInfo）。
+type Tool struct {
+	Module  string `json:"module"`
+	Version string `json:"version"`
+
+	// Revision 为构建时的 VCS 提交（有未提交修改时带 +dirty）；go run 或无 VCS 信息时为空。
+	Revision string `json:"revision,omitempty"`
+
+	Go string `json:"go"`
+}
+
+// Output 描述生成的文件。
+type Output struct {
+	Rows   int    `json:"rows"`
+	Bytes  int64  `json:"bytes"`
+	SHA256 string `json:"sha256"`
+}
+
+func currentTool() Tool {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return Tool{Version: "unknown"}
+	}
+	t := Tool{Module: info.Main.Path, Version: info.Main.Version, Go: info.GoVersion}
+	var dirty bool
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			t.Revision = s.Value
+		case "vcs.modified":
+			dirty = s.Value == "true"
+		}
+	}
+	if t.Revision != "" && dirty {
+		t.Revision += "+dirty"
+	}
+	return t
+}
+
+// WriteMeta 将 m 以缩进 JSON 写入 path。
+func WriteMeta(path string, m Meta) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("写入伴随文件失败: %w", err)
+	}
+	return nil
+}
+
+// ReadMeta 读取 path 处的伴随文件。
+func ReadMeta(path string) (Meta, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Meta{}, fmt.Errorf("读取伴随文件失败: %w", err)
+	}
+	var m Meta
+	if err := json.Unmarshal(data, &m); err != nil {
+		return Meta{}, fmt.Errorf("解析伴随文件 %s 失败: %w", path, err)
+	}
+	if m.Job.Rows <= 0 || m.Output.SHA256 == "" {
+		return Meta{}, fmt.Errorf("伴随文件 %s 缺少行数或校验和", path)
+	}
+	return m, nil
+}
+
+// Reproduce 按 m.Job 重新生成到 path，并校验其 SHA-256 与 m.Output 一致，返回新文件的 Meta。
+// 不一致时返回的 error 包装 ErrMismatch，并指出生成算法版本或指纹是否已变化。
+func Reproduce(path string, m Meta, workers int, logger *slog.Logger) (Meta, error) {
+	if m.Generator.Version != 0 && m.Generator.Version != generator.GeneratorVersion {
+		loggerOrDiscard(logger).Warn("生成算法版本不同，可能无法复现",
+			"recorded", m.Generator.Version, "current", generator.GeneratorVersion)
+	}
+	got, err := Generate(path, m.Job, workers, logger)
+	if err != nil {
+		return Meta{}, err
+	}
+	if got.Output.SHA256 == m.Output.SHA256 {
+		return got, nil
+	}
+	switch {
+	case m.Generator.Version != got.Generator.Version:
+		err = fmt.Errorf("%w(生成算法版本 v%d → v%d)", ErrMismatch, m.Generator.Version, got.Generator.Version)
+	case m.Fingerprint != "" && m.Fingerprint != got.Fingerprint:
+		err = fmt.Errorf("%w(指纹 %s → %s)", ErrMismatch, m.Fingerprint, got.Fingerprint)
+	case m.Generator.Algorithm == generator.RNGMathRand:
+		err = fmt.Errorf("%w(%s 算法不保证跨版本稳定，建议改用 -rng pcg 等)", ErrMismatch, generator.RNGMathRand)
+	default:
+		err = ErrMismatch
+	}
+	return got, fmt.Errorf("%w: 记录 %s, 实际 %s", err, m.Output.SHA256, got.Output.SHA256)
+}
+
+// FileSHA256 返回文件内容的 SHA-256（十六进制）与字节数。
+func FileSHA256(path string) (string, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return "", 0, fmt.Errorf("计算校验和失败: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), n, nil
+}
+
+func loggerOrDiscard(l *slog.Logger) *slog.Logger {
+	if l == nil {
+		return slog.New(slog.DiscardHandler)
+	}
+	return l
+}
@@ -0,0 +1,169 @@
+package studentgen_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/xianyudd/hanzi-data-kit/generator"
+	"github.com/xianyudd/hanzi-data-kit/internal/studentgen"
+)
+
+func TestGenerateAndReproduce(t *testing.T) {
+	dir := t.TempDir()
+	jobs := map[string]studentgen.Job{
+		"full": {
+			Student: generator.StudentGenConfig{Seed: 3, RNG: generator.RNGPCG},
+			Rows:    200, Address: true, Headers: "cn",
+			Contact: &studentgen.ContactSpec{Carriers: []string{"mobile"}, MaskPhone: true},
+			Dates:   &studentgen.DateSpec{Reference: "2025-09-01", Location: "+08:00", EnrollmentAge: 18},
+			Dialect: studentgen.DialectSpec{Delimiter: ";", CRLF: true},
+		},
+		"per_row": {
+			Student: generator.StudentGenConfig{Seed: 3, PerRow: true},
+			Rows:    300, Offset: 1000, Headers: "en",
+			Dialect: studentgen.DialectSpec{Delimiter: ","},
+		},
+	}
+	for name, job := range jobs {
+		t.Run(name, func(t *testing.T) {
+			out := filepath.Join(dir, name+".csv")
+			meta, err := studentgen.Generate(out, job, 1, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if meta.Job.Student.AgeMin != 18 || len(meta.Job.Student.Cities) == 0 || meta.Job.Student.RNG == "" {
+				t.Errorf("伴随文件应记录补全默认值后的配置: %+v", meta.Job.Student)
+			}
+			if meta.Fingerprint != meta.Generator.String() || meta.Output.Rows != job.Rows || meta.Output.Bytes == 0 {
+				t.Errorf("meta = %+v", meta)
+			}
+			metaPath := out + studentgen.MetaSuffix
+			if err := studentgen.WriteMeta(metaPath, meta); err != nil {
+				t.Fatal(err)
+			}
+			read, err := studentgen.ReadMeta(metaPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			workers := 1
+			if job.Student.PerRow {
+				workers = 4
+			}
+			again := filepath.Join(dir, name+"-again.csv")
+			if _, err := studentgen.Reproduce(again, read, workers, nil); err != nil {
+				t.Fatalf("Reproduce: %v", err)
+			}
+			a, _ := os.ReadFile(out)
+			b, _ := os.ReadFile(again)
+			if string(a) != string(b) {
+				t.Fatal("重新生成的文件与原文件不同")
+			}
+
+			read.Job.Student.Seed++
+			if _, err := studentgen.Reproduce(again, read, 1, nil); !errors.Is(err, studentgen.ErrMismatch) {
+				t.Fatalf("修改 seed 后应返回 ErrMismatch, 实际为 %v", err)
+			}
+		})
+	}
+}
+
+func TestJobValidate(t *testing.T) {
+	base := studentgen.Job{Rows: 10, Headers: "cn", Dialect: studentgen.DialectSpec{Delimiter: ","}}
+	tests := []struct {
+		name   string
+		modify func(*studentgen.Job)
+	}{
+		{"rows", func(j *studentgen.Job) { j.Rows = 0 }},
+		{"offset without per-row", func(j *studentgen.Job) { j.Offset = 5 }},
+		{"offset with dates", func(j *studentgen.Job) {
+			j.Student.PerRow, j.Offset = true, 5
+			j.Dates = &studentgen.DateSpec{Location: "+08:00"}
+		}},
+		{"rng", func(j *studentgen.Job) { j.Student.RNG = "mt19937" }},
+		{"delimiter", func(j *studentgen.Job) { j.Dialect.Delimiter = ",;" }},
+		{"headers", func(j *studentgen.Job) { j.Headers = "jp" }},
+		{"carrier", func(j *studentgen.Job) { j.Contact = &studentgen.ContactSpec{Carriers: []string{"x"}} }},
+		{"tz", func(j *studentgen.Job) { j.Dates = &studentgen.DateSpec{Location: "Mars/Base"} }},
+	}
+	if err := base.Validate(); err != nil {
+		t.Fatalf("base: %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j := base
+			tt.modify(&j)
+			if err := j.Validate(); err == nil {
+				t.Error("应返回 error")
+			}
+		})
+	}
+}
+
+func TestAppend(t *testing.T) {
+	dir := t.TempDir()
+	jobs := map[string]studentgen.Job{
+		"sequential": {
+			Student: generator.StudentGenConfig{Seed: 9},
+			Rows:    500, Address: true, Headers: "cn",
+			Dates:   &studentgen.DateSpec{Reference: "2025-09-01", Location: "+08:00", EnrollmentAge: 18},
+			Dialect: studentgen.DialectSpec{Delimiter: ","},
+		},
+		"per_row": {
+			Student: generator.StudentGenConfig{Seed: 9, RNG: generator.RNGSplitMix64, PerRow: true},
+			Rows:    500, Offset: 3, Headers: "en",
+			Dialect: studentgen.DialectSpec{Delimiter: "\t", CRLF: true},
+		},
+		// 日期列在 PerRow 模式下仍按顺序抽取，需要保存其位置。
+		"per_row_dates": {
+			Student: generator.StudentGenConfig{Seed: 9, RNG: generator.RNGPCG, PerRow: true},
+			Rows:    500, Address: true, Headers: "cn",
+			Dates:   &studentgen.DateSpec{Reference: "2025-09-01", Location: "+08:00", EnrollmentAge: 18},
+			Dialect: studentgen.DialectSpec{Delimiter: ","},
+		},
+	}
+	for name, job := range jobs {
+		t.Run(name, func(t *testing.T) {
+			full := filepath.Join(dir, name+"-full.csv")
+			want, err := studentgen.Generate(full, job, 1, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			part := filepath.Join(dir, name+"-part.csv")
+			first := job
+			first.Rows = 123
+			meta, err := studentgen.Generate(part, first, 1, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if meta, err = studentgen.Append(part, meta, 300, 1, nil); err != nil {
+				t.Fatal(err)
+			}
+			if meta, err = studentgen.Append(part, meta, 500, 1, nil); err != nil {
+				t.Fatal(err)
+			}
+			if meta.Output != want.Output || !reflect.DeepEqual(meta.State, want.State) {
+				t.Errorf("追加后的 Meta 与一次生成的不同:\n%+v\n%+v", meta, want)
+			}
+			a, _ := os.ReadFile(full)
+			b, _ := os.ReadFile(part)
+			if string(a) != string(b) {
+				t.Fatal("追加后的文件与一次生成的不同")
+			}
+
+			if _, err := studentgen.Append(part, meta, 500, 1, nil); err == nil {
+				t.Error("总行数不大于已有行数时应返回 error")
+			}
+			if err := os.WriteFile(part, append(b, b[len(b)-2:]...), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := studentgen.Append(part, meta, 600, 1, nil); err == nil {
+				t.Error("文件被修改后应返回 error")
+			}
+		})
+	}
+}
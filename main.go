@@ -17,7 +17,7 @@ func main() {
 	)
 
 	fmt.Println(">>> 开始写入csv文件:", outPath)
-	headers := model.StudentHeadersCN() // 获取中文表头，未来我们也可以添加 StudentHeadersEN() 来支持英文表头
+	headers := model.StudentHeadersCN() // 获取中文表头；也可以改用 model.StudentHeadersEN() 输出英文表头，解析器同样能识别
 
 	gen := generator.NewStudentGenerator(generator.StudentGenConfig{
 		Seed:     seed,
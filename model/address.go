@@ -0,0 +1,31 @@
+package model
+
+// Address 为一个分级的中国地址：省/市/区县来自行政区划表，街道与门牌号为生成的虚构值。
+type Address struct {
+	Province string `json:"province"` // 省级全称，如 浙江省
+	City     string `json:"city"`     // 地级全称，如 杭州市；直辖市与 Province 相同
+	District string `json:"district"` // 县级全称，如 西湖区
+	// Code 为县级 GB/T 2260 区划代码，如 330106。
+	Code        string `json:"code"`
+	Street      string `json:"street"`       // 街道，如 文新街道
+	HouseNumber string `json:"house_number"` // 门牌号，如 文一路128号
+}
+
+// String 返回完整地址，如 "浙江省杭州市西湖区文新街道文一路128号"；直辖市不重复市名，如 "北京市朝阳区…"。
+func (a Address) String() string {
+	s := a.Province
+	if a.City != a.Province {
+		s += a.City
+	}
+	return s + a.District + a.Street + a.HouseNumber
+}
+
+// AddressHeadersCN 返回地址的中文表头（与 AddressToRowCN 对应）。
+func AddressHeadersCN() []string {
+	return []string{"省份", "城市", "区县", "区划代码", "街道", "门牌号"}
+}
+
+// AddressToRowCN 将 Address 映射为与 AddressHeadersCN 对应的一行。
+func AddressToRowCN(a Address) []string {
+	return []string{a.Province, a.City, a.District, a.Code, a.Street, a.HouseNumber}
+}
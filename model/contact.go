@@ -0,0 +1,14 @@
+package model
+
+// Contact 为一组联系方式。
+type Contact struct {
+	Phone    string `json:"phone"`    // 手机号，可能为脱敏形式（如 138****1234）
+	Email    string `json:"email"`    // 邮箱
+	Username string `json:"username"` // 用户名
+}
+
+// ContactHeadersCN 返回联系方式的中文表头（与 ContactToRowCN 对应）。
+func ContactHeadersCN() []string { return []string{"手机号", "邮箱", "用户名"} }
+
+// ContactToRowCN 将 Contact 映射为与 ContactHeadersCN 对应的一行。
+func ContactToRowCN(c Contact) []string { return []string{c.Phone, c.Email, c.Username} }
@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// DateLayout 与 DateTimeLayout 为写出 CSV 时日期与时间戳的格式。
+const (
+	DateLayout     = "2006-01-02"
+	DateTimeLayout = "2006-01-02 15:04:05"
+)
+
+// FormatDate 按 DateLayout 格式化 t；t 为零值时返回空串。
+func FormatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(DateLayout)
+}
+
+// FormatDateTime 按 DateTimeLayout 格式化 t（使用 t 自身的时区）；t 为零值时返回空串。
+func FormatDateTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(DateTimeLayout)
+}
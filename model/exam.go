@@ -0,0 +1,67 @@
+package model
+
+import (
+	"strconv"
+	"time"
+)
+
+// ExamSubject 为考试中的一个科目。
+type ExamSubject struct {
+	Name      string  `json:"name"`
+	FullMarks float64 `json:"full_marks"`
+}
+
+// ExamResult 为一名学生在一次考试中的各科成绩及派生字段。
+type ExamResult struct {
+	StudentID string `json:"student_id"`
+	Name      string `json:"name"`
+	ClassID   string `json:"class_id"`
+	SchoolID  string `json:"school_id"`
+
+	// Scores 为各科成绩，顺序与 Exam.Subjects 一致。
+	Scores []float64 `json:"scores"`
+
+	// 以下为派生字段，由 rank.ScoreExam 计算。
+	Total      float64 `json:"total"`
+	ClassRank  int     `json:"class_rank"`
+	SchoolRank int     `json:"school_rank"`
+	// Percentile 为总分在全体考生中的百分位（0~100，越高越好）。
+	Percentile float64 `json:"percentile"`
+	// Band 为按百分位划分的等级 A~E。
+	Band string `json:"band"`
+
+	// SubmittedAt 为交卷时间；未生成时为零值。
+	SubmittedAt time.Time `json:"submitted_at,omitzero"`
+}
+
+// Exam 为一次多科考试。
+type Exam struct {
+	Subjects []ExamSubject `json:"subjects"`
+	Results  []ExamResult  `json:"results"`
+}
+
+// ExamHeadersCN 返回考试成绩表的中文表头：学号、姓名、班级编号、学校编号、各科目名、总分、班级排名、学校排名、百分位、等级、交卷时间。
+func ExamHeadersCN(subjects []ExamSubject) []string {
+	h := []string{"学号", "姓名", "班级编号", "学校编号"}
+	for _, s := range subjects {
+		h = append(h, s.Name)
+	}
+	return append(h, "总分", "班级排名", "学校排名", "百分位", "等级", "交卷时间")
+}
+
+// ExamResultToRowCN 将 ExamResult 映射为与 ExamHeadersCN 对应的一行；分数保留 1 位小数，百分位保留 2 位小数，
+// 交卷时间按其自身时区以 DateTimeLayout 格式输出（未生成时为空）。
+func ExamResultToRowCN(r ExamResult) []string {
+	row := []string{r.StudentID, r.Name, r.ClassID, r.SchoolID}
+	for _, s := range r.Scores {
+		row = append(row, strconv.FormatFloat(s, 'f', 1, 64))
+	}
+	return append(row,
+		strconv.FormatFloat(r.Total, 'f', 1, 64),
+		strconv.Itoa(r.ClassRank),
+		strconv.Itoa(r.SchoolRank),
+		strconv.FormatFloat(r.Percentile, 'f', 2, 64),
+		r.Band,
+		FormatDateTime(r.SubmittedAt),
+	)
+}
@@ -0,0 +1,65 @@
+package model
+
+import "time"
+
+// School 为一所学校。
+type School struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	City string `json:"city"`
+}
+
+// Class 为学校中的一个班级；每个班级只属于一所学校。
+type Class struct {
+	ID       string `json:"id"`
+	SchoolID string `json:"school_id"`
+	Name     string `json:"name"`
+	// GradeLevel 为年级（从 1 开始）。
+	GradeLevel int `json:"grade_level"`
+}
+
+// Teacher 为学校中的一名教师，只教授一个科目。
+type Teacher struct {
+	ID       string `json:"id"`
+	SchoolID string `json:"school_id"`
+	Name     string `json:"name"`
+	Subject  string `json:"subject"`
+}
+
+// Course 为某个班级的一门课，由本校教授该科目的一名教师任教。
+type Course struct {
+	ID        string `json:"id"`
+	ClassID   string `json:"class_id"`
+	TeacherID string `json:"teacher_id"`
+	Subject   string `json:"subject"`
+}
+
+// SchoolStudent 为在籍学生：在 Student 的基础上增加学号、所在班级与出生/入学日期。
+// Student.City 为学校所在城市，Student.Score 为各科成绩的平均分。
+type SchoolStudent struct {
+	ID      string `json:"id"`
+	ClassID string `json:"class_id"`
+	Student
+
+	// BirthDate 与 Student.Age 一致（以生成时的参考日期计算周岁）。
+	BirthDate time.Time `json:"birth_date"`
+	// EnrollmentDate 为所在年级对应学年的开学报到日期。
+	EnrollmentDate time.Time `json:"enrollment_date"`
+}
+
+// Enrollment 为一名学生在一门课上的成绩；学生选修本班的全部课程。
+type Enrollment struct {
+	StudentID string  `json:"student_id"`
+	CourseID  string  `json:"course_id"`
+	Score     float64 `json:"score"`
+}
+
+// SchoolDataset 为一组外键一致的学校数据表。
+type SchoolDataset struct {
+	Schools     []School
+	Classes     []Class
+	Teachers    []Teacher
+	Courses     []Course
+	Students    []SchoolStudent
+	Enrollments []Enrollment
+}
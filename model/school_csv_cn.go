@@ -0,0 +1,56 @@
+package model
+
+import "strconv"
+
+// 以下 XxxHeadersCN / XxxToRowCN 与 StudentHeadersCN / StudentToRowCN 的约定相同：表头与行一一对应，分数保留 1 位小数。
+
+// SchoolHeadersCN 返回学校表的中文表头。
+func SchoolHeadersCN() []string { return []string{"学校编号", "学校名称", "城市"} }
+
+// SchoolToRowCN 将 School 映射为与 SchoolHeadersCN 对应的一行。
+func SchoolToRowCN(s School) []string { return []string{s.ID, s.Name, s.City} }
+
+// ClassHeadersCN 返回班级表的中文表头。
+func ClassHeadersCN() []string {
+	return []string{"班级编号", "学校编号", "班级名称", "年级"}
+}
+
+// ClassToRowCN 将 Class 映射为与 ClassHeadersCN 对应的一行。
+func ClassToRowCN(c Class) []string {
+	return []string{c.ID, c.SchoolID, c.Name, strconv.Itoa(c.GradeLevel)}
+}
+
+// TeacherHeadersCN 返回教师表的中文表头。
+func TeacherHeadersCN() []string { return []string{"教师编号", "学校编号", "姓名", "科目"} }
+
+// TeacherToRowCN 将 Teacher 映射为与 TeacherHeadersCN 对应的一行。
+func TeacherToRowCN(t Teacher) []string { return []string{t.ID, t.SchoolID, t.Name, t.Subject} }
+
+// CourseHeadersCN 返回课程表的中文表头。
+func CourseHeadersCN() []string {
+	return []string{"课程编号", "班级编号", "教师编号", "科目"}
+}
+
+// CourseToRowCN 将 Course 映射为与 CourseHeadersCN 对应的一行。
+func CourseToRowCN(c Course) []string { return []string{c.ID, c.ClassID, c.TeacherID, c.Subject} }
+
+// SchoolStudentHeadersCN 返回在籍学生表的中文表头：学号、班级编号，后接 StudentHeadersCN、出生日期、入学日期。
+// 该表可以直接交给 parser 按学生 CSV 解析，学号、班级编号与日期会作为附加列保留。
+func SchoolStudentHeadersCN() []string {
+	h := append([]string{"学号", "班级编号"}, StudentHeadersCN()...)
+	return append(h, "出生日期", "入学日期")
+}
+
+// SchoolStudentToRowCN 将 SchoolStudent 映射为与 SchoolStudentHeadersCN 对应的一行；日期格式为 DateLayout。
+func SchoolStudentToRowCN(s SchoolStudent) []string {
+	row := append([]string{s.ID, s.ClassID}, StudentToRowCN(s.Student)...)
+	return append(row, FormatDate(s.BirthDate), FormatDate(s.EnrollmentDate))
+}
+
+// EnrollmentHeadersCN 返回成绩表的中文表头。
+func EnrollmentHeadersCN() []string { return []string{"学号", "课程编号", "成绩"} }
+
+// EnrollmentToRowCN 将 Enrollment 映射为与 EnrollmentHeadersCN 对应的一行。
+func EnrollmentToRowCN(e Enrollment) []string {
+	return []string{e.StudentID, e.CourseID, strconv.FormatFloat(e.Score, 'f', 1, 64)}
+}
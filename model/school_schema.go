@@ -0,0 +1,64 @@
+package model
+
+// ColumnSchema 描述数据表中的一列。
+type ColumnSchema struct {
+	Name string `json:"name"`
+	// Type 为逻辑类型：string、integer、number、date（格式 DateLayout）或 datetime（格式 DateTimeLayout）。
+	Type string `json:"type"`
+	// PrimaryKey 为 true 表示该列是（或属于）主键。
+	PrimaryKey bool `json:"primary_key,omitempty"`
+	// References 为外键引用的目标，形如 "classes.班级编号"；不是外键时为空。
+	References  string `json:"references,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// TableSchema 描述一张数据表；Columns 的顺序与对应的 XxxHeadersCN 一致。
+type TableSchema struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Columns     []ColumnSchema `json:"columns"`
+}
+
+// SchoolSchema 返回 SchoolDataset 各表的结构描述，按外键依赖顺序排列（被引用的表在前）。
+func SchoolSchema() []TableSchema {
+	return []TableSchema{
+		{Name: "schools", Description: "学校", Columns: []ColumnSchema{
+			{Name: "学校编号", Type: "string", PrimaryKey: true},
+			{Name: "学校名称", Type: "string"},
+			{Name: "城市", Type: "string"},
+		}},
+		{Name: "classes", Description: "班级，每个班级属于一所学校", Columns: []ColumnSchema{
+			{Name: "班级编号", Type: "string", PrimaryKey: true},
+			{Name: "学校编号", Type: "string", References: "schools.学校编号"},
+			{Name: "班级名称", Type: "string"},
+			{Name: "年级", Type: "integer", Description: "从 1 开始"},
+		}},
+		{Name: "teachers", Description: "教师，每名教师属于一所学校并教授一个科目", Columns: []ColumnSchema{
+			{Name: "教师编号", Type: "string", PrimaryKey: true},
+			{Name: "学校编号", Type: "string", References: "schools.学校编号"},
+			{Name: "姓名", Type: "string"},
+			{Name: "科目", Type: "string"},
+		}},
+		{Name: "courses", Description: "课程，每个班级每个科目一门，由本校教授该科目的教师任教", Columns: []ColumnSchema{
+			{Name: "课程编号", Type: "string", PrimaryKey: true},
+			{Name: "班级编号", Type: "string", References: "classes.班级编号"},
+			{Name: "教师编号", Type: "string", References: "teachers.教师编号"},
+			{Name: "科目", Type: "string"},
+		}},
+		{Name: "students", Description: "在籍学生，每名学生属于一个班级", Columns: []ColumnSchema{
+			{Name: "学号", Type: "string", PrimaryKey: true},
+			{Name: "班级编号", Type: "string", References: "classes.班级编号"},
+			{Name: "姓名", Type: "string"},
+			{Name: "年龄", Type: "integer"},
+			{Name: "城市", Type: "string", Description: "学校所在城市"},
+			{Name: "得分", Type: "number", Description: "各科成绩的平均分"},
+			{Name: "出生日期", Type: "date", Description: "与年龄一致"},
+			{Name: "入学日期", Type: "date", Description: "所在年级对应学年的开学报到日期"},
+		}},
+		{Name: "enrollments", Description: "成绩，每名学生在本班每门课上一条", Columns: []ColumnSchema{
+			{Name: "学号", Type: "string", PrimaryKey: true, References: "students.学号"},
+			{Name: "课程编号", Type: "string", PrimaryKey: true, References: "courses.课程编号"},
+			{Name: "成绩", Type: "number"},
+		}},
+	}
+}
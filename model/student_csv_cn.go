@@ -7,6 +7,7 @@ func StudentHeadersCN() []string {
 	return []string{"姓名", "年龄", "城市", "得分"}
 }
 
+// StudentToRowCN 将 Student 映射为与 StudentHeadersCN 对应的一行；得分固定保留 1 位小数。
 func StudentToRowCN(stu Student) []string {
 	return []string{
 		stu.Name,
@@ -0,0 +1,12 @@
+package model
+
+// StudentHeadersEN 返回英文的CSV表头（与 Student 结构体字段顺序一致）。
+func StudentHeadersEN() []string {
+	return []string{"Name", "Age", "City", "Score"}
+}
+
+// StudentToRowEN 将 Student 映射为与 StudentHeadersEN 对应的一行。
+// 单元格格式与 StudentToRowCN 完全一致，仅表头语言不同。
+func StudentToRowEN(stu Student) []string {
+	return StudentToRowCN(stu)
+}
@@ -1,13 +1,16 @@
 package parser
 
 import (
+	"bufio"
 	"encoding/csv"
+	"errors"
 	"fmt"
 	"github.com/xianyudd/hanzi-data-kit/model"
-	"log"
+	"io"
+	"log/slog"
 	"os"
-	"strconv"
 	"strings"
+	"time"
 )
 
 // CSVParseOptions 控制 CSV 解析行为。
@@ -22,6 +25,73 @@ type CSVParseOptions struct {
 	// SkipBadRows 为 true 时，遇到脏数据行会跳过继续解析；
 	// 为 false 时，遇到第一条脏数据就返回 error（严格模式）。
 	SkipBadRows bool
+
+	// Dialect 指定分隔符、注释符、引号宽松度、字段数策略等；零值为标准逗号 CSV。
+	Dialect Dialect
+
+	// SniffDialect 为 true 时忽略 Dialect，改为根据文件前若干 KB 自动推断方言（见 SniffDialect）。
+	SniffDialect bool
+
+	// HeaderAliases 为 “标准中文列名 -> 别名列表” 的字典，匹配时忽略大小写与空白。
+	// 为 nil 时使用 DefaultStudentHeaderAliases()；传入空 map 则只接受标准列名。
+	HeaderAliases map[string][]string
+
+	// ColumnPolicies 为标准列名（姓名/年龄/城市/得分）指定出现策略；未登记的列默认为 ColumnRequired。
+	ColumnPolicies map[string]ColumnPolicy
+
+	// DuplicateHeaders 指定同一列（含别名）在表头中出现多次时的处理策略；零值为 DuplicateHeaderFirstWins。
+	// 该策略只作用于标准列与丢弃附加列的解析；保留附加列时重名的附加列总是返回 error（见 StudentRecord.Extra）。
+	DuplicateHeaders DuplicateHeaderPolicy
+
+	// OnWarning 若非 nil，则每产生一条非致命告警（重复列、空表头、被跳过的坏行）都会同步回调一次。
+	// 无论是否设置，告警都会汇总到 StudentTable.Warnings 中。
+	OnWarning func(ParseWarning)
+
+	// Logger 用于输出结构化日志（属性含 file/line/column/reason）；为 nil 时丢弃所有日志。
+	Logger *slog.Logger
+
+	// Encoding 为源文件的字符编码：""/"utf-8"、"gbk"、"gb18030"、"utf-16"（依据 BOM 判断字节序），
+	// 或 "auto"（样本是合法 UTF-8 时按 UTF-8，否则按 GB18030）。解码后的文本统一为 UTF-8。
+	Encoding string
+
+	// DistrictColumn 若非空，则为区县列的表头（如 "区县"）：每行的 城市/区县 组合必须真实存在于
+	// region 包的行政区划表中，否则按坏行处理（宽松模式跳过并告警，严格模式报错）。区县为空的行不做检查。
+	DistrictColumn string
+
+	// ColumnValidators 为 “列表头 -> 格式校验函数”，如 {"手机号": contact.ValidatePhone}；
+	// 每行中这些列的非空值都须通过校验，否则按坏行处理。列必须存在于表头中。
+	ColumnValidators map[string]func(string) error
+
+	// DateColumns 为日期列的表头（如 "出生日期"）：每行中这些列的非空值须能按 DateLayouts 解析，否则按坏行处理；
+	// 解析成功的值在 StudentRecord.Extra 中被规范化为 2006-01-02（带时刻时为 2006-01-02 15:04:05）。列必须存在于表头中。
+	DateColumns []string
+
+	// DateLayouts 为解析日期列时依次尝试的布局（time 包格式，或 LayoutExcelSerial）；为空时使用 DefaultDateLayouts()。
+	DateLayouts []string
+
+	// DateLocation 为不含时区的日期所在的时区；为 nil 时为 UTC。
+	DateLocation *time.Location
+}
+
+// ColumnPresence 描述某一列在表头中的出现要求。
+type ColumnPresence int
+
+const (
+	// ColumnRequired 该列必须出现，否则返回“缺少必需列”错误（零值，即默认策略）。
+	ColumnRequired ColumnPresence = iota
+	// ColumnOptional 该列可以缺省；缺省时每行都使用 ColumnPolicy.Default 作为原始值参与解析。
+	ColumnOptional
+	// ColumnForbidden 该列不允许出现，出现则返回错误。
+	ColumnForbidden
+)
+
+// ColumnPolicy 为单个标准列的出现策略。
+type ColumnPolicy struct {
+	Presence ColumnPresence
+
+	// Default 为 ColumnOptional 且列缺省时使用的原始单元格值（如年龄缺省可设为 "0"）。
+	// 姓名/年龄/得分 为 ColumnOptional 或 ColumnForbidden 时，Default 须分别为非空、整数、数值，否则构造解析器时返回 error。
+	Default string
 }
 
 func defaultCSVParseOptions() CSVParseOptions {
@@ -29,6 +99,7 @@ func defaultCSVParseOptions() CSVParseOptions {
 		TrimSpace:   true,
 		AllowBOM:    true,
 		SkipBadRows: false, // 默认保持你当前行为：严格
+		Dialect:     DefaultDialect(),
 	}
 }
 
@@ -39,18 +110,25 @@ func ParseCSVToStudents(filename string) ([]model.Student, error) {
 
 // ParseCSVToStudentsWithOptions 读取 CSV 文件并解析为 Student 切片（可配置解析策略）。
 //
-// 该解析器按“中文表头”映射字段，因此列顺序可以变化，但必须包含以下列：
+// 该解析器按“中文表头”映射字段，因此列顺序可以变化，默认必须包含以下列（可通过 ColumnPolicies 放宽或禁止）：
 //   - 姓名
 //   - 年龄
 //   - 城市
 //   - 得分
 //
+// 表头还可以使用 HeaderAliases 中登记的别名（默认包含英文表头 Name/Age/City/Score），
+// 匹配时忽略大小写与空白；缺列时若存在拼写相近的表头，错误信息会给出提示。
+//
+// 未识别的列会被忽略；如需保留，请使用 ParseCSVToStudentTable。
+// 重复列按 DuplicateHeaders 处理（默认第一个生效），空表头列会被忽略；可用 DiagnoseHeaders 预先检查表头。
+//
 // 行级策略：
 //   - 空行/全空字段行：跳过
 //   - 姓名缺失：严格模式返回 error；宽松模式（SkipBadRows=true）跳过该行
 //   - 年龄/得分解析失败：严格模式返回 error；宽松模式跳过该行
 //
 // 兼容性：
+//   - Dialect 控制分隔符（如 ; 或 \t）、# 注释行、宽松引号与可变字段数；SniffDialect=true 时自动推断
 //   - AllowBOM=true 时会自动剥离 UTF-8 BOM（常见于 Excel 导出的 CSV 表头）
 //   - TrimSpace=true 时会对表头与单元格做 TrimSpace
 //
@@ -58,95 +136,136 @@ func ParseCSVToStudents(filename string) ([]model.Student, error) {
 //   - 成功时返回解析得到的 students（顺序与文件行顺序一致，不含表头行）
 //   - 失败时返回 error，并尽可能包含行号与原始值，便于定位数据问题
 func ParseCSVToStudentsWithOptions(filename string, opts CSVParseOptions) ([]model.Student, error) {
-	file, err := os.Open(filename)
+	table, err := parseStudentTable(filename, opts, false)
 	if err != nil {
-		return nil, fmt.Errorf("打开文件失败: %v", err)
+		return nil, err
 	}
-	defer file.Close()
+	return table.Students(), nil
+}
 
-	reader := csv.NewReader(file)
-	records, err := reader.ReadAll()
+// ParseCSVToStudentTable 与 ParseCSVToStudentsWithOptions 的解析规则相同，
+// 但返回带行号的记录，并把未识别的列保存在每条记录的 Extra 中（表头顺序见 ExtraHeaders），
+// 以便配合 WriteStudentTableCSV 在“解析 → 变换 → 写出”的链路中不丢失合作方的附加列。
+func ParseCSVToStudentTable(filename string, opts CSVParseOptions) (*StudentTable, error) {
+	return parseStudentTable(filename, opts, true)
+}
+
+// openCSV 打开 filename 并按 opts 中的方言（或嗅探结果）构造 csv.Reader；调用方负责关闭返回的文件。
+func openCSV(filename string, opts CSVParseOptions) (*os.File, *csv.Reader, error) {
+	file, err := os.Open(filename)
 	if err != nil {
-		return nil, fmt.Errorf("读取CSV失败: %s, 错误: %v", filename, err)
+		return nil, nil, fmt.Errorf("打开文件失败: %v", err)
 	}
-	if len(records) == 0 {
-		return nil, fmt.Errorf("CSV文件为空: %s", filename)
+	reader, err := newCSVReader(file, filename, opts)
+	if err != nil {
+		file.Close()
+		return nil, nil, err
 	}
+	return file, reader, nil
+}
 
-	hdr := records[0]
-	idx := headerIndex(hdr, opts.TrimSpace, opts.AllowBOM)
+// newCSVReader 对 r 做字符解码，并按 opts 中的方言（或嗅探结果）构造 csv.Reader；name 仅用于错误信息。
+func newCSVReader(r io.Reader, name string, opts CSVParseOptions) (*csv.Reader, error) {
+	decoded, err := decodeReader(r, opts.Encoding)
+	if err != nil {
+		return nil, err
+	}
 
-	required := []string{"姓名", "年龄", "城市", "得分"}
-	for _, col := range required {
-		if _, ok := idx[col]; !ok {
-			return nil, fmt.Errorf("缺少必需列: %s", col)
+	br := bufio.NewReaderSize(decoded, sniffSampleSize)
+	dialect := opts.Dialect
+	if opts.SniffDialect {
+		dialect, err = sniffBuffered(br)
+		if errors.Is(err, ErrSniffFailed) {
+			// 样本为空：交给调用方的“CSV文件为空”分支给出更明确的错误。
+			dialect, err = DefaultDialect(), nil
 		}
+		if err != nil {
+			return nil, fmt.Errorf("推断CSV方言失败: %s, 错误: %w", name, err)
+		}
+	}
+	if err := dialect.Validate(); err != nil {
+		return nil, fmt.Errorf("CSV方言配置错误: %w", err)
 	}
+	return dialect.newReader(br), nil
+}
 
-	// 逐行解析 (跳过表头)
-	student := make([]model.Student, 0, len(records)-1)
-	for line := 2; line <= len(records); line++ {
-		row := records[line-1]
+// DiagnoseHeaders 只读取 filename 的表头行，报告重复列、空表头与未识别的列。
+// 表头识别规则（别名、BOM、TrimSpace、方言）与 ParseCSVToStudentsWithOptions 一致。
+func DiagnoseHeaders(filename string, opts CSVParseOptions) (*HeaderReport, error) {
+	file, reader, err := openCSV(filename, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
 
-		//空行/全空字段：跳过
-		nonEmpty := false
-		for _, cell := range row {
-			if opts.TrimSpace {
-				cell = strings.TrimSpace(cell)
-			}
-			if cell != "" {
-				nonEmpty = true
-				break
-			}
-		}
-		if !nonEmpty {
-			log.Printf("跳过空行: 第 %d 行\n", line)
-			continue
-		}
+	// 表头诊断只关心第一行，不对后续行的字段数做校验。
+	reader.FieldsPerRecord = -1
+	hdr, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("CSV文件为空: %s", filename)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取CSV失败: %s, 错误: %w", filename, err)
+	}
 
-		name, ok := getCell(row, idx, "姓名", opts.TrimSpace)
-		if !ok || name == " " {
-			if opts.SkipBadRows {
-				continue
-			}
-			return nil, fmt.Errorf("缺少姓名(第%d行)", line)
-		}
-		ageStr, _ := getCell(row, idx, "年龄", opts.TrimSpace)
-		city, _ := getCell(row, idx, "城市", opts.TrimSpace)
-		scoreStr, _ := getCell(row, idx, "得分", opts.TrimSpace)
+	aliases := opts.HeaderAliases
+	if aliases == nil {
+		aliases = DefaultStudentHeaderAliases()
+	}
+	_, report := headerIndex(hdr, opts.TrimSpace, opts.AllowBOM, headerAliasLookup(model.StudentHeadersCN(), aliases))
+	return &report, nil
+}
 
-		age, err := strconv.Atoi(ageStr)
-		if err != nil {
-			if opts.SkipBadRows {
-				continue
-			}
-			return nil, fmt.Errorf("解析年龄失败(第%d行, 值=%q): %w", line, ageStr, err)
-		}
+// ParseCSVReaderToStudentTable 与 ParseCSVToStudentTable 相同，但从 r 读取（如 HTTP 上传的内容）；
+// name 仅用于错误信息与日志。
+func ParseCSVReaderToStudentTable(r io.Reader, name string, opts CSVParseOptions) (*StudentTable, error) {
+	return collectStudentTable(opts, func(opts CSVParseOptions) (*StudentScanner, error) {
+		return NewStudentScannerFromReader(r, name, opts)
+	})
+}
 
-		score, err := strconv.ParseFloat(scoreStr, 64)
-		if err != nil {
-			if opts.SkipBadRows {
-				continue
-			}
-			return nil, fmt.Errorf("解析得分失败(第%d行, 值=%q): %w", line, scoreStr, err)
-		}
+func parseStudentTable(filename string, opts CSVParseOptions, keepExtra bool) (*StudentTable, error) {
+	return collectStudentTable(opts, func(opts CSVParseOptions) (*StudentScanner, error) {
+		return newStudentScanner(filename, opts, keepExtra)
+	})
+}
 
-		student = append(student, model.Student{
-			Name:  name,
-			Age:   age,
-			City:  city,
-			Score: score,
-		})
+// collectStudentTable 用 open 构造的扫描器读取全部记录；告警在回调 opts.OnWarning 的同时汇总到表中。
+func collectStudentTable(opts CSVParseOptions, open func(CSVParseOptions) (*StudentScanner, error)) (*StudentTable, error) {
+	table := &StudentTable{}
+	onWarning := opts.OnWarning
+	opts.OnWarning = func(w ParseWarning) {
+		table.Warnings = append(table.Warnings, w)
+		if onWarning != nil {
+			onWarning(w)
+		}
 	}
 
-	return student, nil
+	sc, err := open(opts)
+	if err != nil {
+		return nil, err
+	}
+	defer sc.Close()
 
+	table.Header = sc.Header()
+	table.ExtraHeaders = sc.ExtraHeaders()
+	for {
+		rec, err := sc.Next()
+		if errors.Is(err, io.EOF) {
+			return table, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		table.Records = append(table.Records, rec)
+	}
 }
 
-// headerIndex 根据表头行构建 “列名 -> 下标” 的索引。
-// 会对列名做 TrimSpace，并忽略空列名。
-func headerIndex(headers []string, trimSpace bool, allowBOM bool) map[string]int {
-	idx := make(map[string]int, len(headers))
+// headerIndex 根据表头行构建 “列名 -> 全部出现位置（0-based 下标）” 的索引，并给出表头诊断。
+// 能通过 lookup（规范化别名 -> 标准列名）识别的表头以标准列名登记，其余表头按原文登记并计入 report.Unknown。
+// 会对列名做 TrimSpace，并忽略空列名（计入 report.EmptyColumns）。
+func headerIndex(headers []string, trimSpace bool, allowBOM bool, lookup map[string]string) (idx map[string][]int, report HeaderReport) {
+	idx = make(map[string][]int, len(headers))
 	for i, h := range headers {
 		if allowBOM && i == 0 {
 			h = stripBOM(h)
@@ -156,21 +275,65 @@ func headerIndex(headers []string, trimSpace bool, allowBOM bool) map[string]int
 			h = strings.TrimSpace(h)
 		}
 
-		if h == " " {
+		if strings.TrimSpace(h) == "" {
+			report.EmptyColumns = append(report.EmptyColumns, i+1)
+			continue
+		}
+		if col, ok := lookup[NormalizeHeader(h)]; ok {
+			h = col
+		} else if _, seen := idx[h]; !seen {
+			report.Unknown = append(report.Unknown, h)
+		}
+		idx[h] = append(idx[h], i)
+	}
+
+	for h, positions := range idx {
+		if len(positions) < 2 {
 			continue
 		}
-		// 只记录第一个出现的表头位置，后续重复的表头会被忽略
-		if _, exists := idx[h]; !exists {
-			idx[h] = i
+		if report.Duplicates == nil {
+			report.Duplicates = make(map[string][]int)
+		}
+		for _, i := range positions {
+			report.Duplicates[h] = append(report.Duplicates[h], i+1)
 		}
 	}
-	return idx
+	return idx, report
+}
+
+// getCell 按列名安全取值；列出现多次时按 policy 选择其中一个位置。
+// 若列不存在或下标越界，返回 ok=false。
+func getCell(row []string, idx map[string][]int, col string, policy DuplicateHeaderPolicy, trimSpace bool) (val string, ok bool) {
+	positions := idx[col]
+	if len(positions) == 0 {
+		return "", false
+	}
+	switch policy {
+	case DuplicateHeaderLastWins:
+		positions = positions[len(positions)-1:]
+	case DuplicateHeaderMerge:
+		// 合并：取第一个非空值；全部为空时退化为第一个位置。
+		for _, i := range positions {
+			if v, ok := cellAt(row, i, trimSpace); ok && v != "" {
+				return v, true
+			}
+		}
+	}
+	return cellAt(row, positions[0], trimSpace)
+}
+
+// getCells 返回列 col 所有出现位置上的值（越界的位置记为空字符串）。
+func getCells(row []string, idx map[string][]int, col string, trimSpace bool) []string {
+	vals := make([]string, 0, len(idx[col]))
+	for _, i := range idx[col] {
+		v, _ := cellAt(row, i, trimSpace)
+		vals = append(vals, v)
+	}
+	return vals
 }
 
-// getCell 按列名安全取值；若列不存在或下标越界，返回 ok=false。
-func getCell(row []string, idx map[string]int, col string, trimSpace bool) (val string, ok bool) {
-	i, exists := idx[col]
-	if !exists || i < 0 || i >= len(row) {
+func cellAt(row []string, i int, trimSpace bool) (string, bool) {
+	if i < 0 || i >= len(row) {
 		return "", false
 	}
 	v := row[i]
@@ -1,12 +1,19 @@
 package parser_test
 
 import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 
+	"github.com/xianyudd/hanzi-data-kit/contact"
 	"github.com/xianyudd/hanzi-data-kit/model"
 	"github.com/xianyudd/hanzi-data-kit/parser"
+	"golang.org/x/text/encoding/simplifiedchinese"
 )
 
 // writeTempFile 在临时目录写入文件并返回路径。
@@ -84,6 +91,31 @@ func TestParseCSVToStudents_TableDriven(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name:    "english_headers_ok",
+			content: []byte("Name,Age,City,Score\n张三,22,北京,95.0\n"),
+			opts:    nil,
+			want: []model.Student{
+				{Name: "张三", Age: 22, City: "北京", Score: 95.0},
+			},
+		},
+		{
+			name:    "header_alias_case_and_space_insensitive_ok",
+			content: []byte("学生 姓名, AGE ,所在城市,成绩\n张三,22,北京,95.0\n"),
+			opts:    nil,
+			want: []model.Student{
+				{Name: "张三", Age: 22, City: "北京", Score: 95.0},
+			},
+		},
+		{
+			name:    "custom_alias_only_returns_error_for_default_alias",
+			content: []byte("name,年龄,城市,得分\n张三,22,北京,95.0\n"),
+			opts: &parser.CSVParseOptions{
+				TrimSpace:     true,
+				HeaderAliases: map[string][]string{}, // 空 map：只接受标准列名
+			},
+			wantErr: true,
+		},
 		{
 			name:    "missing_required_header_returns_error",
 			content: []byte("姓名,年龄,城市\n张三,22,北京\n"), // 缺“得分”
@@ -135,3 +167,154 @@ func TestParseCSVToStudents_TableDriven(t *testing.T) {
 		})
 	}
 }
+
+func TestParseCSVToStudents_MissingHeaderSuggestion(t *testing.T) {
+	path := writeTempFile(t, "in.csv", []byte("姓名,年龄,城市,scroe\n张三,22,北京,95.0\n"))
+
+	_, err := parser.ParseCSVToStudents(path)
+	if err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), "得分") || !strings.Contains(err.Error(), `"scroe"`) {
+		t.Fatalf("error should name the missing column and suggest the close header, got: %v", err)
+	}
+}
+
+func TestParseCSVToStudents_StructuredLogging(t *testing.T) {
+	path := writeTempFile(t, "in.csv", []byte("姓名,年龄,城市,得分\n张三,22,北京,95.0\n,,,\n李四,x,上海,88.0\n"))
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	_, err := parser.ParseCSVToStudentsWithOptions(path, parser.CSVParseOptions{
+		TrimSpace:   true,
+		SkipBadRows: true,
+		Logger:      logger,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var entries []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var e map[string]any
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("log line is not JSON: %q", line)
+		}
+		entries = append(entries, e)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 log entries, got %d: %s", len(entries), buf.String())
+	}
+	// 空行：Debug；坏行：Warn，且带有 file/line/column/reason 属性。
+	if e := entries[0]; e["level"] != "DEBUG" || e["line"] != float64(3) || e["file"] != path {
+		t.Fatalf("blank-row entry mismatch: %v", e)
+	}
+	if e := entries[1]; e["level"] != "WARN" || e["line"] != float64(4) || e["column"] != "年龄" || e["reason"] != "row_skipped" {
+		t.Fatalf("bad-row entry mismatch: %v", e)
+	}
+}
+
+func TestParseCSVToStudents_Encoding(t *testing.T) {
+	gbk, err := simplifiedchinese.GBK.NewEncoder().Bytes([]byte("姓名,年龄,城市,得分\n张三,22,北京,95.0\n"))
+	if err != nil {
+		t.Fatalf("encode gbk failed: %v", err)
+	}
+	want := model.Student{Name: "张三", Age: 22, City: "北京", Score: 95.0}
+
+	for _, enc := range []string{"gbk", "auto"} {
+		path := writeTempFile(t, "in.csv", gbk)
+		got, err := parser.ParseCSVToStudentsWithOptions(path, parser.CSVParseOptions{TrimSpace: true, Encoding: enc})
+		if err != nil {
+			t.Fatalf("encoding=%s: unexpected error: %v", enc, err)
+		}
+		if len(got) != 1 || got[0] != want {
+			t.Fatalf("encoding=%s: got %#v", enc, got)
+		}
+	}
+}
+
+func TestParseCSVToStudentTable_DistrictColumn(t *testing.T) {
+	content := "姓名,年龄,城市,得分,区县\n张三,18,杭州,90,西湖区\n李四,19,北京,80,西湖区\n王五,20,北京市,70,海淀区\n赵六,21,上海,60,\n钱七,22,杭州,50,桐庐县\n"
+	path := writeTempFile(t, "in.csv", []byte(content))
+
+	table, err := parser.ParseCSVToStudentTable(path, parser.CSVParseOptions{TrimSpace: true, SkipBadRows: true, DistrictColumn: "区县"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(table.Records) != 4 {
+		t.Fatalf("records = %d, want 4", len(table.Records))
+	}
+	if len(table.Warnings) != 1 || table.Warnings[0].Line != 3 || table.Warnings[0].Column != "区县" {
+		t.Fatalf("warnings = %v", table.Warnings)
+	}
+
+	if _, err := parser.ParseCSVToStudentTable(path, parser.CSVParseOptions{TrimSpace: true, DistrictColumn: "区县"}); err == nil || !strings.Contains(err.Error(), "第3行") {
+		t.Errorf("严格模式 err = %v", err)
+	}
+	if _, err := parser.ParseCSVToStudentTable(path, parser.CSVParseOptions{TrimSpace: true, DistrictColumn: "区"}); err == nil {
+		t.Error("区县列不存在时应返回 error")
+	}
+}
+
+func TestParseCSVToStudentTable_ColumnValidators(t *testing.T) {
+	content := "姓名,年龄,城市,得分,手机号,邮箱\n张三,18,杭州,90,13812341234,zhangsan@qq.com\n李四,19,北京,80,12012341234,lisi@qq.com\n王五,20,上海,70,,wangwu@\n赵六,21,上海,60,,\n"
+	path := writeTempFile(t, "in.csv", []byte(content))
+	validators := map[string]func(string) error{"手机号": contact.ValidatePhone, "邮箱": contact.ValidateEmail}
+
+	table, err := parser.ParseCSVToStudentTable(path, parser.CSVParseOptions{TrimSpace: true, SkipBadRows: true, ColumnValidators: validators})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(table.Records) != 2 {
+		t.Fatalf("records = %d, want 2", len(table.Records))
+	}
+	if len(table.Warnings) != 2 || table.Warnings[0].Column != "手机号" || table.Warnings[1].Column != "邮箱" {
+		t.Fatalf("warnings = %v", table.Warnings)
+	}
+
+	if _, err := parser.ParseCSVToStudentTable(path, parser.CSVParseOptions{TrimSpace: true, ColumnValidators: validators}); err == nil || !strings.Contains(err.Error(), "第3行") {
+		t.Errorf("严格模式 err = %v", err)
+	}
+	validators["用户名"] = contact.ValidateUsername
+	if _, err := parser.ParseCSVToStudentTable(path, parser.CSVParseOptions{TrimSpace: true, ColumnValidators: validators}); err == nil {
+		t.Error("待校验的列不存在时应返回 error")
+	}
+}
+
+// ScanStudents 逐条产出的学生与 ParseCSVToStudentsWithOptions 一致，且忽略（可能重名的）附加列。
+func TestScanStudents(t *testing.T) {
+	path := writeTempFile(t, "in.csv", []byte("姓名,年龄,城市,得分,备注,备注\n张三,18,北京,90,a,b\n李四,x,上海,80,,\n王五,20,广州,70,,\n"))
+	opts := parser.CSVParseOptions{TrimSpace: true, SkipBadRows: true}
+
+	want, err := parser.ParseCSVToStudentsWithOptions(path, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []model.Student
+	for stu, err := range parser.ScanStudents(path, opts) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, stu)
+	}
+	if !slices.Equal(got, want) || len(got) != 2 {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	opts.SkipBadRows = false
+	var errs int
+	for _, err := range parser.ScanStudents(path, opts) {
+		if err != nil {
+			errs++
+		}
+	}
+	if errs != 1 {
+		t.Errorf("严格模式应在坏行处产出一个 error 后结束, 实际 %d 个", errs)
+	}
+	for _, err := range parser.ScanStudents(filepath.Join(t.TempDir(), "missing.csv"), opts) {
+		if err == nil {
+			t.Error("文件不存在时应产出 error")
+		}
+	}
+}
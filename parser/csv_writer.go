@@ -1,18 +1,47 @@
 package parser
 
 import (
-	"encoding/csv"
 	"fmt"
+	"io"
+	"iter"
+	"log/slog"
 	"os"
+	"slices"
+	"sync"
 )
 
+// CSVWriteOptions 控制 CSV 写出行为。
+type CSVWriteOptions struct {
+	// Dialect 指定分隔符、换行风格（UseCRLF）与是否强制加引号（AlwaysQuote）；零值为标准逗号 CSV。
+	Dialect Dialect
+
+	// Logger 用于输出写入进度等结构化日志；为 nil 时丢弃所有日志。
+	Logger *slog.Logger
+
+	// Workers 大于 1 时，WriteLargeCSVWithOptions 用这么多个 goroutine 分块并发调用 rowGenerator，
+	// 写出顺序仍与行号一致。此时 rowGenerator 必须可并发调用，且结果只取决于行号
+	// （如 generator.StudentGenerator.At）。对其他写出函数无效。
+	Workers int
+
+	// Append 为 true 且文件已存在且非空时，不截断文件而是在末尾追加数据行（不再写表头）：
+	// 写入前校验文件首行与 headers 一致（headers 为空时不校验）且文件以换行结尾。
+	// rowGenerator 的行号仍从 1 开始；让新行接续原有数据的序列由调用方负责（如 generator.RestoreStudentGenerator）。
+	Append bool
+}
+
+func defaultCSVWriteOptions() CSVWriteOptions {
+	return CSVWriteOptions{
+		Dialect: DefaultDialect(),
+	}
+}
+
 // WriteLargeCSV 以流式方式将大量行写入 CSV 文件 filename。
 //
 // 行写入顺序：可选表头 headers（若非空）→ 共 totalRows 行数据。
 // rowGenerator 会被调用 totalRows 次，参数 rowIndex 为 1-based（范围 [1, totalRows]），
 // 返回值为该行的列数据（[]string）。
 //
-// 函数会定期 Flush 缓冲区；若发生底层 I/O 错误（如磁盘写满、权限不足），会返回 error。
+// 函数会定期 Flush 缓冲区并通过 opts.Logger 以 Debug 级别报告进度；若发生底层 I/O 错误（如磁盘写满、权限不足），会返回 error。
 //
 // 参数说明:
 //   - filename: 文件名
@@ -20,35 +49,199 @@ import (
 //   - totalRows: 需要写入的总行数
 //   - rowGenerator: 行生成器（1-based 行号）
 func WriteLargeCSV(filename string, headers []string, totalRows int, rowGenerator func(rowNum int) []string) error {
-	file, err := os.Create(filename)
-	if err != nil {
-		return fmt.Errorf("创建文件失败：%w", err)
+	return WriteLargeCSVWithOptions(filename, headers, totalRows, rowGenerator, defaultCSVWriteOptions())
+}
+
+// WriteLargeCSVWithOptions 与 WriteLargeCSV 相同，但可通过 opts 指定输出方言
+// （如 ; 分隔、CRLF 换行、所有字段强制加引号）。
+func WriteLargeCSVWithOptions(filename string, headers []string, totalRows int, rowGenerator func(rowNum int) []string, opts CSVWriteOptions) error {
+	rows := func(yield func([]string, error) bool) {
+		for i := 1; i <= totalRows; i++ {
+			if !yield(rowGenerator(i), nil) {
+				return
+			}
+		}
+	}
+	if opts.Workers > 1 {
+		rows = parallelRows(totalRows, opts.Workers, rowGenerator)
+	}
+	_, err := writeCSV(filename, headers, rows, totalRows, opts)
+	return err
+}
+
+// parallelChunk 为并发生成时每个任务负责的行数。
+const parallelChunk = 4096
+
+// parallelRows 用 workers 个 goroutine 按块并发调用 rowGenerator，并按行号顺序产出各行。
+// 同时在途的块数不超过 2*workers，因此内存占用与总行数无关；消费方提前停止时等待所有 goroutine 退出后才返回。
+func parallelRows(totalRows, workers int, rowGenerator func(rowNum int) []string) iter.Seq2[[]string, error] {
+	type job struct {
+		first, last int // 闭区间，1-based
+		out         chan [][]string
+	}
+	return func(yield func([]string, error) bool) {
+		var wg sync.WaitGroup
+		done := make(chan struct{})
+		defer wg.Wait()
+		defer close(done)
+		jobs := make(chan job)
+		pending := make(chan chan [][]string, 2*workers)
+
+		wg.Add(1 + workers)
+		go func() {
+			defer wg.Done()
+			defer close(jobs)
+			defer close(pending)
+			for first := 1; first <= totalRows; first += parallelChunk {
+				j := job{first: first, last: min(first+parallelChunk-1, totalRows), out: make(chan [][]string, 1)}
+				select {
+				case pending <- j.out:
+				case <-done:
+					return
+				}
+				select {
+				case jobs <- j:
+				case <-done:
+					return
+				}
+			}
+		}()
+		for range workers {
+			go func() {
+				defer wg.Done()
+				for j := range jobs {
+					rows := make([][]string, 0, j.last-j.first+1)
+					for i := j.first; i <= j.last; i++ {
+						rows = append(rows, rowGenerator(i))
+					}
+					j.out <- rows // 带 1 个缓冲，不会阻塞
+				}
+			}()
+		}
+
+		for out := range pending {
+			for _, row := range <-out {
+				if !yield(row, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// WriteCSVRows 以流式方式写出行数事先未知的数据（如数据库游标），返回写入的数据行数。
+// rows 产生的 error 会中止写入并原样返回；已写入的内容保留在文件中。
+func WriteCSVRows(filename string, headers []string, rows iter.Seq2[[]string, error], opts CSVWriteOptions) (int, error) {
+	return writeCSV(filename, headers, rows, -1, opts)
+}
+
+// writeCSV 为写出的公共实现；total<0 表示总行数未知（仅影响进度日志）。
+func writeCSV(filename string, headers []string, rows iter.Seq2[[]string, error], total int, opts CSVWriteOptions) (int, error) {
+	if err := opts.Dialect.Validate(); err != nil {
+		return 0, fmt.Errorf("CSV方言配置错误: %w", err)
+	}
+
+	var file *os.File
+	var err error
+	appending := false
+	if opts.Append {
+		if file, appending, err = openForAppend(filename, headers, opts.Dialect); err != nil {
+			return 0, fmt.Errorf("追加写入失败：%w", err)
+		}
+	} else if file, err = os.Create(filename); err != nil {
+		return 0, fmt.Errorf("创建文件失败：%w", err)
 	}
 	defer file.Close()
 
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
+	logger := loggerOrDiscard(opts.Logger).With("file", filename)
+	writer := opts.Dialect.newWriter(file)
 
-	if len(headers) > 0 {
+	if len(headers) > 0 && !appending {
 		if err := writer.Write(headers); err != nil {
-			return fmt.Errorf("写入表头失败：%w", err)
+			return 0, fmt.Errorf("写入表头失败：%w", err)
 		}
 	}
 	flushInterval := 100_000
 
-	for i := 1; i <= totalRows; i++ {
-		row := rowGenerator(i)
-
+	i := 0
+	for row, err := range rows {
+		if err != nil {
+			writer.Flush()
+			return i, err
+		}
+		i++
 		if err := writer.Write(row); err != nil {
-			return fmt.Errorf("写入第 %d 行失败: %w", i, err)
+			return i - 1, fmt.Errorf("写入第 %d 行失败: %w", i, err)
 		}
 		if i%flushInterval == 0 {
 			writer.Flush()
 			if err := writer.Error(); err != nil {
-				return fmt.Errorf("刷新缓冲区到磁盘失败: %w", err)
+				return i, fmt.Errorf("刷新缓冲区到磁盘失败: %w", err)
+			}
+			if total >= 0 {
+				logger.Debug("写入进度", "rows", i, "total", total)
+			} else {
+				logger.Debug("写入进度", "rows", i)
 			}
 		}
-		fmt.Printf("已写入 %d/%d 行\r", i, totalRows)
 	}
-	return nil
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return i, fmt.Errorf("刷新缓冲区到磁盘失败: %w", err)
+	}
+	logger.Info("写入完成", "rows", i)
+	return i, nil
+}
+
+// openForAppend 打开 filename 用于追加，返回的文件位于末尾；appending 为 false 表示文件不存在或为空（需写表头）。
+func openForAppend(filename string, headers []string, d Dialect) (f *os.File, appending bool, err error) {
+	f, err = os.OpenFile(filename, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, false, err
+	}
+	defer func() {
+		if err != nil {
+			f.Close()
+		}
+	}()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, false, err
+	}
+	if info.Size() == 0 {
+		return f, false, nil
+	}
+
+	if len(headers) > 0 {
+		first, err := d.newReader(f).Read()
+		if err != nil {
+			return nil, false, fmt.Errorf("读取已有表头失败: %w", err)
+		}
+		if len(first) > 0 {
+			first[0] = stripBOM(first[0])
+		}
+		if !slices.Equal(first, headers) {
+			return nil, false, fmt.Errorf("已有文件的表头 %v 与要写入的表头 %v 不一致", first, headers)
+		}
+	}
+	last := make([]byte, 1)
+	if _, err := f.ReadAt(last, info.Size()-1); err != nil {
+		return nil, false, err
+	}
+	if last[0] != '\n' {
+		return nil, false, fmt.Errorf("已有文件不以换行结尾，可能上次写入未完成")
+	}
+	if _, err := f.Seek(0, io.SeekEnd); err != nil {
+		return nil, false, err
+	}
+	return f, true, nil
+}
+
+// WriteStudentTableCSV 将 table 写出为 CSV：标准中文列在前，附加列（ExtraHeaders）按原顺序在后，
+// 从而使 ParseCSVToStudentTable → 变换 → WriteStudentTableCSV 的往返不丢失未识别的列。
+func WriteStudentTableCSV(filename string, table *StudentTable, opts CSVWriteOptions) error {
+	return WriteLargeCSVWithOptions(filename, table.Headers(), len(table.Records), func(i int) []string {
+		return table.Row(i - 1)
+	}, opts)
 }
@@ -0,0 +1,84 @@
+package parser_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/xianyudd/hanzi-data-kit/parser"
+)
+
+func TestWriteLargeCSVWithOptions_Workers(t *testing.T) {
+	dir := t.TempDir()
+	row := func(i int) []string { return []string{strconv.Itoa(i), strconv.Itoa(i * i)} }
+
+	// 行数不是分块大小的整数倍，且包含 0 行与不足一块的情形。
+	for _, n := range []int{0, 7, 10_001} {
+		seqPath := filepath.Join(dir, "seq.csv")
+		parPath := filepath.Join(dir, "par.csv")
+		if err := parser.WriteLargeCSVWithOptions(seqPath, []string{"i", "sq"}, n, row, parser.CSVWriteOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		var calls atomic.Int64
+		err := parser.WriteLargeCSVWithOptions(parPath, []string{"i", "sq"}, n, func(i int) []string {
+			calls.Add(1)
+			return row(i)
+		}, parser.CSVWriteOptions{Workers: 4})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, _ := os.ReadFile(seqPath)
+		got, _ := os.ReadFile(parPath)
+		if !bytes.Equal(got, want) {
+			t.Fatalf("n=%d: 并发写出的内容与顺序写出不一致", n)
+		}
+		if calls.Load() != int64(n) {
+			t.Fatalf("n=%d: rowGenerator 被调用 %d 次", n, calls.Load())
+		}
+	}
+}
+
+func TestWriteLargeCSVWithOptions_Append(t *testing.T) {
+	dir := t.TempDir()
+	headers := []string{"i", "sq"}
+	row := func(base int) func(int) []string {
+		return func(i int) []string { return []string{strconv.Itoa(base + i), strconv.Itoa((base + i) * (base + i))} }
+	}
+	opts := parser.CSVWriteOptions{Dialect: parser.Dialect{Delimiter: ';', UseCRLF: true, AlwaysQuote: true}}
+
+	full := filepath.Join(dir, "full.csv")
+	if err := parser.WriteLargeCSVWithOptions(full, headers, 10, row(0), opts); err != nil {
+		t.Fatal(err)
+	}
+	// 文件不存在时与普通写出相同（写表头），之后的写入追加在末尾。
+	part := filepath.Join(dir, "part.csv")
+	appendOpts := opts
+	appendOpts.Append = true
+	for _, r := range [][2]int{{0, 4}, {4, 5}, {9, 1}} {
+		if err := parser.WriteLargeCSVWithOptions(part, headers, r[1], row(r[0]), appendOpts); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, _ := os.ReadFile(full)
+	got, _ := os.ReadFile(part)
+	if !bytes.Equal(got, want) {
+		t.Fatalf("分段追加的结果与一次写出不同:\n%s\nvs\n%s", got, want)
+	}
+
+	if err := parser.WriteLargeCSVWithOptions(part, []string{"i", "cube"}, 1, row(10), appendOpts); err == nil {
+		t.Error("表头不一致时应返回 error")
+	}
+	truncated := filepath.Join(dir, "truncated.csv")
+	if err := os.WriteFile(truncated, want[:len(want)-1], 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := parser.WriteLargeCSVWithOptions(truncated, headers, 1, row(10), appendOpts); err == nil {
+		t.Error("文件不以换行结尾时应返回 error")
+	}
+	if got, _ := os.ReadFile(part); !bytes.Equal(got, want) {
+		t.Error("校验失败时不应改动已有文件")
+	}
+}
@@ -0,0 +1,75 @@
+package parser
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xianyudd/hanzi-data-kit/model"
+)
+
+// LayoutExcelSerial 为表示 Excel 日期序列号（如 45658 表示 2025-01-01，小数部分为一天内的时刻）的特殊布局名，
+// 可与 time 包的布局一起出现在 DateLayouts 中。
+const LayoutExcelSerial = "excel"
+
+// DefaultDateLayouts 返回默认尝试的日期布局（按顺序）：
+// 2006-01-02、2006/1/2、2006年1月2日、2006.1.2、20060102，带时刻的 2006-01-02 15:04:05 等，RFC 3339，以及 Excel 序列号。
+// 月、日的前导零可有可无（如 2006/01/02 与 2006/1/2 均可）。
+func DefaultDateLayouts() []string {
+	return []string{
+		"2006-1-2", "2006/1/2", "2006年1月2日", "2006.1.2", "20060102",
+		"2006-1-2 15:04:05", "2006-1-2 15:04", "2006/1/2 15:04:05", "2006/1/2 15:04", "2006年1月2日 15:04:05", "2006年1月2日15时04分05秒",
+		time.RFC3339,
+		LayoutExcelSerial,
+	}
+}
+
+// ParseDate 依次尝试 layouts 解析 s，返回第一个成功的结果；layouts 为空时使用 DefaultDateLayouts()。
+// 不含时区的布局按 loc 解释，loc 为 nil 时为 UTC。
+func ParseDate(s string, layouts []string, loc *time.Location) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if len(layouts) == 0 {
+		layouts = DefaultDateLayouts()
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	for _, layout := range layouts {
+		if layout == LayoutExcelSerial {
+			if t, ok := parseExcelSerial(s, loc); ok {
+				return t, nil
+			}
+			continue
+		}
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("无法识别的日期 %q(支持的格式: %s)", s, strings.Join(layouts, " | "))
+}
+
+// parseExcelSerial 解析 Excel（1900 日期系统）的日期序列号：1 为 1900-01-01。
+// Excel 沿袭了把 1900 年当作闰年的错误，序列号 60 对应不存在的 1900-02-29，视为无效。
+func parseExcelSerial(s string, loc *time.Location) (time.Time, bool) {
+	v, err := strconv.ParseFloat(s, 64)
+	// 上限 2958465 为 9999-12-31。
+	if err != nil || v < 1 || v >= 2958466 || math.Floor(v) == 60 {
+		return time.Time{}, false
+	}
+	days := int(math.Floor(v))
+	if days < 60 {
+		days++ // 1900-03-01 之前的序列号没有被虚构的 2 月 29 日错位。
+	}
+	secs := int(math.Round((v - math.Floor(v)) * 86400))
+	return time.Date(1899, 12, 30, 0, 0, secs, 0, loc).AddDate(0, 0, days), true
+}
+
+// formatParsedDate 将解析得到的日期规范化为 2006-01-02；带有非零时刻时为 2006-01-02 15:04:05。
+func formatParsedDate(t time.Time) string {
+	if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 && t.Nanosecond() == 0 {
+		return t.Format(model.DateLayout)
+	}
+	return t.Format(model.DateTimeLayout)
+}
@@ -0,0 +1,86 @@
+package parser_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/xianyudd/hanzi-data-kit/parser"
+)
+
+func TestParseDate(t *testing.T) {
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+	tests := []struct {
+		in      string
+		layouts []string
+		want    time.Time
+		wantErr bool
+	}{
+		{in: "2006-01-02", want: day(2006, 1, 2)},
+		{in: "2006-1-2", want: day(2006, 1, 2)},
+		{in: "2006/1/2", want: day(2006, 1, 2)},
+		{in: "2006/01/02", want: day(2006, 1, 2)},
+		{in: "2006年1月2日", want: day(2006, 1, 2)},
+		{in: " 2006年12月25日 ", want: day(2006, 12, 25)},
+		{in: "20060102", want: day(2006, 1, 2)},
+		{in: "2025-06-07 09:30:15", want: time.Date(2025, 6, 7, 9, 30, 15, 0, time.UTC)},
+		{in: "45658", want: day(2025, 1, 1)},
+		{in: "45658.5", want: time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)},
+		{in: "1", want: day(1900, 1, 1)},
+		{in: "61", want: day(1900, 3, 1)},
+		{in: "60", wantErr: true}, // Excel 虚构的 1900-02-29
+		{in: "2006/13/40", wantErr: true},
+		{in: "明天", wantErr: true},
+		{in: "02/01/2006", layouts: []string{"02/01/2006"}, want: day(2006, 1, 2)},
+		{in: "45658", layouts: []string{"2006-01-02"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parser.ParseDate(tt.in, tt.layouts, nil)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("期望出错, 得到 %s", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(tt.want) {
+				t.Fatalf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	cst := time.FixedZone("CST", 8*3600)
+	got, err := parser.ParseDate("2025-06-07 09:00:00", nil, cst)
+	if err != nil || !got.Equal(time.Date(2025, 6, 7, 1, 0, 0, 0, time.UTC)) {
+		t.Errorf("按东八区解释: got %s, %v", got, err)
+	}
+}
+
+func TestParseCSVToStudentTable_DateColumns(t *testing.T) {
+	content := "姓名,年龄,城市,得分,出生日期\n张三,18,杭州,90,2006年1月2日\n李四,19,北京,80,45658\n王五,20,上海,70,2006/13/40\n赵六,21,上海,60,\n"
+	path := writeTempFile(t, "in.csv", []byte(content))
+
+	table, err := parser.ParseCSVToStudentTable(path, parser.CSVParseOptions{TrimSpace: true, SkipBadRows: true, DateColumns: []string{"出生日期"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, r := range table.Records {
+		got = append(got, r.Extra["出生日期"])
+	}
+	if len(got) != 3 || got[0] != "2006-01-02" || got[1] != "2025-01-01" || got[2] != "" {
+		t.Fatalf("规范化后的日期 = %q", got)
+	}
+	if len(table.Warnings) != 1 || table.Warnings[0].Line != 4 {
+		t.Fatalf("warnings = %v", table.Warnings)
+	}
+
+	if _, err := parser.ParseCSVToStudentTable(path, parser.CSVParseOptions{TrimSpace: true, DateColumns: []string{"出生日期"}}); err == nil {
+		t.Error("严格模式下无法识别的日期应返回 error")
+	}
+	if _, err := parser.ParseCSVToStudentTable(path, parser.CSVParseOptions{TrimSpace: true, DateColumns: []string{"入学日期"}}); err == nil {
+		t.Error("日期列不存在时应返回 error")
+	}
+}
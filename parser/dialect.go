@@ -0,0 +1,295 @@
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Dialect 描述一种 CSV“方言”，由解析器（CSVParseOptions）与写出器（CSVWriteOptions）共用。
+// 零值等价于 encoding/csv 的默认行为：逗号分隔、无注释、严格引号、LF 换行。
+type Dialect struct {
+	// Delimiter 字段分隔符；0 表示使用逗号 ','。常见取值：',' ';' '\t' '|'。
+	Delimiter rune
+
+	// Comment 注释起始字符；0 表示不识别注释行。设为 '#' 时以 # 开头的行会被忽略（仅解析时生效）。
+	Comment rune
+
+	// LazyQuotes 为 true 时允许不规范的引号（如未加引号字段中出现 "），仅解析时生效。
+	LazyQuotes bool
+
+	// FieldsPerRecord 每行字段数策略（语义同 csv.Reader.FieldsPerRecord）：
+	//   - 0：以第一行（表头）的字段数为准，后续行必须一致
+	//   - >0：每行必须恰好为该字段数
+	//   - <0：允许每行字段数不同
+	FieldsPerRecord int
+
+	// UseCRLF 为 true 时写出使用 \r\n 换行（解析时 \r\n 总是被正确识别）。
+	UseCRLF bool
+
+	// AlwaysQuote 为 true 时写出的每个字段都用双引号包裹（仅写出时生效）。
+	AlwaysQuote bool
+}
+
+// DefaultDialect 返回默认方言（逗号分隔、严格模式）。
+func DefaultDialect() Dialect {
+	return Dialect{Delimiter: ','}
+}
+
+// delimiter 返回实际使用的分隔符；未配置时回退为逗号。
+func (d Dialect) delimiter() rune {
+	if d.Delimiter == 0 {
+		return ','
+	}
+	return d.Delimiter
+}
+
+// Validate 检查方言配置是否合法（分隔符/注释符不能冲突，且不能为引号或换行）。
+func (d Dialect) Validate() error {
+	delim := d.delimiter()
+	if !validDelim(delim) {
+		return fmt.Errorf("非法分隔符: %q", delim)
+	}
+	if d.Comment != 0 {
+		if !validDelim(d.Comment) {
+			return fmt.Errorf("非法注释符: %q", d.Comment)
+		}
+		if d.Comment == delim {
+			return fmt.Errorf("注释符与分隔符相同: %q", d.Comment)
+		}
+	}
+	return nil
+}
+
+// ParseDelimiter 将命令行中的分隔符/注释符文本解析为 rune。
+// 支持单个字符（如 ";"）、转义写法 `\t` 以及别名 "tab"；空字符串返回 0（表示使用默认值）。
+func ParseDelimiter(s string) (rune, error) {
+	switch strings.ToLower(s) {
+	case "":
+		return 0, nil
+	case "tab", `\t`:
+		return '\t', nil
+	}
+	if utf8.RuneCountInString(s) != 1 {
+		return 0, fmt.Errorf("分隔符必须是单个字符: %q", s)
+	}
+	r, _ := utf8.DecodeRuneInString(s)
+	return r, nil
+}
+
+func validDelim(r rune) bool {
+	return r != 0 && r != '"' && r != '\r' && r != '\n' && r != utf8.RuneError
+}
+
+// newReader 基于方言构造 csv.Reader。
+func (d Dialect) newReader(r io.Reader) *csv.Reader {
+	reader := csv.NewReader(r)
+	reader.Comma = d.delimiter()
+	reader.Comment = d.Comment
+	reader.LazyQuotes = d.LazyQuotes
+	reader.FieldsPerRecord = d.FieldsPerRecord
+	return reader
+}
+
+// rowWriter 是写出端的最小接口：csv.Writer 与 quotingWriter 都满足它。
+type rowWriter interface {
+	Write(record []string) error
+	Flush()
+	Error() error
+}
+
+// newWriter 基于方言构造行写出器；AlwaysQuote 时使用自带的强制引号实现。
+func (d Dialect) newWriter(w io.Writer) rowWriter {
+	if d.AlwaysQuote {
+		return &quotingWriter{w: bufio.NewWriter(w), comma: d.delimiter(), useCRLF: d.UseCRLF}
+	}
+	writer := csv.NewWriter(w)
+	writer.Comma = d.delimiter()
+	writer.UseCRLF = d.UseCRLF
+	return writer
+}
+
+// quotingWriter 对每个字段都加双引号写出（encoding/csv 只在必要时加引号）。
+type quotingWriter struct {
+	w       *bufio.Writer
+	comma   rune
+	useCRLF bool
+	err     error
+}
+
+func (q *quotingWriter) Write(record []string) error {
+	if q.err != nil {
+		return q.err
+	}
+	for i, field := range record {
+		if i > 0 {
+			q.w.WriteRune(q.comma)
+		}
+		q.w.WriteByte('"')
+		q.w.WriteString(strings.ReplaceAll(field, `"`, `""`))
+		q.w.WriteByte('"')
+	}
+	if q.useCRLF {
+		_, q.err = q.w.WriteString("\r\n")
+	} else {
+		q.err = q.w.WriteByte('\n')
+	}
+	return q.err
+}
+
+func (q *quotingWriter) Flush() {
+	if err := q.w.Flush(); err != nil && q.err == nil {
+		q.err = err
+	}
+}
+
+func (q *quotingWriter) Error() error {
+	return q.err
+}
+
+// sniffSampleSize 为方言嗅探读取的最大字节数。
+const sniffSampleSize = 8 * 1024
+
+// sniffCandidates 为嗅探时考虑的候选分隔符，按优先级排列（得分相同时靠前者胜出）。
+var sniffCandidates = []rune{',', ';', '\t', '|'}
+
+// ErrSniffFailed 表示样本中无法识别出可靠的分隔符。
+var ErrSniffFailed = errors.New("无法从样本中识别CSV方言")
+
+// SniffDialect 从 r 的前若干 KB 推断 CSV 方言（分隔符、注释符、引号宽松度、字段数策略、换行风格）。
+//
+// 推断规则：
+//   - 以 # 开头的行视为注释，推断 Comment='#'
+//   - 候选分隔符（, ; \t |）中，选择在各数据行出现次数最一致且非零的一个
+//   - 各行字段数不一致时 FieldsPerRecord=-1
+//   - 未加引号字段中出现双引号时 LazyQuotes=true
+//   - 样本中出现 \r\n 时 UseCRLF=true
+//
+// 样本只有一列（没有任何候选分隔符）时返回逗号方言；样本为空时返回 ErrSniffFailed。
+func SniffDialect(r io.Reader) (Dialect, error) {
+	sample, err := io.ReadAll(io.LimitReader(r, sniffSampleSize))
+	if err != nil {
+		return Dialect{}, fmt.Errorf("读取嗅探样本失败: %w", err)
+	}
+	return sniffSample(sample)
+}
+
+// sniffBuffered 在不消耗数据的前提下，从 br 中窥探样本并推断方言。
+func sniffBuffered(br *bufio.Reader) (Dialect, error) {
+	sample, err := br.Peek(sniffSampleSize)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return Dialect{}, fmt.Errorf("读取嗅探样本失败: %w", err)
+	}
+	return sniffSample(sample)
+}
+
+func sniffSample(sample []byte) (Dialect, error) {
+	sample = bytes.TrimPrefix(sample, []byte("\ufeff"))
+	d := DefaultDialect()
+	if bytes.Contains(sample, []byte("\r\n")) {
+		d.UseCRLF = true
+	}
+
+	lines := strings.Split(strings.ReplaceAll(string(sample), "\r\n", "\n"), "\n")
+	// 样本可能在行中间被截断：若不是以换行结束，丢弃最后一个不完整的行。
+	if len(sample) == sniffSampleSize && len(lines) > 1 {
+		lines = lines[:len(lines)-1]
+	}
+
+	data := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			d.Comment = '#'
+			continue
+		}
+		data = append(data, line)
+	}
+	if len(data) == 0 {
+		return Dialect{}, ErrSniffFailed
+	}
+
+	bestScore := 0
+	for _, cand := range sniffCandidates {
+		counts := make([]int, len(data))
+		for i, line := range data {
+			counts[i] = countUnquoted(line, cand)
+		}
+		score, consistent := delimiterScore(counts)
+		if score > bestScore {
+			bestScore = score
+			d.Delimiter = cand
+			d.FieldsPerRecord = 0
+			if !consistent {
+				d.FieldsPerRecord = -1
+			}
+		}
+	}
+
+	for _, line := range data {
+		if hasBareQuote(line, d.delimiter()) {
+			d.LazyQuotes = true
+			break
+		}
+	}
+	return d, nil
+}
+
+// delimiterScore 根据每行的分隔符出现次数给候选打分：
+// 出现次数等于众数的行数越多、众数越大，得分越高；众数为 0 时得分为 0。
+func delimiterScore(counts []int) (score int, consistent bool) {
+	freq := make(map[int]int, len(counts))
+	mode, modeFreq := 0, 0
+	for _, c := range counts {
+		freq[c]++
+		if freq[c] > modeFreq || (freq[c] == modeFreq && c > mode) {
+			mode, modeFreq = c, freq[c]
+		}
+	}
+	if mode == 0 {
+		return 0, true
+	}
+	// 一致性优先，其次才是列数，避免“偶尔出现在文本中的字符”胜出。
+	return modeFreq*1000 + mode, modeFreq == len(counts)
+}
+
+// countUnquoted 统计 line 中位于引号外的 delim 个数。
+// 只有出现在字段开头的双引号才开启引号区，字段中间的“裸引号”按普通字符处理。
+func countUnquoted(line string, delim rune) int {
+	n := 0
+	// justClosed 用于识别引号区内的转义引号 ""：关闭后紧跟的 " 重新进入引号区。
+	inQuotes, fieldStart, justClosed := false, true, false
+	for _, r := range line {
+		switch {
+		case r == '"' && (inQuotes || fieldStart || justClosed):
+			inQuotes = !inQuotes
+			justClosed = !inQuotes
+			fieldStart = false
+			continue
+		case r == delim && !inQuotes:
+			n++
+			fieldStart = true
+		default:
+			fieldStart = false
+		}
+		justClosed = false
+	}
+	return n
+}
+
+// hasBareQuote 判断 line 中是否存在出现在未加引号字段内部的双引号。
+func hasBareQuote(line string, delim rune) bool {
+	for _, field := range strings.Split(line, string(delim)) {
+		if len(field) > 0 && field[0] != '"' && strings.Contains(field, `"`) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+package parser_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xianyudd/hanzi-data-kit/model"
+	"github.com/xianyudd/hanzi-data-kit/parser"
+)
+
+func TestSniffDialect(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    parser.Dialect
+	}{
+		{
+			name:    "comma",
+			content: "姓名,年龄,城市,得分\n张三,22,北京,95.0\n",
+			want:    parser.Dialect{Delimiter: ','},
+		},
+		{
+			name:    "semicolon_with_comment_and_crlf",
+			content: "# 导出自合作方系统\r\n姓名;年龄;城市;得分\r\n张三;22;北京;95,5\r\n",
+			want:    parser.Dialect{Delimiter: ';', Comment: '#', UseCRLF: true},
+		},
+		{
+			name:    "tab_variable_fields",
+			content: "姓名\t年龄\t城市\t得分\n张三\t22\t北京\t95.0\n李四\t25\t上海\t88.0\t备注\n王五\t28\t广州\t92.5\n",
+			want:    parser.Dialect{Delimiter: '\t', FieldsPerRecord: -1},
+		},
+		{
+			name:    "lazy_quotes",
+			content: "姓名|年龄|城市|得分\n张\"三|22|北京|95.0\n",
+			want:    parser.Dialect{Delimiter: '|', LazyQuotes: true},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parser.SniffDialect(strings.NewReader(tt.content))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("dialect mismatch:\n  got:  %#v\n  want: %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCSVToStudents_Dialect(t *testing.T) {
+	want := []model.Student{
+		{Name: "张三", Age: 22, City: "北京", Score: 95.0},
+		{Name: "李四", Age: 25, City: "上海", Score: 88.5},
+	}
+	content := []byte("# comment line\r\n姓名;年龄;城市;得分\r\n张三;22;北京;95.0\r\n李四;25;上海;88.5;extra\r\n")
+
+	tests := []struct {
+		name string
+		opts parser.CSVParseOptions
+	}{
+		{
+			name: "explicit_dialect",
+			opts: parser.CSVParseOptions{
+				TrimSpace: true,
+				Dialect:   parser.Dialect{Delimiter: ';', Comment: '#', FieldsPerRecord: -1},
+			},
+		},
+		{
+			name: "sniffed_dialect",
+			opts: parser.CSVParseOptions{TrimSpace: true, SniffDialect: true},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeTempFile(t, "in.csv", content)
+			got, err := parser.ParseCSVToStudentsWithOptions(path, tt.opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != len(want) {
+				t.Fatalf("expected %d students, got %d", len(want), len(got))
+			}
+			for i := range want {
+				if got[i] != want[i] {
+					t.Fatalf("student[%d] mismatch:\n  got:  %#v\n  want: %#v", i, got[i], want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWriteLargeCSVWithOptions_Dialect(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.csv")
+	rows := [][]string{{"张三", "22"}, {`李"四`, "25"}}
+
+	err := parser.WriteLargeCSVWithOptions(path, []string{"姓名", "年龄"}, len(rows), func(i int) []string {
+		return rows[i-1]
+	}, parser.CSVWriteOptions{
+		Dialect: parser.Dialect{Delimiter: ';', UseCRLF: true, AlwaysQuote: true},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read output failed: %v", err)
+	}
+	want := "\"姓名\";\"年龄\"\r\n\"张三\";\"22\"\r\n\"李\"\"四\";\"25\"\r\n"
+	if string(got) != want {
+		t.Fatalf("output mismatch:\n  got:  %q\n  want: %q", got, want)
+	}
+}
@@ -0,0 +1,85 @@
+package parser
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// decodeReader 按编码名将 r 包装为输出 UTF-8 的 Reader。
+func decodeReader(r io.Reader, name string) (io.Reader, error) {
+	switch strings.ToLower(strings.ReplaceAll(name, "_", "-")) {
+	case "", "utf-8", "utf8":
+		return r, nil
+	case "auto":
+		return autoDecode(r)
+	}
+	enc, err := lookupEncoding(name)
+	if err != nil {
+		return nil, err
+	}
+	return transform.NewReader(r, enc.NewDecoder()), nil
+}
+
+// EncodeWriter 按编码名将 w 包装为接收 UTF-8 文本、输出目标编码的 Writer。
+// 编码名与 CSVParseOptions.Encoding 相同（不支持 "auto"）；""/"utf-8" 时原样返回 w。
+// 返回的 Writer 需要 Close 以刷新尾部字节（不会关闭 w 本身）。
+func EncodeWriter(w io.Writer, name string) (io.WriteCloser, error) {
+	switch strings.ToLower(strings.ReplaceAll(name, "_", "-")) {
+	case "", "utf-8", "utf8":
+		return nopWriteCloser{w}, nil
+	}
+	enc, err := lookupEncoding(name)
+	if err != nil {
+		return nil, err
+	}
+	return transform.NewWriter(w, enc.NewEncoder()), nil
+}
+
+func lookupEncoding(name string) (encoding.Encoding, error) {
+	switch strings.ToLower(strings.ReplaceAll(name, "_", "-")) {
+	case "gbk", "cp936":
+		return simplifiedchinese.GBK, nil
+	case "gb18030":
+		return simplifiedchinese.GB18030, nil
+	case "utf-16", "utf16":
+		return unicode.UTF16(unicode.LittleEndian, unicode.ExpectBOM), nil
+	}
+	return nil, fmt.Errorf("不支持的字符编码: %q（可选: utf-8|gbk|gb18030|utf-16|auto）", name)
+}
+
+// autoDecode 窥探样本：UTF-16 BOM → UTF-16；合法 UTF-8 → 原样；否则按 GB18030（GBK 的超集）解码。
+func autoDecode(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReaderSize(r, sniffSampleSize)
+	sample, _ := br.Peek(sniffSampleSize)
+	switch {
+	case len(sample) >= 2 && (sample[0] == 0xFF && sample[1] == 0xFE || sample[0] == 0xFE && sample[1] == 0xFF):
+		return transform.NewReader(br, unicode.UTF16(unicode.LittleEndian, unicode.ExpectBOM).NewDecoder()), nil
+	case validUTF8Prefix(sample):
+		return br, nil
+	default:
+		return transform.NewReader(br, simplifiedchinese.GB18030.NewDecoder()), nil
+	}
+}
+
+// validUTF8Prefix 判断样本是否为合法 UTF-8；样本末尾被截断的多字节字符不计为非法。
+func validUTF8Prefix(b []byte) bool {
+	for i := 0; i < utf8.UTFMax && len(b) > 0; i++ {
+		if utf8.Valid(b) {
+			return true
+		}
+		b = b[:len(b)-1]
+	}
+	return utf8.Valid(b)
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
@@ -0,0 +1,203 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// DefaultStudentHeaderAliases 返回学生表头的默认别名字典：标准中文列名 -> 可接受的别名。
+// 标准列名本身总是可以匹配，无需出现在别名列表中。
+// 匹配时忽略大小写与所有空白（含全角空格），因此 "Name"、" name "、"Student Name" 均可命中。
+func DefaultStudentHeaderAliases() map[string][]string {
+	return map[string][]string{
+		"姓名": {"名字", "学生姓名", "name", "student name"},
+		"年龄": {"岁数", "age"},
+		"城市": {"所在城市", "city"},
+		"得分": {"分数", "成绩", "score"},
+	}
+}
+
+// headerAliasLookup 将别名字典展开为 “规范化别名 -> 标准列名” 的查找表。
+func headerAliasLookup(columns []string, aliases map[string][]string) map[string]string {
+	lookup := make(map[string]string, len(columns)*4)
+	for _, col := range columns {
+		lookup[NormalizeHeader(col)] = col
+		for _, alias := range aliases[col] {
+			lookup[NormalizeHeader(alias)] = col
+		}
+	}
+	return lookup
+}
+
+// NormalizeHeader 返回表头的比较形式：转小写并移除所有空白字符。
+// 解析器按该形式匹配表头与别名；其他按列名查找标准列的地方（如 expr、db）也应使用它，以保持一致。
+func NormalizeHeader(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, s)
+}
+
+// suggestHeader 在未被识别的表头中寻找与 col（或其别名）最接近的一个，用于错误提示。
+// 仅当编辑距离不超过别名长度的 1/3（至少为 1）时才给出建议；找不到时返回空字符串。
+func suggestHeader(col string, aliases map[string][]string, unmatched []string) string {
+	targets := append([]string{col}, aliases[col]...)
+
+	best, bestDist := "", -1
+	for _, h := range unmatched {
+		nh := []rune(NormalizeHeader(h))
+		if len(nh) == 0 {
+			continue
+		}
+		for _, t := range targets {
+			nt := []rune(NormalizeHeader(t))
+			limit := max(1, len(nt)/3)
+			d := editDistance(nh, nt)
+			if d <= limit && (bestDist < 0 || d < bestDist) {
+				best, bestDist = h, d
+			}
+		}
+	}
+	return best
+}
+
+// missingColumnError 构造“缺少必需列”错误；若存在近似表头，附带拼写建议。
+func missingColumnError(col string, aliases map[string][]string, unmatched []string) error {
+	if s := suggestHeader(col, aliases, unmatched); s != "" {
+		return fmt.Errorf("缺少必需列: %s（表头中的 %q 与之相近，是否拼写有误？）", col, s)
+	}
+	return fmt.Errorf("缺少必需列: %s", col)
+}
+
+// editDistance 计算 a 与 b 的编辑距离（Damerau-Levenshtein 的 OSA 变体，相邻字符交换计为 1）。
+func editDistance(a, b []rune) int {
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(b)]
+}
+
+// DuplicateHeaderPolicy 描述同一列在表头中出现多次时的处理策略。
+type DuplicateHeaderPolicy int
+
+const (
+	// DuplicateHeaderFirstWins 使用第一次出现的列（零值，即默认策略）。
+	DuplicateHeaderFirstWins DuplicateHeaderPolicy = iota
+	// DuplicateHeaderError 存在重复列时直接返回错误。
+	DuplicateHeaderError
+	// DuplicateHeaderLastWins 使用最后一次出现的列。
+	DuplicateHeaderLastWins
+	// DuplicateHeaderMerge 取各次出现中第一个非空的值，并在 StudentRecord.Multi 中保留全部取值。
+	DuplicateHeaderMerge
+	// DuplicateHeaderWarn 与 DuplicateHeaderFirstWins 相同，但会为每个重复列产生一条告警。
+	DuplicateHeaderWarn
+)
+
+var duplicateHeaderPolicyNames = []string{"first", "error", "last", "merge", "warn"}
+
+// String 返回策略的命令行名称（first/error/last/merge/warn）。
+func (p DuplicateHeaderPolicy) String() string {
+	if p < 0 || int(p) >= len(duplicateHeaderPolicyNames) {
+		return fmt.Sprintf("DuplicateHeaderPolicy(%d)", int(p))
+	}
+	return duplicateHeaderPolicyNames[p]
+}
+
+// ParseDuplicateHeaderPolicy 将命令行名称（first/error/last/merge/warn）解析为策略。
+func ParseDuplicateHeaderPolicy(s string) (DuplicateHeaderPolicy, error) {
+	for i, name := range duplicateHeaderPolicyNames {
+		if strings.EqualFold(s, name) {
+			return DuplicateHeaderPolicy(i), nil
+		}
+	}
+	return 0, fmt.Errorf("未知的重复列策略: %q（可选: %s）", s, strings.Join(duplicateHeaderPolicyNames, "|"))
+}
+
+// HeaderReport 是表头诊断结果。列号均为 1-based。
+type HeaderReport struct {
+	// Duplicates 为重复出现的列：列名（已识别的列为标准列名）-> 全部出现位置。
+	Duplicates map[string][]int `json:"duplicates,omitempty"`
+
+	// EmptyColumns 为表头为空的列号。
+	EmptyColumns []int `json:"empty_columns,omitempty"`
+
+	// Unknown 为未识别的列名（去重后按出现顺序排列）。
+	Unknown []string `json:"unknown,omitempty"`
+}
+
+// OK 表示表头中没有重复列和空表头（未识别的列不视为问题）。
+func (r HeaderReport) OK() bool {
+	return len(r.Duplicates) == 0 && len(r.EmptyColumns) == 0
+}
+
+// String 返回便于人工阅读的多行报告。
+func (r HeaderReport) String() string {
+	var b strings.Builder
+	if len(r.Duplicates) == 0 {
+		b.WriteString("重复列: 无\n")
+	} else {
+		fmt.Fprintf(&b, "重复列: %s\n", r.duplicatesSummary())
+	}
+	if len(r.EmptyColumns) == 0 {
+		b.WriteString("空表头: 无\n")
+	} else {
+		fmt.Fprintf(&b, "空表头: 第 %s 列\n", joinInts(r.EmptyColumns))
+	}
+	if len(r.Unknown) == 0 {
+		b.WriteString("未识别列: 无\n")
+	} else {
+		fmt.Fprintf(&b, "未识别列: %s\n", strings.Join(r.Unknown, ", "))
+	}
+	return b.String()
+}
+
+// duplicateNames 返回重复列名，按首次出现的列号排序，保证输出稳定。
+func (r HeaderReport) duplicateNames() []string {
+	names := make([]string, 0, len(r.Duplicates))
+	for h := range r.Duplicates {
+		names = append(names, h)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return r.Duplicates[names[i]][0] < r.Duplicates[names[j]][0]
+	})
+	return names
+}
+
+// duplicatesSummary 形如 `得分(第 4,6 列); 备注(第 5,7 列)`。
+func (r HeaderReport) duplicatesSummary() string {
+	parts := make([]string, 0, len(r.Duplicates))
+	for _, h := range r.duplicateNames() {
+		parts = append(parts, fmt.Sprintf("%s(第 %s 列)", h, joinInts(r.Duplicates[h])))
+	}
+	return strings.Join(parts, "; ")
+}
+
+func joinInts(xs []int) string {
+	parts := make([]string, len(xs))
+	for i, x := range xs {
+		parts[i] = strconv.Itoa(x)
+	}
+	return strings.Join(parts, ",")
+}
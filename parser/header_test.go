@@ -0,0 +1,130 @@
+package parser_test
+
+import (
+	"testing"
+
+	"github.com/xianyudd/hanzi-data-kit/parser"
+)
+
+func TestParseCSVToStudentTable_DuplicateHeaderPolicies(t *testing.T) {
+	content := []byte("姓名,年龄,城市,得分,分数\n张三,22,北京,,95.0\n李四,25,上海,80.0,88.5\n")
+
+	tests := []struct {
+		name       string
+		policy     parser.DuplicateHeaderPolicy
+		wantScores []float64
+		wantWarn   int
+		wantErr    bool
+	}{
+		{name: "error", policy: parser.DuplicateHeaderError, wantErr: true},
+		{name: "last_wins", policy: parser.DuplicateHeaderLastWins, wantScores: []float64{95.0, 88.5}},
+		{name: "merge", policy: parser.DuplicateHeaderMerge, wantScores: []float64{95.0, 80.0}},
+		{name: "warn", policy: parser.DuplicateHeaderWarn, wantWarn: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeTempFile(t, "in.csv", content)
+
+			var warnings []parser.ParseWarning
+			table, err := parser.ParseCSVToStudentTable(path, parser.CSVParseOptions{
+				TrimSpace:        true,
+				DuplicateHeaders: tt.policy,
+				OnWarning:        func(w parser.ParseWarning) { warnings = append(warnings, w) },
+			})
+			if len(warnings) != tt.wantWarn {
+				t.Fatalf("expected %d warnings, got %v", tt.wantWarn, warnings)
+			}
+			if tt.wantErr {
+				// warn 策略等同 first-wins：第一行“得分”为空，严格模式下报错。
+				if err == nil {
+					t.Fatalf("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for i, want := range tt.wantScores {
+				if got := table.Records[i].Score; got != want {
+					t.Fatalf("record[%d] score: got %v, want %v", i, got, want)
+				}
+			}
+			if tt.policy == parser.DuplicateHeaderMerge {
+				if got := table.Records[1].Multi["得分"]; len(got) != 2 || got[0] != "80.0" || got[1] != "88.5" {
+					t.Fatalf("merge should keep all values, got %q", got)
+				}
+			}
+		})
+	}
+}
+
+func TestDiagnoseHeaders(t *testing.T) {
+	path := writeTempFile(t, "in.csv", []byte("姓名,年龄,,城市,得分,Name,备注,备注\n"))
+
+	report, err := parser.DiagnoseHeaders(path, parser.CSVParseOptions{TrimSpace: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.OK() {
+		t.Fatalf("report should not be OK: %s", report)
+	}
+	if got := report.Duplicates["姓名"]; len(got) != 2 || got[0] != 1 || got[1] != 6 {
+		t.Fatalf("duplicate 姓名 positions: got %v", got)
+	}
+	if got := report.Duplicates["备注"]; len(got) != 2 || got[0] != 7 || got[1] != 8 {
+		t.Fatalf("duplicate 备注 positions: got %v", got)
+	}
+	if len(report.EmptyColumns) != 1 || report.EmptyColumns[0] != 3 {
+		t.Fatalf("empty columns: got %v", report.EmptyColumns)
+	}
+	if len(report.Unknown) != 1 || report.Unknown[0] != "备注" {
+		t.Fatalf("unknown columns: got %q", report.Unknown)
+	}
+}
+
+func TestParseCSVToStudentTable_SkippedRowWarnings(t *testing.T) {
+	path := writeTempFile(t, "in.csv", []byte("姓名,年龄,城市,得分\n张三,22,北京,95.0\n李四,x,上海,88.0\n,23,广州,70.0\n"))
+
+	table, err := parser.ParseCSVToStudentTable(path, parser.CSVParseOptions{TrimSpace: true, SkipBadRows: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(table.Records) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(table.Records))
+	}
+	if len(table.Warnings) != 2 {
+		t.Fatalf("expected 2 warnings, got %v", table.Warnings)
+	}
+	if w := table.Warnings[0]; w.Kind != parser.WarnRowSkipped || w.Line != 3 || w.Column != "年龄" {
+		t.Fatalf("warning[0] mismatch: %#v", w)
+	}
+	if w := table.Warnings[1]; w.Kind != parser.WarnRowSkipped || w.Line != 4 || w.Column != "姓名" {
+		t.Fatalf("warning[1] mismatch: %#v", w)
+	}
+}
+
+// 行号为源文件中的物理行：含换行的引号字段与空行不会让后面的行号错位。
+func TestParseCSVToStudentTable_LineNumbersArePhysical(t *testing.T) {
+	path := writeTempFile(t, "in.csv", []byte("姓名,年龄,城市,得分,备注\n张三,22,北京,95.0,\"第一行\n第二行\"\n\n李四,x,上海,88.0,\n王五,23,广州,70.0,\n"))
+
+	table, err := parser.ParseCSVToStudentTable(path, parser.CSVParseOptions{TrimSpace: true, SkipBadRows: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(table.Records) != 2 || table.Records[0].Line != 2 || table.Records[1].Line != 6 {
+		t.Fatalf("records = %#v", table.Records)
+	}
+	if len(table.Warnings) != 1 || table.Warnings[0].Line != 5 {
+		t.Fatalf("warnings = %v", table.Warnings)
+	}
+}
+
+func TestNormalizeHeader(t *testing.T) {
+	for in, want := range map[string]string{"Score": "score", " 得 分\t": "得分", "User Name": "username", "": ""} {
+		if got := parser.NormalizeHeader(in); got != want {
+			t.Errorf("NormalizeHeader(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
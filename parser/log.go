@@ -0,0 +1,14 @@
+package parser
+
+import "log/slog"
+
+// discardLogger 是库的默认日志器：作为库，parser 默认不向任何地方输出日志。
+var discardLogger = slog.New(slog.DiscardHandler)
+
+// loggerOrDiscard 在 l 为 nil 时返回丢弃一切输出的日志器。
+func loggerOrDiscard(l *slog.Logger) *slog.Logger {
+	if l == nil {
+		return discardLogger
+	}
+	return l
+}
@@ -0,0 +1,392 @@
+package parser
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/xianyudd/hanzi-data-kit/model"
+	"github.com/xianyudd/hanzi-data-kit/region"
+)
+
+// StudentScanner 以流式方式逐条解析学生 CSV：表头在构造时解析，记录通过 Next 逐条读取，
+// 内存占用与文件大小无关。解析规则与 ParseCSVToStudentTable 完全一致。
+//
+// 用法：
+//
+//	sc, err := parser.NewStudentScanner(path, opts)
+//	if err != nil { ... }
+//	defer sc.Close()
+//	for {
+//		rec, err := sc.Next()
+//		if errors.Is(err, io.EOF) { break }
+//		if err != nil { ... }
+//		// 使用 rec
+//	}
+type StudentScanner struct {
+	filename  string
+	file      io.Closer
+	reader    *csv.Reader
+	opts      CSVParseOptions
+	keepExtra bool
+	logger    *slog.Logger
+
+	idx    map[string][]int
+	report HeaderReport
+	// validated 为 ColumnValidators 中的列，按表头顺序排列以保证告警顺序稳定。
+	validated []string
+	extra     []string
+	// headerLine 为表头所在的行号；表头前可能有空行或注释行。
+	headerLine int
+}
+
+// NewStudentScanner 打开 filename 并解析表头；表头不满足列策略时返回 error。
+// 调用方用完后需调用 Close。
+func NewStudentScanner(filename string, opts CSVParseOptions) (*StudentScanner, error) {
+	return newStudentScanner(filename, opts, true)
+}
+
+// NewStudentScannerFromReader 与 NewStudentScanner 相同，但从 r 读取；name 仅用于错误信息与日志。
+// Close 不会关闭 r。
+func NewStudentScannerFromReader(r io.Reader, name string, opts CSVParseOptions) (*StudentScanner, error) {
+	reader, err := newCSVReader(r, name, opts)
+	if err != nil {
+		return nil, err
+	}
+	return startScanner(nopCloser{}, reader, name, opts, true)
+}
+
+// nopCloser 用作不持有文件的扫描器的 closer，Close 什么也不做。
+type nopCloser struct{}
+
+func (nopCloser) Close() error { return nil }
+
+// ScanStudents 与 ParseCSVToStudentsWithOptions 的解析规则相同，但逐条产出学生，内存占用与文件大小无关；
+// 未识别的列被忽略。打开文件或解析表头失败时产出的第一个元素即为该 error；遇到 error 后迭代结束。
+func ScanStudents(filename string, opts CSVParseOptions) iter.Seq2[model.Student, error] {
+	return func(yield func(model.Student, error) bool) {
+		sc, err := newStudentScanner(filename, opts, false)
+		if err != nil {
+			yield(model.Student{}, err)
+			return
+		}
+		defer sc.Close()
+		for {
+			rec, err := sc.Next()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if !yield(rec.Student, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+func newStudentScanner(filename string, opts CSVParseOptions, keepExtra bool) (*StudentScanner, error) {
+	file, reader, err := openCSV(filename, opts)
+	if err != nil {
+		return nil, err
+	}
+	return startScanner(file, reader, filename, opts, keepExtra)
+}
+
+// startScanner 构造扫描器并读取表头；失败时关闭 closer。
+func startScanner(closer io.Closer, reader *csv.Reader, name string, opts CSVParseOptions, keepExtra bool) (*StudentScanner, error) {
+	sc := &StudentScanner{
+		filename:  name,
+		file:      closer,
+		reader:    reader,
+		opts:      opts,
+		keepExtra: keepExtra,
+		logger:    loggerOrDiscard(opts.Logger).With("file", name),
+	}
+	if err := validateColumnPolicies(opts.ColumnPolicies); err != nil {
+		closer.Close()
+		return nil, err
+	}
+	if err := sc.readHeader(); err != nil {
+		closer.Close()
+		return nil, err
+	}
+	return sc, nil
+}
+
+// validateColumnPolicies 检查可缺省/禁止列的 Default：这些列缺省时每行都以 Default 解析，
+// 若 Default 本身无法解析（如年龄为空），所有行都会失败，因此在构造时就报错。
+func validateColumnPolicies(policies map[string]ColumnPolicy) error {
+	for _, col := range model.StudentHeadersCN() {
+		p := policies[col]
+		if p.Presence == ColumnRequired {
+			continue
+		}
+		var err error
+		switch col {
+		case "姓名":
+			if strings.TrimSpace(p.Default) == "" {
+				err = errors.New("姓名不能为空")
+			}
+		case "年龄":
+			_, err = strconv.Atoi(p.Default)
+		case "得分":
+			_, err = strconv.ParseFloat(p.Default, 64)
+		}
+		if err != nil {
+			return fmt.Errorf("列策略错误: %s 可缺省或禁止时每行都使用 Default=%q, 该值无效: %w", col, p.Default, err)
+		}
+	}
+	return nil
+}
+
+// readHeader 读取并校验表头行。
+func (s *StudentScanner) readHeader() error {
+	hdr, err := s.reader.Read()
+	if errors.Is(err, io.EOF) {
+		return fmt.Errorf("CSV文件为空: %s", s.filename)
+	}
+	if err != nil {
+		return fmt.Errorf("读取CSV失败: %s, 错误: %w", s.filename, err)
+	}
+	s.headerLine, _ = s.reader.FieldPos(0)
+
+	aliases := s.opts.HeaderAliases
+	if aliases == nil {
+		aliases = DefaultStudentHeaderAliases()
+	}
+	columns := model.StudentHeadersCN()
+	s.idx, s.report = headerIndex(hdr, s.opts.TrimSpace, s.opts.AllowBOM, headerAliasLookup(columns, aliases))
+	if s.keepExtra {
+		s.extra = s.report.Unknown
+		// Extra 以表头为键，重名的附加列只能保留一个值，写回时其余列会丢失，因此直接拒绝。
+		for _, h := range s.extra {
+			if cols := s.report.Duplicates[h]; len(cols) > 0 {
+				return fmt.Errorf("附加列 %s 重复出现于第 %s 列，重名的附加列无法原样保留，请先重命名", h, joinInts(cols))
+			}
+		}
+	}
+
+	for _, c := range s.report.EmptyColumns {
+		s.warn(ParseWarning{Kind: WarnEmptyHeader, Line: s.headerLine, Message: fmt.Sprintf("第 %d 列表头为空，该列将被忽略", c)})
+	}
+	if len(s.report.Duplicates) > 0 {
+		switch s.opts.DuplicateHeaders {
+		case DuplicateHeaderError:
+			return fmt.Errorf("存在重复列: %s", s.report.duplicatesSummary())
+		case DuplicateHeaderWarn:
+			for _, h := range s.report.duplicateNames() {
+				s.warn(ParseWarning{Kind: WarnDuplicateHeader, Line: s.headerLine, Column: h,
+					Message: fmt.Sprintf("列 %s 重复出现于第 %s 列，使用第 %d 列", h, joinInts(s.report.Duplicates[h]), s.report.Duplicates[h][0])})
+			}
+		}
+	}
+
+	if d := s.opts.DistrictColumn; d != "" {
+		if _, ok := s.idx[d]; !ok {
+			return fmt.Errorf("区县列 %s 不存在于表头中", d)
+		}
+	}
+
+	for col := range s.opts.ColumnValidators {
+		if _, ok := s.idx[col]; !ok {
+			return fmt.Errorf("待校验的列 %s 不存在于表头中", col)
+		}
+		s.validated = append(s.validated, col)
+	}
+	slices.SortFunc(s.validated, func(a, b string) int { return s.idx[a][0] - s.idx[b][0] })
+
+	for _, col := range s.opts.DateColumns {
+		if _, ok := s.idx[col]; !ok {
+			return fmt.Errorf("日期列 %s 不存在于表头中", col)
+		}
+	}
+
+	for _, col := range columns {
+		policy := s.opts.ColumnPolicies[col]
+		_, present := s.idx[col]
+		switch {
+		case policy.Presence == ColumnRequired && !present:
+			return missingColumnError(col, aliases, s.report.Unknown)
+		case policy.Presence == ColumnForbidden && present:
+			return fmt.Errorf("不允许出现的列: %s", col)
+		}
+	}
+	return nil
+}
+
+// Header 返回表头诊断结果。
+func (s *StudentScanner) Header() HeaderReport { return s.report }
+
+// ExtraHeaders 返回附加列（未识别的列）的表头，按源文件中的顺序排列。
+func (s *StudentScanner) ExtraHeaders() []string { return s.extra }
+
+// Close 关闭底层文件。
+func (s *StudentScanner) Close() error { return s.file.Close() }
+
+// Next 返回下一条记录；没有更多记录时返回 io.EOF。
+// 宽松模式（SkipBadRows=true）下坏行会被跳过并产生告警；严格模式下返回带行号的 error。
+func (s *StudentScanner) Next() (StudentRecord, error) {
+	for {
+		row, err := s.reader.Read()
+		if errors.Is(err, io.EOF) {
+			return StudentRecord{}, io.EOF
+		}
+		if err != nil {
+			return StudentRecord{}, fmt.Errorf("读取CSV失败: %s, 错误: %w", s.filename, err)
+		}
+		// 行号取记录起始的物理行：空行、注释行与含换行的引号字段都会使其大于记录序号。
+		line, _ := s.reader.FieldPos(0)
+
+		rec, skip, err := s.parseRow(row, line)
+		if err != nil {
+			return StudentRecord{}, err
+		}
+		if !skip {
+			return rec, nil
+		}
+	}
+}
+
+// cell 按列名取值；列被 ColumnOptional 策略缺省时返回其默认值。
+func (s *StudentScanner) cell(row []string, col string) (string, bool) {
+	if _, present := s.idx[col]; !present {
+		return s.opts.ColumnPolicies[col].Default, true
+	}
+	return getCell(row, s.idx, col, s.opts.DuplicateHeaders, s.opts.TrimSpace)
+}
+
+// parseRow 解析一行数据；skip=true 表示该行被跳过（空行或宽松模式下的坏行）。
+func (s *StudentScanner) parseRow(row []string, line int) (rec StudentRecord, skip bool, err error) {
+	opts := s.opts
+
+	//空行/全空字段：跳过
+	nonEmpty := false
+	for _, cell := range row {
+		if opts.TrimSpace {
+			cell = strings.TrimSpace(cell)
+		}
+		if cell != "" {
+			nonEmpty = true
+			break
+		}
+	}
+	if !nonEmpty {
+		s.logger.Debug("跳过空行", "line", line, "reason", "empty_row")
+		return rec, true, nil
+	}
+
+	name, ok := s.cell(row, "姓名")
+	if !ok || name == "" {
+		if opts.SkipBadRows {
+			s.warn(ParseWarning{Kind: WarnRowSkipped, Line: line, Column: "姓名", Message: "缺少姓名"})
+			return rec, true, nil
+		}
+		return rec, false, fmt.Errorf("缺少姓名(第%d行)", line)
+	}
+	ageStr, _ := s.cell(row, "年龄")
+	city, _ := s.cell(row, "城市")
+	scoreStr, _ := s.cell(row, "得分")
+
+	age, err := strconv.Atoi(ageStr)
+	if err != nil {
+		if opts.SkipBadRows {
+			s.warn(ParseWarning{Kind: WarnRowSkipped, Line: line, Column: "年龄", Message: fmt.Sprintf("解析年龄失败, 值=%q", ageStr)})
+			return rec, true, nil
+		}
+		return rec, false, fmt.Errorf("解析年龄失败(第%d行, 值=%q): %w", line, ageStr, err)
+	}
+
+	score, err := strconv.ParseFloat(scoreStr, 64)
+	if err != nil {
+		if opts.SkipBadRows {
+			s.warn(ParseWarning{Kind: WarnRowSkipped, Line: line, Column: "得分", Message: fmt.Sprintf("解析得分失败, 值=%q", scoreStr)})
+			return rec, true, nil
+		}
+		return rec, false, fmt.Errorf("解析得分失败(第%d行, 值=%q): %w", line, scoreStr, err)
+	}
+
+	if col := opts.DistrictColumn; col != "" {
+		if district, _ := getCell(row, s.idx, col, opts.DuplicateHeaders, opts.TrimSpace); district != "" {
+			if err := region.CheckCityDistrict(city, district); err != nil {
+				if opts.SkipBadRows {
+					s.warn(ParseWarning{Kind: WarnRowSkipped, Line: line, Column: col, Message: err.Error()})
+					return rec, true, nil
+				}
+				return rec, false, fmt.Errorf("区划校验失败(第%d行): %w", line, err)
+			}
+		}
+	}
+
+	for _, col := range s.validated {
+		v, _ := getCell(row, s.idx, col, opts.DuplicateHeaders, opts.TrimSpace)
+		if v == "" {
+			continue
+		}
+		if err := opts.ColumnValidators[col](v); err != nil {
+			if opts.SkipBadRows {
+				s.warn(ParseWarning{Kind: WarnRowSkipped, Line: line, Column: col, Message: err.Error()})
+				return rec, true, nil
+			}
+			return rec, false, fmt.Errorf("校验 %s 失败(第%d行): %w", col, line, err)
+		}
+	}
+
+	var dates map[string]string
+	for _, col := range opts.DateColumns {
+		v, _ := getCell(row, s.idx, col, opts.DuplicateHeaders, opts.TrimSpace)
+		if v == "" {
+			continue
+		}
+		t, err := ParseDate(v, opts.DateLayouts, opts.DateLocation)
+		if err != nil {
+			if opts.SkipBadRows {
+				s.warn(ParseWarning{Kind: WarnRowSkipped, Line: line, Column: col, Message: err.Error()})
+				return rec, true, nil
+			}
+			return rec, false, fmt.Errorf("解析 %s 失败(第%d行): %w", col, line, err)
+		}
+		if dates == nil {
+			dates = make(map[string]string, len(opts.DateColumns))
+		}
+		dates[col] = formatParsedDate(t)
+	}
+
+	rec = StudentRecord{
+		Student: model.Student{
+			Name:  name,
+			Age:   age,
+			City:  city,
+			Score: score,
+		},
+		Line: line,
+	}
+	if len(s.extra) > 0 {
+		rec.Extra = make(map[string]string, len(s.extra))
+		for _, h := range s.extra {
+			rec.Extra[h], _ = getCell(row, s.idx, h, opts.DuplicateHeaders, opts.TrimSpace)
+			if d, ok := dates[h]; ok {
+				rec.Extra[h] = d
+			}
+		}
+	}
+	if s.keepExtra && opts.DuplicateHeaders == DuplicateHeaderMerge && len(s.report.Duplicates) > 0 {
+		rec.Multi = make(map[string][]string, len(s.report.Duplicates))
+		for h := range s.report.Duplicates {
+			rec.Multi[h] = getCells(row, s.idx, h, opts.TrimSpace)
+		}
+	}
+	return rec, false, nil
+}
+
+// warn 记录一条告警：写结构化日志，并回调 OnWarning（若设置）。
+func (s *StudentScanner) warn(w ParseWarning) {
+	s.logger.Warn(w.Message, "line", w.Line, "column", w.Column, "reason", string(w.Kind))
+	if s.opts.OnWarning != nil {
+		s.opts.OnWarning(w)
+	}
+}
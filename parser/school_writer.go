@@ -0,0 +1,68 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/xianyudd/hanzi-data-kit/model"
+)
+
+// SchemaFileName 为 WriteSchoolDataset 写出的结构描述文件名。
+const SchemaFileName = "schema.json"
+
+// DatasetTable 为结构描述中的一张表：在 model.TableSchema 的基础上增加文件名与行数。
+type DatasetTable struct {
+	model.TableSchema
+	File string `json:"file"`
+	Rows int    `json:"rows"`
+}
+
+// DatasetSchema 为 schema.json 的内容。
+type DatasetSchema struct {
+	Tables []DatasetTable `json:"tables"`
+}
+
+// WriteSchoolDataset 将 ds 写入目录 dir（不存在时自动创建）：每张表一个 <表名>.csv（表名见 model.SchoolSchema），
+// 另写出 schema.json 描述各表的列、主键、外键与行数。返回写出的结构描述。
+func WriteSchoolDataset(dir string, ds *model.SchoolDataset, opts CSVWriteOptions) (*DatasetSchema, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("创建目录失败: %w", err)
+	}
+
+	tables := map[string]struct {
+		headers []string
+		rows    int
+		row     func(i int) []string
+	}{
+		"schools":     {model.SchoolHeadersCN(), len(ds.Schools), func(i int) []string { return model.SchoolToRowCN(ds.Schools[i-1]) }},
+		"classes":     {model.ClassHeadersCN(), len(ds.Classes), func(i int) []string { return model.ClassToRowCN(ds.Classes[i-1]) }},
+		"teachers":    {model.TeacherHeadersCN(), len(ds.Teachers), func(i int) []string { return model.TeacherToRowCN(ds.Teachers[i-1]) }},
+		"courses":     {model.CourseHeadersCN(), len(ds.Courses), func(i int) []string { return model.CourseToRowCN(ds.Courses[i-1]) }},
+		"students":    {model.SchoolStudentHeadersCN(), len(ds.Students), func(i int) []string { return model.SchoolStudentToRowCN(ds.Students[i-1]) }},
+		"enrollments": {model.EnrollmentHeadersCN(), len(ds.Enrollments), func(i int) []string { return model.EnrollmentToRowCN(ds.Enrollments[i-1]) }},
+	}
+
+	schema := &DatasetSchema{}
+	for _, ts := range model.SchoolSchema() {
+		t, ok := tables[ts.Name]
+		if !ok {
+			return nil, fmt.Errorf("未知的数据表: %s", ts.Name)
+		}
+		file := ts.Name + ".csv"
+		if err := WriteLargeCSVWithOptions(filepath.Join(dir, file), t.headers, t.rows, t.row, opts); err != nil {
+			return nil, fmt.Errorf("写入 %s 失败: %w", file, err)
+		}
+		schema.Tables = append(schema.Tables, DatasetTable{TableSchema: ts, File: file, Rows: t.rows})
+	}
+
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, SchemaFileName), append(data, '\n'), 0o644); err != nil {
+		return nil, fmt.Errorf("写入 %s 失败: %w", SchemaFileName, err)
+	}
+	return schema, nil
+}
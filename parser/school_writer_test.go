@@ -0,0 +1,49 @@
+package parser_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/xianyudd/hanzi-data-kit/generator"
+	"github.com/xianyudd/hanzi-data-kit/parser"
+)
+
+func TestWriteSchoolDataset(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "school")
+	ds := generator.GenerateSchoolDataset(generator.SchoolGenConfig{Seed: 3, Schools: 1, ClassesPerSchool: 2, StudentsPerClass: 4})
+	schema, err := parser.WriteSchoolDataset(dir, ds, parser.CSVWriteOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, parser.SchemaFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var onDisk parser.DatasetSchema
+	if err := json.Unmarshal(data, &onDisk); err != nil {
+		t.Fatal(err)
+	}
+	if len(onDisk.Tables) != 6 || len(schema.Tables) != 6 {
+		t.Fatalf("tables = %d", len(onDisk.Tables))
+	}
+	for _, tbl := range onDisk.Tables {
+		if _, err := os.Stat(filepath.Join(dir, tbl.File)); err != nil {
+			t.Errorf("%s: %v", tbl.Name, err)
+		}
+	}
+
+	// 学生表可以直接按学生 CSV 解析，学号与班级编号作为附加列保留。
+	table, err := parser.ParseCSVToStudentTable(filepath.Join(dir, "students.csv"), parser.CSVParseOptions{TrimSpace: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(table.Records) != 8 || table.Records[0].Extra["学号"] != ds.Students[0].ID {
+		t.Errorf("records = %d, first = %+v", len(table.Records), table.Records[0])
+	}
+	if table.Records[3].Student != ds.Students[3].Student {
+		t.Errorf("第 4 条 = %+v, want %+v", table.Records[3].Student, ds.Students[3].Student)
+	}
+}
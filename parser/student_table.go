@@ -0,0 +1,56 @@
+package parser
+
+import "github.com/xianyudd/hanzi-data-kit/model"
+
+// StudentRecord 是一条带解析上下文的学生记录。
+type StudentRecord struct {
+	model.Student
+
+	// Line 为该记录在源文件中起始的行号（1-based，通常表头为第 1 行；跨多行的引号字段按首行计）；非解析得到的记录为 0。
+	Line int
+
+	// Extra 保存未被识别为 Student 字段的列：原表头 -> 单元格值；无附加列时为 nil。
+	// 附加列的表头必须唯一：保留附加列的解析（StudentTable、StudentScanner）遇到重名的附加列时返回 error。
+	Extra map[string]string
+
+	// Multi 仅在 DuplicateHeaderMerge 策略下填充：重复列名 -> 各次出现的取值（按列顺序）。
+	Multi map[string][]string
+}
+
+// StudentTable 是解析得到的一张学生表：记录本身加上附加列的表头顺序。
+type StudentTable struct {
+	Records []StudentRecord
+
+	// ExtraHeaders 为附加列的表头，按其在源文件中出现的顺序排列。
+	ExtraHeaders []string
+
+	// Header 为表头诊断结果（重复列、空表头、未识别列）。
+	Header HeaderReport
+
+	// Warnings 为解析过程中产生的全部非致命告警，按产生顺序排列。
+	Warnings []ParseWarning
+}
+
+// Students 返回表中所有记录的 Student 部分（丢弃行号与附加列）。
+func (t *StudentTable) Students() []model.Student {
+	students := make([]model.Student, len(t.Records))
+	for i, rec := range t.Records {
+		students[i] = rec.Student
+	}
+	return students
+}
+
+// Headers 返回写出该表时使用的完整表头：标准中文列在前，附加列按原顺序在后。
+func (t *StudentTable) Headers() []string {
+	return append(model.StudentHeadersCN(), t.ExtraHeaders...)
+}
+
+// Row 返回第 i 条记录对应的一行（与 Headers 对齐）；记录中缺少的附加列输出为空字符串。
+func (t *StudentTable) Row(i int) []string {
+	rec := t.Records[i]
+	row := model.StudentToRowCN(rec.Student)
+	for _, h := range t.ExtraHeaders {
+		row = append(row, rec.Extra[h])
+	}
+	return row
+}
@@ -0,0 +1,176 @@
+package parser_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/xianyudd/hanzi-data-kit/model"
+	"github.com/xianyudd/hanzi-data-kit/parser"
+)
+
+func TestStudentTable_RoundTripKeepsExtraColumns(t *testing.T) {
+	in := writeTempFile(t, "in.csv", []byte(
+		"学号,姓名,年龄,城市,得分,备注\n"+
+			"S001,张三,22,北京,95.0,班长\n"+
+			"S002,李四,25,上海,88.5,\n",
+	))
+
+	table, err := parser.ParseCSVToStudentTable(in, parser.CSVParseOptions{TrimSpace: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := table.ExtraHeaders, []string{"学号", "备注"}; len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("extra headers mismatch: got %q, want %q", got, want)
+	}
+	if rec := table.Records[0]; rec.Line != 2 || rec.Extra["学号"] != "S001" || rec.Extra["备注"] != "班长" {
+		t.Fatalf("record[0] mismatch: %#v", rec)
+	}
+
+	// 变换：加分后写出，附加列应原样保留在标准列之后。
+	for i := range table.Records {
+		table.Records[i].Score += 1
+	}
+	out := filepath.Join(t.TempDir(), "out.csv")
+	if err := parser.WriteStudentTableCSV(out, table, parser.CSVWriteOptions{}); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	got, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("read output failed: %v", err)
+	}
+	want := "姓名,年龄,城市,得分,学号,备注\n" +
+		"张三,22,北京,96.0,S001,班长\n" +
+		"李四,25,上海,89.5,S002,\n"
+	if string(got) != want {
+		t.Fatalf("output mismatch:\n  got:  %q\n  want: %q", got, want)
+	}
+}
+
+func TestParseCSVToStudents_ColumnPolicies(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  []byte
+		policies map[string]parser.ColumnPolicy
+		want     []model.Student
+		wantErr  bool
+	}{
+		{
+			name:    "optional_column_uses_default",
+			content: []byte("姓名,年龄,得分\n张三,22,95.0\n"),
+			policies: map[string]parser.ColumnPolicy{
+				"城市": {Presence: parser.ColumnOptional, Default: "未知"},
+			},
+			want: []model.Student{{Name: "张三", Age: 22, City: "未知", Score: 95.0}},
+		},
+		{
+			name:    "optional_column_present_ignores_default",
+			content: []byte("姓名,年龄,城市,得分\n张三,22,北京,95.0\n"),
+			policies: map[string]parser.ColumnPolicy{
+				"城市": {Presence: parser.ColumnOptional, Default: "未知"},
+			},
+			want: []model.Student{{Name: "张三", Age: 22, City: "北京", Score: 95.0}},
+		},
+		{
+			name:    "forbidden_column_present_returns_error",
+			content: []byte("姓名,年龄,城市,得分\n张三,22,北京,95.0\n"),
+			policies: map[string]parser.ColumnPolicy{
+				"城市": {Presence: parser.ColumnForbidden, Default: "未知"},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "optional_age_without_default_returns_error",
+			content: []byte("姓名,城市,得分\n张三,北京,95.0\n"),
+			policies: map[string]parser.ColumnPolicy{
+				"年龄": {Presence: parser.ColumnOptional},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "forbidden_name_without_default_returns_error",
+			content: []byte("年龄,城市,得分\n22,北京,95.0\n"),
+			policies: map[string]parser.ColumnPolicy{
+				"姓名": {Presence: parser.ColumnForbidden},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "optional_score_with_bad_default_returns_error",
+			content: []byte("姓名,年龄,城市\n张三,22,北京\n"),
+			policies: map[string]parser.ColumnPolicy{
+				"得分": {Presence: parser.ColumnOptional, Default: "N/A"},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "optional_age_and_score_with_defaults",
+			content: []byte("姓名,城市\n张三,北京\n"),
+			policies: map[string]parser.ColumnPolicy{
+				"年龄": {Presence: parser.ColumnOptional, Default: "0"},
+				"得分": {Presence: parser.ColumnOptional, Default: "0"},
+			},
+			want: []model.Student{{Name: "张三", Age: 0, City: "北京", Score: 0}},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeTempFile(t, "in.csv", tt.content)
+			got, err := parser.ParseCSVToStudentsWithOptions(path, parser.CSVParseOptions{
+				TrimSpace:      true,
+				ColumnPolicies: tt.policies,
+			})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %d students, got %d", len(tt.want), len(got))
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Fatalf("student[%d] mismatch:\n  got:  %#v\n  want: %#v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+// 可缺省的 年龄 没有 Default 时每行都会解析失败；宽松模式下也应在构造时报错，而不是静默跳过所有行。
+func TestNewStudentScanner_RejectsUnusableColumnDefault(t *testing.T) {
+	path := writeTempFile(t, "in.csv", []byte("姓名,城市,得分\n张三,北京,95.0\n"))
+	_, err := parser.NewStudentScanner(path, parser.CSVParseOptions{
+		TrimSpace:      true,
+		SkipBadRows:    true,
+		ColumnPolicies: map[string]parser.ColumnPolicy{"年龄": {Presence: parser.ColumnOptional}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "列策略错误: 年龄") {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+// 重名的附加列无法以表头为键原样保留，保留附加列的解析应报错而不是静默丢弃后出现的列；
+// 丢弃附加列的解析不受影响。
+func TestParseCSVToStudentTable_RejectsDuplicateExtraHeaders(t *testing.T) {
+	path := writeTempFile(t, "in.csv", []byte("姓名,年龄,城市,得分,备注,备注\n张三,22,北京,95.0,班长,团员\n"))
+
+	for _, policy := range []parser.DuplicateHeaderPolicy{parser.DuplicateHeaderFirstWins, parser.DuplicateHeaderMerge} {
+		_, err := parser.ParseCSVToStudentTable(path, parser.CSVParseOptions{TrimSpace: true, DuplicateHeaders: policy})
+		if err == nil || !strings.Contains(err.Error(), "附加列 备注 重复出现于第 5,6 列") {
+			t.Fatalf("policy=%s: err = %v", policy, err)
+		}
+	}
+	students, err := parser.ParseCSVToStudentsWithOptions(path, parser.CSVParseOptions{TrimSpace: true})
+	if err != nil || len(students) != 1 {
+		t.Fatalf("ParseCSVToStudentsWithOptions: %v, %v", students, err)
+	}
+}
@@ -0,0 +1,37 @@
+package parser
+
+import "fmt"
+
+// WarningKind 标识解析告警的类别。
+type WarningKind string
+
+const (
+	// WarnDuplicateHeader 表头中同一列出现多次（仅 DuplicateHeaderWarn 策略下产生）。
+	WarnDuplicateHeader WarningKind = "duplicate_header"
+	// WarnEmptyHeader 表头中存在空列名，该列被忽略。
+	WarnEmptyHeader WarningKind = "empty_header"
+	// WarnRowSkipped 宽松模式（SkipBadRows=true）下跳过了一条坏行。
+	WarnRowSkipped WarningKind = "row_skipped"
+)
+
+// ParseWarning 是一条结构化的非致命解析告警。
+type ParseWarning struct {
+	Kind WarningKind `json:"kind"`
+
+	// Line 为告警所在记录在源文件中起始的行号（1-based，通常表头为第 1 行）。
+	Line int `json:"line"`
+
+	// Column 为相关列名（标准列名或原始表头）；与具体列无关时为空。
+	Column string `json:"column,omitempty"`
+
+	// Message 为面向人的描述。
+	Message string `json:"message"`
+}
+
+// String 返回形如 `[row_skipped] 第3行 年龄: 解析年龄失败, 值="x"` 的单行描述。
+func (w ParseWarning) String() string {
+	if w.Column == "" {
+		return fmt.Sprintf("[%s] 第%d行: %s", w.Kind, w.Line, w.Message)
+	}
+	return fmt.Sprintf("[%s] 第%d行 %s: %s", w.Kind, w.Line, w.Column, w.Message)
+}
@@ -0,0 +1,166 @@
+package parser
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// XLSXWriter 以流式方式写出只含一个工作表的 .xlsx 文件，内存占用与行数无关。
+// 写入的内容可以直接发送到 HTTP 响应等不可回退的流中。
+//
+// 单元格默认写为文本；通过 NumericColumns 指定的列在内容可解析为数字时写为数值单元格。
+type XLSXWriter struct {
+	zw      *zip.Writer
+	sheet   *bufio.Writer
+	numeric map[int]bool
+	closed  bool
+}
+
+// xlsxStaticParts 为除工作表外的固定部件：路径 -> 内容。
+var xlsxStaticParts = []struct{ name, body string }{
+	{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`},
+	{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+		`</Relationships>`},
+	{"xl/styles.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<fonts count="1"><font><sz val="11"/><name val="Calibri"/></font></fonts>` +
+		`<fills count="1"><fill><patternFill patternType="none"/></fill></fills>` +
+		`<borders count="1"><border/></borders>` +
+		`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+		`<cellXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/></cellXfs>` +
+		`</styleSheet>`},
+}
+
+// NewXLSXWriter 在 w 上开始一个 .xlsx 文件，工作表名为 sheet（为空时为 "Sheet1"）。
+// 写完所有行后必须调用 Close；Close 不会关闭 w。
+func NewXLSXWriter(w io.Writer, sheet string) (*XLSXWriter, error) {
+	if sheet == "" {
+		sheet = "Sheet1"
+	}
+	zw := zip.NewWriter(w)
+	for _, p := range xlsxStaticParts {
+		if err := writeZipPart(zw, p.name, p.body); err != nil {
+			return nil, err
+		}
+	}
+	var name strings.Builder
+	xml.EscapeText(&name, []byte(sheet))
+	workbook := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="` + name.String() + `" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	if err := writeZipPart(zw, "xl/workbook.xml", workbook); err != nil {
+		return nil, err
+	}
+
+	// 工作表放在最后，以便逐行流式写入。
+	sw, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, fmt.Errorf("写入xlsx失败: %w", err)
+	}
+	x := &XLSXWriter{zw: zw, sheet: bufio.NewWriter(sw)}
+	x.sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+		`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	return x, nil
+}
+
+func writeZipPart(zw *zip.Writer, name, body string) error {
+	f, err := zw.Create(name)
+	if err == nil {
+		_, err = io.WriteString(f, body)
+	}
+	if err != nil {
+		return fmt.Errorf("写入xlsx失败: %w", err)
+	}
+	return nil
+}
+
+// NumericColumns 指定按数值写出的列（0-based 下标）。
+func (x *XLSXWriter) NumericColumns(cols ...int) {
+	x.numeric = make(map[int]bool, len(cols))
+	for _, c := range cols {
+		x.numeric[c] = true
+	}
+}
+
+// Write 写出一行。
+func (x *XLSXWriter) Write(row []string) error {
+	b := x.sheet
+	b.WriteString("<row>")
+	for i, cell := range row {
+		if x.numeric[i] && isXLSXNumber(cell) {
+			b.WriteString("<c><v>")
+			b.WriteString(cell)
+			b.WriteString("</v></c>")
+			continue
+		}
+		b.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`)
+		if err := xml.EscapeText(b, []byte(cell)); err != nil {
+			return err
+		}
+		b.WriteString("</t></is></c>")
+	}
+	_, err := b.WriteString("</row>")
+	return err
+}
+
+// Flush 将已缓冲的行写入底层 writer（用于 HTTP 分块传输时及时发送数据）。
+func (x *XLSXWriter) Flush() error {
+	if err := x.sheet.Flush(); err != nil {
+		return err
+	}
+	return x.zw.Flush()
+}
+
+// Close 结束工作表并写出 zip 目录。
+func (x *XLSXWriter) Close() error {
+	if x.closed {
+		return nil
+	}
+	x.closed = true
+	x.sheet.WriteString("</sheetData></worksheet>")
+	if err := x.sheet.Flush(); err != nil {
+		return fmt.Errorf("写入xlsx失败: %w", err)
+	}
+	if err := x.zw.Close(); err != nil {
+		return fmt.Errorf("写入xlsx失败: %w", err)
+	}
+	return nil
+}
+
+// isXLSXNumber 判断 s 是否是可以直接写入 <v> 的十进制数。
+func isXLSXNumber(s string) bool {
+	if s == "" {
+		return false
+	}
+	digits, dot := 0, false
+	for i, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			digits++
+		case r == '-' && i == 0:
+		case r == '.' && !dot:
+			dot = true
+		default:
+			return false
+		}
+	}
+	return digits > 0
+}
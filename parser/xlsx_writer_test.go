@@ -0,0 +1,91 @@
+package parser_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/xianyudd/hanzi-data-kit/parser"
+)
+
+func TestXLSXWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := parser.NewXLSXWriter(&buf, "学生")
+	if err != nil {
+		t.Fatalf("new writer: %v", err)
+	}
+	w.NumericColumns(1, 3)
+	rows := [][]string{{"姓名", "年龄", "城市", "得分"}, {"张<三>", "22", "北京", "95.0"}, {"李四", "", "上海", "n/a"}}
+	for _, row := range rows {
+		if err := w.Write(row); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("output is not a zip: %v", err)
+	}
+	parts := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("open %s: %v", f.Name, err)
+		}
+		b, _ := io.ReadAll(rc)
+		rc.Close()
+		parts[f.Name] = string(b)
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml", "xl/worksheets/sheet1.xml"} {
+		body, ok := parts[name]
+		if !ok {
+			t.Fatalf("missing part %s", name)
+		}
+		if err := xml.Unmarshal([]byte(body), new(struct{})); err != nil {
+			t.Fatalf("part %s is not well-formed XML: %v", name, err)
+		}
+	}
+
+	// 解析工作表，检查单元格类型与内容。
+	var sheet struct {
+		Rows []struct {
+			Cells []struct {
+				Type   string `xml:"t,attr"`
+				Value  string `xml:"v"`
+				Inline string `xml:"is>t"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := xml.Unmarshal([]byte(parts["xl/worksheets/sheet1.xml"]), &sheet); err != nil {
+		t.Fatalf("parse sheet: %v", err)
+	}
+	var got []string
+	for _, r := range sheet.Rows {
+		var cells []string
+		for _, c := range r.Cells {
+			if c.Type == "inlineStr" {
+				cells = append(cells, "s:"+c.Inline)
+			} else {
+				cells = append(cells, "n:"+c.Value)
+			}
+		}
+		got = append(got, strings.Join(cells, ","))
+	}
+	want := []string{
+		"s:姓名,s:年龄,s:城市,s:得分",
+		"s:张<三>,n:22,s:北京,n:95.0",
+		"s:李四,s:,s:上海,s:n/a",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("sheet mismatch:\n  got:  %q\n  want: %q", got, want)
+	}
+	if !strings.Contains(parts["xl/workbook.xml"], `name="学生"`) {
+		t.Fatalf("sheet name not set: %s", parts["xl/workbook.xml"])
+	}
+}
@@ -0,0 +1,8 @@
+// Package rank 提供处理并列情况的排名工具，以及基于它的考试派生字段计算（总分、班级/学校排名、百分位、等级）。
+//
+// 三种排名方式对分数 [90, 80, 80, 70] 的结果：
+//
+//	Competition  1, 2, 2, 4   并列占用后续名次（"1224"，默认）
+//	Dense        1, 2, 2, 3   并列不占用后续名次（"1223"）
+//	Ordinal      1, 2, 3, 4   并列按原顺序依次排名
+package rank
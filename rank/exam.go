@@ -0,0 +1,49 @@
+package rank
+
+import "github.com/xianyudd/hanzi-data-kit/model"
+
+// ExamOptions 控制考试派生字段的计算。
+type ExamOptions struct {
+	// Method 为并列总分的排名方式；零值为 Competition。
+	Method Method
+
+	// Bands 为等级划分；Labels 为空时使用 DefaultBands()。
+	Bands Bands
+}
+
+// ScoreExam 计算 e 中每条成绩的派生字段：总分为各科之和，班级/学校排名按总分在同班/同校内排名，
+// 百分位与等级按总分在全体考生中计算。
+func ScoreExam(e *model.Exam, opts ExamOptions) error {
+	bands := opts.Bands
+	if len(bands.Labels) == 0 {
+		bands = DefaultBands()
+	}
+	if err := bands.Validate(); err != nil {
+		return err
+	}
+
+	n := len(e.Results)
+	totals := make([]float64, n)
+	classes := make([]string, n)
+	schools := make([]string, n)
+	for i := range e.Results {
+		r := &e.Results[i]
+		r.Total = 0
+		for _, s := range r.Scores {
+			r.Total += s
+		}
+		totals[i], classes[i], schools[i] = r.Total, r.ClassID, r.SchoolID
+	}
+
+	classRanks := Grouped(totals, classes, opts.Method)
+	schoolRanks := Grouped(totals, schools, opts.Method)
+	percentiles := Percentiles(totals)
+	for i := range e.Results {
+		r := &e.Results[i]
+		r.ClassRank = classRanks[i]
+		r.SchoolRank = schoolRanks[i]
+		r.Percentile = percentiles[i]
+		r.Band = bands.Assign(percentiles[i])
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package rank
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+)
+
+// Method 为并列情况的排名方式。
+type Method int
+
+const (
+	// Competition 为竞赛排名：并列者名次相同，并占用后续名次（1,2,2,4）。
+	Competition Method = iota
+	// Dense 为密集排名：并列者名次相同，后续名次连续（1,2,2,3）。
+	Dense
+	// Ordinal 为序数排名：并列者按原顺序依次排名（1,2,3,4）。
+	Ordinal
+)
+
+// ParseMethod 解析排名方式名称：competition|dense|ordinal。
+func ParseMethod(s string) (Method, error) {
+	switch s {
+	case "competition", "":
+		return Competition, nil
+	case "dense":
+		return Dense, nil
+	case "ordinal":
+		return Ordinal, nil
+	}
+	return 0, fmt.Errorf("未知的排名方式 %q(可选 competition|dense|ordinal)", s)
+}
+
+// String 返回排名方式名称。
+func (m Method) String() string {
+	switch m {
+	case Competition:
+		return "competition"
+	case Dense:
+		return "dense"
+	case Ordinal:
+		return "ordinal"
+	}
+	return fmt.Sprintf("Method(%d)", int(m))
+}
+
+// Func 按 compare 给出的顺序为 items 排名，返回与 items 对齐的名次（从 1 开始）。
+// compare(a, b) < 0 表示 a 排在 b 前面，== 0 表示并列。
+func Func[T any](items []T, compare func(a, b T) int, m Method) []int {
+	order := make([]int, len(items))
+	for i := range order {
+		order[i] = i
+	}
+	// 稳定排序保证 Ordinal 下并列者按原顺序排名。
+	slices.SortStableFunc(order, func(a, b int) int { return compare(items[a], items[b]) })
+
+	ranks := make([]int, len(items))
+	dense := 0
+	for pos, i := range order {
+		switch {
+		case pos > 0 && m != Ordinal && compare(items[order[pos-1]], items[i]) == 0:
+			ranks[i] = ranks[order[pos-1]]
+		case m == Dense:
+			dense++
+			ranks[i] = dense
+		default:
+			ranks[i] = pos + 1
+		}
+	}
+	return ranks
+}
+
+// Descending 按数值从高到低排名（最高分为第 1 名）。
+func Descending[T cmp.Ordered](values []T, m Method) []int {
+	return Func(values, func(a, b T) int { return cmp.Compare(b, a) }, m)
+}
+
+// Grouped 在 groups 相同的记录内按数值从高到低排名；groups 与 values 一一对应。
+func Grouped[T cmp.Ordered](values []T, groups []string, m Method) []int {
+	members := map[string][]int{}
+	for i, g := range groups {
+		members[g] = append(members[g], i)
+	}
+	ranks := make([]int, len(values))
+	for _, idx := range members {
+		sub := make([]T, len(idx))
+		for j, i := range idx {
+			sub[j] = values[i]
+		}
+		for j, r := range Descending(sub, m) {
+			ranks[idx[j]] = r
+		}
+	}
+	return ranks
+}
+
+// Percentiles 返回每个值的百分位（0~100）：低于它的比例加上与它并列比例的一半，
+// 即 (below + 0.5*equal) / n * 100。越高表示越靠前。
+func Percentiles[T cmp.Ordered](values []T) []float64 {
+	n := len(values)
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	out := make([]float64, n)
+	for i, v := range values {
+		below, _ := slices.BinarySearch(sorted, v)
+		end := below
+		for end < n && sorted[end] == v {
+			end++
+		}
+		out[i] = (float64(below) + 0.5*float64(end-below)) / float64(n) * 100
+	}
+	return out
+}
+
+// Bands 按百分位划分等级：从最好的等级开始，Shares[i] 为等级 Labels[i] 占全体的百分比。
+type Bands struct {
+	Labels []string
+	Shares []float64
+}
+
+// DefaultBands 返回 A~E 五档，比例为 15%/35%/35%/13%/2%。
+func DefaultBands() Bands {
+	return Bands{
+		Labels: []string{"A", "B", "C", "D", "E"},
+		Shares: []float64{15, 35, 35, 13, 2},
+	}
+}
+
+// Validate 检查 Labels 与 Shares 等长、比例非负且总和为 100。
+func (b Bands) Validate() error {
+	if len(b.Labels) == 0 || len(b.Labels) != len(b.Shares) {
+		return fmt.Errorf("等级数(%d)与比例数(%d)不一致", len(b.Labels), len(b.Shares))
+	}
+	sum := 0.0
+	for _, s := range b.Shares {
+		if s < 0 {
+			return fmt.Errorf("等级比例不能为负: %v", s)
+		}
+		sum += s
+	}
+	if sum < 99.999 || sum > 100.001 {
+		return fmt.Errorf("等级比例之和必须为 100, 实际为 %v", sum)
+	}
+	return nil
+}
+
+// Assign 返回百分位 p 所属的等级：前 Shares[0]% 为 Labels[0]，依此类推。
+func (b Bands) Assign(p float64) string {
+	top := 100.0
+	for i, s := range b.Shares {
+		top -= s
+		if p >= top {
+			return b.Labels[i]
+		}
+	}
+	return b.Labels[len(b.Labels)-1]
+}
@@ -0,0 +1,100 @@
+package rank_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/xianyudd/hanzi-data-kit/model"
+	"github.com/xianyudd/hanzi-data-kit/rank"
+)
+
+func TestDescending(t *testing.T) {
+	values := []float64{80, 90, 70, 80, 90, 60}
+	tests := []struct {
+		method rank.Method
+		want   []int
+	}{
+		{rank.Competition, []int{3, 1, 5, 3, 1, 6}},
+		{rank.Dense, []int{2, 1, 3, 2, 1, 4}},
+		{rank.Ordinal, []int{3, 1, 5, 4, 2, 6}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method.String(), func(t *testing.T) {
+			if got := rank.Descending(values, tt.method); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if got := rank.Descending([]float64{}, rank.Dense); len(got) != 0 {
+		t.Errorf("空输入 = %v", got)
+	}
+}
+
+func TestGrouped(t *testing.T) {
+	values := []int{90, 80, 85, 80, 70}
+	groups := []string{"a", "b", "a", "b", "a"}
+	if got, want := rank.Grouped(values, groups, rank.Competition), []int{1, 1, 2, 1, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestPercentiles(t *testing.T) {
+	got := rank.Percentiles([]float64{10, 20, 20, 40})
+	want := []float64{12.5, 50, 50, 87.5}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestBands(t *testing.T) {
+	b := rank.DefaultBands()
+	tests := []struct {
+		p    float64
+		want string
+	}{{100, "A"}, {85, "A"}, {84.9, "B"}, {50, "B"}, {49, "C"}, {15, "C"}, {14, "D"}, {2, "D"}, {1.9, "E"}, {0, "E"}}
+	for _, tt := range tests {
+		if got := b.Assign(tt.p); got != tt.want {
+			t.Errorf("Assign(%v) = %s, want %s", tt.p, got, tt.want)
+		}
+	}
+	if err := (rank.Bands{Labels: []string{"A", "B"}, Shares: []float64{50, 40}}).Validate(); err == nil {
+		t.Error("比例之和不为 100 时应返回 error")
+	}
+}
+
+func TestParseMethod(t *testing.T) {
+	for _, m := range []rank.Method{rank.Competition, rank.Dense, rank.Ordinal} {
+		if got, err := rank.ParseMethod(m.String()); err != nil || got != m {
+			t.Errorf("ParseMethod(%q) = %v, %v", m, got, err)
+		}
+	}
+	if _, err := rank.ParseMethod("x"); err == nil {
+		t.Error("未知方式应返回 error")
+	}
+}
+
+func TestScoreExam(t *testing.T) {
+	e := &model.Exam{
+		Subjects: []model.ExamSubject{{Name: "语文", FullMarks: 150}, {Name: "数学", FullMarks: 150}},
+		Results: []model.ExamResult{
+			{StudentID: "1", ClassID: "c1", SchoolID: "s1", Scores: []float64{100, 120}},
+			{StudentID: "2", ClassID: "c1", SchoolID: "s1", Scores: []float64{110, 110}},
+			{StudentID: "3", ClassID: "c2", SchoolID: "s1", Scores: []float64{140, 90}},
+			{StudentID: "4", ClassID: "c3", SchoolID: "s2", Scores: []float64{60, 70}},
+		},
+	}
+	if err := rank.ScoreExam(e, rank.ExamOptions{Method: rank.Dense}); err != nil {
+		t.Fatal(err)
+	}
+	type derived struct {
+		Total             float64
+		ClassRank, School int
+		Band              string
+	}
+	want := []derived{{220, 1, 2, "B"}, {220, 1, 2, "B"}, {230, 1, 1, "A"}, {130, 1, 1, "D"}}
+	for i, r := range e.Results {
+		if got := (derived{r.Total, r.ClassRank, r.SchoolRank, r.Band}); got != want[i] {
+			t.Errorf("results[%d] = %+v, want %+v (percentile %v)", i, got, want[i], r.Percentile)
+		}
+	}
+}
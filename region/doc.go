@@ -0,0 +1,6 @@
+// Package region 内嵌 GB/T 2260 行政区划代码表（省级 → 地级 → 县级），提供查询、合法性校验与按人口加权的地区抽样。
+//
+// 内置表收录 31 个省级行政区及其全部地级（附常住人口）与县级区划。直辖市的地级以 "市辖区"/"县" 代码
+// （如 110100、500200）登记，名称为直辖市本身；省直辖的县级区划（如 湖北省的 仙桃市）登记在
+// "省直辖县级行政区划"（如 429000）下，查询与抽样时视为城市。
+package region
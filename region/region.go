@@ -0,0 +1,284 @@
+package region
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed divisions.txt
+var divisionsTxt string
+
+// Level 为行政区划级别。
+type Level int
+
+const (
+	Province   Level = iota // 省级（省、自治区、直辖市）
+	Prefecture              // 地级（地级市；直辖市为其本身）
+	County                  // 县级（市辖区、县级市）
+)
+
+// Division 为一个行政区划。
+type Division struct {
+	// Code 为 6 位 GB/T 2260 代码。
+	Code  string
+	Name  string
+	Level Level
+	// Population 为常住人口（万人），仅地级区划有值。
+	Population int
+}
+
+// ShortName 返回去掉 "市" 后缀的简称，如 杭州市 → 杭州；与 model.Student.City 的写法一致。
+func (d Division) ShortName() string {
+	if s := strings.TrimSuffix(d.Name, "市"); len([]rune(s)) >= 2 {
+		return s
+	}
+	return d.Name
+}
+
+// ProvinceCode 返回所属省级区划代码。
+func (d Division) ProvinceCode() string { return d.Code[:2] + "0000" }
+
+// PrefectureCode 返回所属地级区划代码；省级区划返回空字符串。
+func (d Division) PrefectureCode() string {
+	if d.Level == Province {
+		return ""
+	}
+	return d.Code[:4] + "00"
+}
+
+// table 为解析后的区划表。
+type table struct {
+	byCode      map[string]Division
+	children    map[string][]Division
+	provinces   []Division
+	prefectures []Division
+	// byCityName 以城市（地级区划及省直辖的县级区划）的全称与简称为键。
+	byCityName map[string][]Division
+}
+
+var divisions = mustParse(divisionsTxt)
+
+func mustParse(s string) *table {
+	t, err := parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func parse(s string) (*table, error) {
+	t := &table{
+		byCode:     map[string]Division{},
+		children:   map[string][]Division{},
+		byCityName: map[string][]Division{},
+	}
+	sc := bufio.NewScanner(strings.NewReader(s))
+	line := 0
+	for sc.Scan() {
+		line++
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Split(text, "\t")
+		if len(fields) < 2 || len(fields[0]) != 6 {
+			return nil, fmt.Errorf("区划表第 %d 行格式错误: %q", line, text)
+		}
+		d := Division{Code: fields[0], Name: fields[1]}
+		switch {
+		case strings.HasSuffix(d.Code, "0000"):
+			d.Level = Province
+			t.provinces = append(t.provinces, d)
+		case strings.HasSuffix(d.Code, "00"):
+			d.Level = Prefecture
+			if len(fields) < 3 {
+				return nil, fmt.Errorf("区划表第 %d 行: 地级区划缺少人口", line)
+			}
+			pop, err := strconv.Atoi(fields[2])
+			if err != nil {
+				return nil, fmt.Errorf("区划表第 %d 行: 人口不是整数: %q", line, fields[2])
+			}
+			d.Population = pop
+			t.prefectures = append(t.prefectures, d)
+			if !d.direct() {
+				t.addCity(d)
+			}
+		default:
+			d.Level = County
+			if parent, ok := t.byCode[d.PrefectureCode()]; ok && parent.direct() {
+				t.addCity(d)
+			}
+		}
+		if _, dup := t.byCode[d.Code]; dup {
+			return nil, fmt.Errorf("区划表第 %d 行: 代码 %s 重复", line, d.Code)
+		}
+		t.byCode[d.Code] = d
+		if parent := d.parentCode(); parent != "" {
+			if _, ok := t.byCode[parent]; !ok {
+				return nil, fmt.Errorf("区划表第 %d 行: %s 的上级 %s 不存在", line, d.Code, parent)
+			}
+			t.children[parent] = append(t.children[parent], d)
+		}
+	}
+	return t, sc.Err()
+}
+
+func (t *table) addCity(d Division) {
+	t.byCityName[d.Name] = append(t.byCityName[d.Name], d)
+	if short := d.ShortName(); short != d.Name {
+		t.byCityName[short] = append(t.byCityName[short], d)
+	}
+}
+
+// direct 报告 d 是否为 "省直辖县级行政区划"（如 429000）：其下的县级区划（如 仙桃市）由省直接管辖，
+// 本身即被视为城市。
+func (d Division) direct() bool { return d.Level == Prefecture && d.Code[2:4] == "90" }
+
+func (d Division) parentCode() string {
+	switch d.Level {
+	case Prefecture:
+		return d.ProvinceCode()
+	case County:
+		return d.PrefectureCode()
+	}
+	return ""
+}
+
+// Lookup 按代码查询区划。
+func Lookup(code string) (Division, bool) {
+	d, ok := divisions.byCode[code]
+	return d, ok
+}
+
+// Parent 返回上级区划；省级区划返回 false。
+func Parent(d Division) (Division, bool) {
+	return Lookup(d.parentCode())
+}
+
+// Provinces 返回全部省级区划，按代码排列。
+func Provinces() []Division { return append([]Division(nil), divisions.provinces...) }
+
+// Prefectures 返回全部地级区划，按代码排列。
+func Prefectures() []Division { return append([]Division(nil), divisions.prefectures...) }
+
+// Children 返回 code 的直接下级区划，按代码排列。
+func Children(code string) []Division { return append([]Division(nil), divisions.children[code]...) }
+
+// FindCity 按名称查找城市，全称（杭州市）与简称（杭州）均可。城市为地级区划，或省直辖的县级区划（如 仙桃市）；
+// 直辖市返回其市辖区一级（如 重庆市 为 500100）。
+func FindCity(name string) (Division, bool) {
+	ds := divisions.byCityName[strings.TrimSpace(name)]
+	if len(ds) == 0 {
+		return Division{}, false
+	}
+	return ds[0], true
+}
+
+// FindDistrict 在城市 city 下按名称查找县级区划；name 须为全称（如 西湖区）。
+// city 没有下级区划时（东莞市等不设区的地级市、省直辖的县级区划），只有其本身的名称匹配。
+func FindDistrict(city Division, name string) (Division, bool) {
+	name = strings.TrimSpace(name)
+	children := divisions.children[city.Code]
+	if len(children) == 0 && name == city.Name {
+		return city, true
+	}
+	for _, d := range children {
+		if d.Name == name {
+			return d, true
+		}
+	}
+	return Division{}, false
+}
+
+// CheckCityDistrict 检查 city（全称或简称）与 district 是否为真实存在的上下级组合。
+func CheckCityDistrict(city, district string) error {
+	cities := divisions.byCityName[strings.TrimSpace(city)]
+	if len(cities) == 0 {
+		return fmt.Errorf("未知的城市: %q", city)
+	}
+	// 重庆市等直辖市有多个地级行（市辖区、县），逐一查找。
+	for _, c := range cities {
+		if _, ok := FindDistrict(c, district); ok {
+			return nil
+		}
+	}
+	c := cities[0]
+	if other := citiesOf(district); len(other) > 0 {
+		return fmt.Errorf("%s 不在 %s 下(属于 %s)", district, c.Name, strings.Join(other, "、"))
+	}
+	return fmt.Errorf("%s 下没有 %q", c.Name, district)
+}
+
+// citiesOf 返回包含名为 district 的县级区划的地级区划名称。
+func citiesOf(district string) []string {
+	var out []string
+	for _, p := range divisions.prefectures {
+		if p.direct() {
+			continue
+		}
+		if _, ok := FindDistrict(p, district); ok {
+			out = append(out, p.Name)
+		}
+	}
+	return out
+}
+
+// Rand 为 Sampler 所需的随机数来源；*math/rand.Rand 满足该接口。
+type Rand interface {
+	Intn(n int) int
+}
+
+// Sampler 按人口加权抽取地级区划，再在其下辖县级区划中等概率抽取。
+type Sampler struct {
+	cum    []int
+	total  int
+	cities []Division
+}
+
+// NewSampler 构造抽样器；provinces 为空时覆盖全部省份，否则只抽取这些省级区划（名称或代码）下的城市。
+func NewSampler(provinces ...string) (*Sampler, error) {
+	allowed := map[string]bool{}
+	for _, p := range provinces {
+		found := false
+		for _, d := range divisions.provinces {
+			if p == d.Code || p == d.Name || strings.HasPrefix(d.Name, p) {
+				allowed[d.Code] = true
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("未知的省级区划: %q", p)
+		}
+	}
+	s := &Sampler{}
+	for _, c := range divisions.prefectures {
+		if len(allowed) > 0 && !allowed[c.ProvinceCode()] {
+			continue
+		}
+		s.total += c.Population
+		s.cum = append(s.cum, s.total)
+		s.cities = append(s.cities, c)
+	}
+	return s, nil
+}
+
+// Pick 返回抽中的省、市、县级区划。不设区的地级市（如 东莞市）与省直辖的县级区划（如 仙桃市），
+// 市与县级均为其本身。
+func (s *Sampler) Pick(rng Rand) (province, city, county Division) {
+	x := rng.Intn(s.total)
+	i := sort.SearchInts(s.cum, x+1)
+	city = s.cities[i]
+	county = city
+	if counties := divisions.children[city.Code]; len(counties) > 0 {
+		county = counties[rng.Intn(len(counties))]
+	}
+	if city.direct() {
+		city = county
+	}
+	province = divisions.byCode[city.ProvinceCode()]
+	return province, city, county
+}
@@ -0,0 +1,108 @@
+package region_test
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/xianyudd/hanzi-data-kit/region"
+)
+
+func TestTableIntegrity(t *testing.T) {
+	if n := len(region.Provinces()); n != 31 {
+		t.Errorf("省级区划 = %d, want 31", n)
+	}
+	// 不设区的地级市（东莞、中山、儋州、嘉峪关）没有县级区划。
+	noCounties := map[string]bool{"441900": true, "442000": true, "460400": true, "620200": true}
+	counties := 0
+	for _, city := range region.Prefectures() {
+		if city.Population <= 0 {
+			t.Errorf("%s 缺少人口", city.Name)
+		}
+		if p, ok := region.Parent(city); !ok || p.Level != region.Province {
+			t.Errorf("%s 的上级 = %+v", city.Name, p)
+		}
+		children := region.Children(city.Code)
+		if (len(children) == 0) != noCounties[city.Code] {
+			t.Errorf("%s 下辖 %d 个区县", city.Name, len(children))
+		}
+		for _, c := range children {
+			if c.Level != region.County || c.PrefectureCode() != city.Code {
+				t.Errorf("%+v 不属于 %s", c, city.Name)
+			}
+		}
+		counties += len(children)
+	}
+	if counties != 2844 {
+		t.Errorf("县级区划 = %d, want 2844", counties)
+	}
+}
+
+func TestCheckCityDistrict(t *testing.T) {
+	tests := []struct {
+		city, district string
+		wantErr        string
+	}{
+		{"杭州", "西湖区", ""},
+		{"杭州市", "西湖区", ""},
+		{"杭州", "桐庐县", ""},
+		{"北京", "朝阳区", ""},
+		{"长春", "朝阳区", ""},
+		{"苏州", "昆山市", ""},
+		{"重庆", "涪陵区", ""},
+		{"重庆", "城口县", ""},
+		{"仙桃", "仙桃市", ""},
+		{"东莞", "东莞市", ""},
+		{"北京", "西湖区", "属于 杭州市"},
+		{"南昌", "浦东新区", "属于 上海市"},
+		{"杭州", "不存在区", "杭州市 下没有"},
+		{"东莞", "南城区", "东莞市 下没有"},
+		{"火星", "西湖区", "未知的城市"},
+	}
+	for _, tt := range tests {
+		err := region.CheckCityDistrict(tt.city, tt.district)
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("%s/%s: err = %v", tt.city, tt.district, err)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("%s/%s: err = %v, want 包含 %q", tt.city, tt.district, err, tt.wantErr)
+		}
+	}
+}
+
+func TestSampler(t *testing.T) {
+	s, err := region.NewSampler()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rng := rand.New(rand.NewSource(1))
+	counts := map[string]int{}
+	for range 50000 {
+		p, c, d := s.Pick(rng)
+		if (d.PrefectureCode() != c.Code && d != c) || c.ProvinceCode() != p.Code {
+			t.Fatalf("层级不一致: %s/%s/%s", p.Name, c.Name, d.Name)
+		}
+		counts[c.Name]++
+	}
+	// 按人口加权：重庆(3205万)应显著多于拉萨(87万)。
+	if counts["重庆市"] < 10*counts["拉萨市"] {
+		t.Errorf("重庆 = %d, 拉萨 = %d", counts["重庆市"], counts["拉萨市"])
+	}
+	// 不设区的地级市与省直辖的县级区划也参与抽样。
+	if counts["东莞市"] == 0 || counts["仙桃市"] == 0 {
+		t.Errorf("东莞 = %d, 仙桃 = %d", counts["东莞市"], counts["仙桃市"])
+	}
+
+	s, err = region.NewSampler("浙江", "440000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 1000 {
+		if p, _, _ := s.Pick(rng); p.Name != "浙江省" && p.Name != "广东省" {
+			t.Fatalf("省份 = %s", p.Name)
+		}
+	}
+	if _, err := region.NewSampler("火星"); err == nil {
+		t.Error("未知省份应返回 error")
+	}
+}
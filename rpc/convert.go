@@ -0,0 +1,84 @@
+package rpc
+
+import (
+	"github.com/xianyudd/hanzi-data-kit/generator"
+	"github.com/xianyudd/hanzi-data-kit/model"
+	"github.com/xianyudd/hanzi-data-kit/parser"
+	"github.com/xianyudd/hanzi-data-kit/rpc/hanzipb"
+)
+
+// StudentToProto 将 model.Student 转换为 protobuf 消息。
+func StudentToProto(s model.Student) *hanzipb.Student {
+	return &hanzipb.Student{Name: s.Name, Age: int32(s.Age), City: s.City, Score: s.Score}
+}
+
+// StudentFromProto 将 protobuf 消息转换为 model.Student；p 为 nil 时返回零值。
+func StudentFromProto(p *hanzipb.Student) model.Student {
+	return model.Student{Name: p.GetName(), Age: int(p.GetAge()), City: p.GetCity(), Score: p.GetScore()}
+}
+
+// GenConfigToProto 将生成配置转换为 protobuf 消息。
+func GenConfigToProto(cfg generator.StudentGenConfig) *hanzipb.StudentGenConfig {
+	return &hanzipb.StudentGenConfig{
+		Seed:            cfg.Seed,
+		AgeMin:          int32(cfg.AgeMin),
+		AgeMax:          int32(cfg.AgeMax),
+		ScoreMin:        cfg.ScoreMin,
+		ScoreMax:        cfg.ScoreMax,
+		Cities:          cfg.Cities,
+		Surnames:        cfg.Surnames,
+		GivenNames1:     cfg.GivenNames1,
+		GivenNames2:     cfg.GivenNames2,
+		TwoCharNameProb: cfg.TwoCharNameProb,
+		ScoreStep:       cfg.ScoreStep,
+	}
+}
+
+// GenConfigFromProto 将 protobuf 消息转换为生成配置；p 为 nil 时返回零值（即全部使用默认值）。
+func GenConfigFromProto(p *hanzipb.StudentGenConfig) generator.StudentGenConfig {
+	return generator.StudentGenConfig{
+		Seed:            p.GetSeed(),
+		AgeMin:          int(p.GetAgeMin()),
+		AgeMax:          int(p.GetAgeMax()),
+		ScoreMin:        p.GetScoreMin(),
+		ScoreMax:        p.GetScoreMax(),
+		Cities:          p.GetCities(),
+		Surnames:        p.GetSurnames(),
+		GivenNames1:     p.GetGivenNames1(),
+		GivenNames2:     p.GetGivenNames2(),
+		TwoCharNameProb: p.GetTwoCharNameProb(),
+		ScoreStep:       p.GetScoreStep(),
+	}
+}
+
+// WarningToProto 将解析告警转换为 protobuf 消息。
+func WarningToProto(w parser.ParseWarning) *hanzipb.ParseWarning {
+	return &hanzipb.ParseWarning{Kind: string(w.Kind), Line: int32(w.Line), Column: w.Column, Message: w.Message}
+}
+
+// recordToProto 将解析出的记录转换为 protobuf 消息。
+func recordToProto(rec parser.StudentRecord) *hanzipb.Record {
+	return &hanzipb.Record{Student: StudentToProto(rec.Student), Line: int32(rec.Line), Extra: rec.Extra}
+}
+
+// parseOptionsFromProto 构造解析选项，默认值与 HTTP 服务的 /parse 一致。
+func parseOptionsFromProto(p *hanzipb.ParseOptions) (parser.CSVParseOptions, error) {
+	opts := parser.CSVParseOptions{
+		TrimSpace:    true,
+		AllowBOM:     true,
+		SkipBadRows:  !p.GetStrict(),
+		SniffDialect: p.GetSniff(),
+		Encoding:     p.GetEncoding(),
+	}
+	delim, err := parser.ParseDelimiter(p.GetDelimiter())
+	if err != nil {
+		return opts, err
+	}
+	opts.Dialect.Delimiter = delim
+	if v := p.GetDupHeaders(); v != "" {
+		if opts.DuplicateHeaders, err = parser.ParseDuplicateHeaderPolicy(v); err != nil {
+			return opts, err
+		}
+	}
+	return opts, nil
+}
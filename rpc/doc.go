@@ -0,0 +1,9 @@
+// Package rpc 以 gRPC 服务的形式提供数据生成与解析，供只支持 gRPC 的服务调用。
+//
+// 服务定义见 rpc/hanzipb/hanzi.proto：
+//
+//	Generate(GenerateRequest) returns (stream GenerateResponse)   按配置分批流式生成
+//	Parse(stream ParseRequest) returns (ParseResponse)            分块上传 CSV，返回记录与行级错误
+//
+// Connect 客户端可以使用 gRPC 协议（connect.WithGRPC()）直接调用本服务。
+package rpc
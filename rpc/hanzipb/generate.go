@@ -0,0 +1,4 @@
+// Package hanzipb 为 hanzi.proto 生成的 protobuf 消息与 gRPC 桩代码，请勿手工修改 *.pb.go。
+package hanzipb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative hanzi.proto
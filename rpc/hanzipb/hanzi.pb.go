@@ -0,0 +1,781 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: hanzi.proto
+
+// hanzi.v1 提供学生数据的生成与解析服务，字段与 Go 侧 model.Student、
+// generator.StudentGenConfig、parser.ParseWarning 一一对应。
+
+package hanzipb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Student 对应 model.Student。
+type Student struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Age           int32                  `protobuf:"varint,2,opt,name=age,proto3" json:"age,omitempty"`
+	City          string                 `protobuf:"bytes,3,opt,name=city,proto3" json:"city,omitempty"`
+	Score         float64                `protobuf:"fixed64,4,opt,name=score,proto3" json:"score,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Student) Reset() {
+	*x = Student{}
+	mi := &file_hanzi_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Student) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Student) ProtoMessage() {}
+
+func (x *Student) ProtoReflect() protoreflect.Message {
+	mi := &file_hanzi_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Student.ProtoReflect.Descriptor instead.
+func (*Student) Descriptor() ([]byte, []int) {
+	return file_hanzi_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Student) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Student) GetAge() int32 {
+	if x != nil {
+		return x.Age
+	}
+	return 0
+}
+
+func (x *Student) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *Student) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+// StudentGenConfig 对应 generator.StudentGenConfig；零值字段使用生成器默认值。
+type StudentGenConfig struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Seed            int64                  `protobuf:"varint,1,opt,name=seed,proto3" json:"seed,omitempty"`
+	AgeMin          int32                  `protobuf:"varint,2,opt,name=age_min,json=ageMin,proto3" json:"age_min,omitempty"`
+	AgeMax          int32                  `protobuf:"varint,3,opt,name=age_max,json=ageMax,proto3" json:"age_max,omitempty"`
+	ScoreMin        float64                `protobuf:"fixed64,4,opt,name=score_min,json=scoreMin,proto3" json:"score_min,omitempty"`
+	ScoreMax        float64                `protobuf:"fixed64,5,opt,name=score_max,json=scoreMax,proto3" json:"score_max,omitempty"`
+	Cities          []string               `protobuf:"bytes,6,rep,name=cities,proto3" json:"cities,omitempty"`
+	Surnames        []string               `protobuf:"bytes,7,rep,name=surnames,proto3" json:"surnames,omitempty"`
+	GivenNames1     []string               `protobuf:"bytes,8,rep,name=given_names1,json=givenNames1,proto3" json:"given_names1,omitempty"`
+	GivenNames2     []string               `protobuf:"bytes,9,rep,name=given_names2,json=givenNames2,proto3" json:"given_names2,omitempty"`
+	TwoCharNameProb float64                `protobuf:"fixed64,10,opt,name=two_char_name_prob,json=twoCharNameProb,proto3" json:"two_char_name_prob,omitempty"`
+	ScoreStep       float64                `protobuf:"fixed64,11,opt,name=score_step,json=scoreStep,proto3" json:"score_step,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *StudentGenConfig) Reset() {
+	*x = StudentGenConfig{}
+	mi := &file_hanzi_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StudentGenConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StudentGenConfig) ProtoMessage() {}
+
+func (x *StudentGenConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_hanzi_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StudentGenConfig.ProtoReflect.Descriptor instead.
+func (*StudentGenConfig) Descriptor() ([]byte, []int) {
+	return file_hanzi_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *StudentGenConfig) GetSeed() int64 {
+	if x != nil {
+		return x.Seed
+	}
+	return 0
+}
+
+func (x *StudentGenConfig) GetAgeMin() int32 {
+	if x != nil {
+		return x.AgeMin
+	}
+	return 0
+}
+
+func (x *StudentGenConfig) GetAgeMax() int32 {
+	if x != nil {
+		return x.AgeMax
+	}
+	return 0
+}
+
+func (x *StudentGenConfig) GetScoreMin() float64 {
+	if x != nil {
+		return x.ScoreMin
+	}
+	return 0
+}
+
+func (x *StudentGenConfig) GetScoreMax() float64 {
+	if x != nil {
+		return x.ScoreMax
+	}
+	return 0
+}
+
+func (x *StudentGenConfig) GetCities() []string {
+	if x != nil {
+		return x.Cities
+	}
+	return nil
+}
+
+func (x *StudentGenConfig) GetSurnames() []string {
+	if x != nil {
+		return x.Surnames
+	}
+	return nil
+}
+
+func (x *StudentGenConfig) GetGivenNames1() []string {
+	if x != nil {
+		return x.GivenNames1
+	}
+	return nil
+}
+
+func (x *StudentGenConfig) GetGivenNames2() []string {
+	if x != nil {
+		return x.GivenNames2
+	}
+	return nil
+}
+
+func (x *StudentGenConfig) GetTwoCharNameProb() float64 {
+	if x != nil {
+		return x.TwoCharNameProb
+	}
+	return 0
+}
+
+func (x *StudentGenConfig) GetScoreStep() float64 {
+	if x != nil {
+		return x.ScoreStep
+	}
+	return 0
+}
+
+type GenerateRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Config *StudentGenConfig      `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
+	// count 为生成的总条数。
+	Count int64 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	// batch_size 为每条响应消息包含的最大条数；<=0 时为 500。
+	BatchSize     int32 `protobuf:"varint,3,opt,name=batch_size,json=batchSize,proto3" json:"batch_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateRequest) Reset() {
+	*x = GenerateRequest{}
+	mi := &file_hanzi_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateRequest) ProtoMessage() {}
+
+func (x *GenerateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hanzi_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateRequest.ProtoReflect.Descriptor instead.
+func (*GenerateRequest) Descriptor() ([]byte, []int) {
+	return file_hanzi_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GenerateRequest) GetConfig() *StudentGenConfig {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+func (x *GenerateRequest) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *GenerateRequest) GetBatchSize() int32 {
+	if x != nil {
+		return x.BatchSize
+	}
+	return 0
+}
+
+type GenerateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Students      []*Student             `protobuf:"bytes,1,rep,name=students,proto3" json:"students,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerateResponse) Reset() {
+	*x = GenerateResponse{}
+	mi := &file_hanzi_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateResponse) ProtoMessage() {}
+
+func (x *GenerateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_hanzi_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateResponse.ProtoReflect.Descriptor instead.
+func (*GenerateResponse) Descriptor() ([]byte, []int) {
+	return file_hanzi_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GenerateResponse) GetStudents() []*Student {
+	if x != nil {
+		return x.Students
+	}
+	return nil
+}
+
+// ParseOptions 对应 parser.CSVParseOptions 中可由客户端控制的部分。
+type ParseOptions struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// delimiter 为分隔符：单个字符或 tab/comma/semicolon/pipe；为空时为逗号。
+	Delimiter string `protobuf:"bytes,1,opt,name=delimiter,proto3" json:"delimiter,omitempty"`
+	// encoding 为源编码（如 gbk）；为空时为 UTF-8。
+	Encoding string `protobuf:"bytes,2,opt,name=encoding,proto3" json:"encoding,omitempty"`
+	// sniff 为 true 时自动探测方言与编码。
+	Sniff bool `protobuf:"varint,3,opt,name=sniff,proto3" json:"sniff,omitempty"`
+	// strict 为 true 时遇到坏行即失败；否则跳过坏行并在 errors 中列出。
+	Strict bool `protobuf:"varint,4,opt,name=strict,proto3" json:"strict,omitempty"`
+	// dup_headers 为重复表头策略：error|first|last|warn|merge。
+	DupHeaders    string `protobuf:"bytes,5,opt,name=dup_headers,json=dupHeaders,proto3" json:"dup_headers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ParseOptions) Reset() {
+	*x = ParseOptions{}
+	mi := &file_hanzi_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ParseOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParseOptions) ProtoMessage() {}
+
+func (x *ParseOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_hanzi_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParseOptions.ProtoReflect.Descriptor instead.
+func (*ParseOptions) Descriptor() ([]byte, []int) {
+	return file_hanzi_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ParseOptions) GetDelimiter() string {
+	if x != nil {
+		return x.Delimiter
+	}
+	return ""
+}
+
+func (x *ParseOptions) GetEncoding() string {
+	if x != nil {
+		return x.Encoding
+	}
+	return ""
+}
+
+func (x *ParseOptions) GetSniff() bool {
+	if x != nil {
+		return x.Sniff
+	}
+	return false
+}
+
+func (x *ParseOptions) GetStrict() bool {
+	if x != nil {
+		return x.Strict
+	}
+	return false
+}
+
+func (x *ParseOptions) GetDupHeaders() string {
+	if x != nil {
+		return x.DupHeaders
+	}
+	return ""
+}
+
+// ParseRequest 为客户端流中的一条消息：options 只在第一条消息中生效，chunk 按顺序拼接为完整的 CSV 内容。
+type ParseRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Options       *ParseOptions          `protobuf:"bytes,1,opt,name=options,proto3" json:"options,omitempty"`
+	Chunk         []byte                 `protobuf:"bytes,2,opt,name=chunk,proto3" json:"chunk,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ParseRequest) Reset() {
+	*x = ParseRequest{}
+	mi := &file_hanzi_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ParseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParseRequest) ProtoMessage() {}
+
+func (x *ParseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hanzi_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParseRequest.ProtoReflect.Descriptor instead.
+func (*ParseRequest) Descriptor() ([]byte, []int) {
+	return file_hanzi_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ParseRequest) GetOptions() *ParseOptions {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *ParseRequest) GetChunk() []byte {
+	if x != nil {
+		return x.Chunk
+	}
+	return nil
+}
+
+type Record struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Student *Student               `protobuf:"bytes,1,opt,name=student,proto3" json:"student,omitempty"`
+	// line 为记录在源文件中的行号（1-based，表头为第 1 行）。
+	Line int32 `protobuf:"varint,2,opt,name=line,proto3" json:"line,omitempty"`
+	// extra 为附加列（未识别的列）的值。
+	Extra         map[string]string `protobuf:"bytes,3,rep,name=extra,proto3" json:"extra,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Record) Reset() {
+	*x = Record{}
+	mi := &file_hanzi_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Record) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Record) ProtoMessage() {}
+
+func (x *Record) ProtoReflect() protoreflect.Message {
+	mi := &file_hanzi_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Record.ProtoReflect.Descriptor instead.
+func (*Record) Descriptor() ([]byte, []int) {
+	return file_hanzi_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Record) GetStudent() *Student {
+	if x != nil {
+		return x.Student
+	}
+	return nil
+}
+
+func (x *Record) GetLine() int32 {
+	if x != nil {
+		return x.Line
+	}
+	return 0
+}
+
+func (x *Record) GetExtra() map[string]string {
+	if x != nil {
+		return x.Extra
+	}
+	return nil
+}
+
+// ParseWarning 对应 parser.ParseWarning。
+type ParseWarning struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kind          string                 `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	Line          int32                  `protobuf:"varint,2,opt,name=line,proto3" json:"line,omitempty"`
+	Column        string                 `protobuf:"bytes,3,opt,name=column,proto3" json:"column,omitempty"`
+	Message       string                 `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ParseWarning) Reset() {
+	*x = ParseWarning{}
+	mi := &file_hanzi_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ParseWarning) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParseWarning) ProtoMessage() {}
+
+func (x *ParseWarning) ProtoReflect() protoreflect.Message {
+	mi := &file_hanzi_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParseWarning.ProtoReflect.Descriptor instead.
+func (*ParseWarning) Descriptor() ([]byte, []int) {
+	return file_hanzi_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ParseWarning) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *ParseWarning) GetLine() int32 {
+	if x != nil {
+		return x.Line
+	}
+	return 0
+}
+
+func (x *ParseWarning) GetColumn() string {
+	if x != nil {
+		return x.Column
+	}
+	return ""
+}
+
+func (x *ParseWarning) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ParseResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Records       []*Record              `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
+	Errors        []*ParseWarning        `protobuf:"bytes,2,rep,name=errors,proto3" json:"errors,omitempty"`
+	ExtraHeaders  []string               `protobuf:"bytes,3,rep,name=extra_headers,json=extraHeaders,proto3" json:"extra_headers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ParseResponse) Reset() {
+	*x = ParseResponse{}
+	mi := &file_hanzi_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ParseResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParseResponse) ProtoMessage() {}
+
+func (x *ParseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_hanzi_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParseResponse.ProtoReflect.Descriptor instead.
+func (*ParseResponse) Descriptor() ([]byte, []int) {
+	return file_hanzi_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ParseResponse) GetRecords() []*Record {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+func (x *ParseResponse) GetErrors() []*ParseWarning {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+func (x *ParseResponse) GetExtraHeaders() []string {
+	if x != nil {
+		return x.ExtraHeaders
+	}
+	return nil
+}
+
+var File_hanzi_proto protoreflect.FileDescriptor
+
+const file_hanzi_proto_rawDesc = "" +
+	"\n" +
+	"\vhanzi.proto\x12\bhanzi.v1\"Y\n" +
+	"\aStudent\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x10\n" +
+	"\x03age\x18\x02 \x01(\x05R\x03age\x12\x12\n" +
+	"\x04city\x18\x03 \x01(\tR\x04city\x12\x14\n" +
+	"\x05score\x18\x04 \x01(\x01R\x05score\"\xd8\x02\n" +
+	"\x10StudentGenConfig\x12\x12\n" +
+	"\x04seed\x18\x01 \x01(\x03R\x04seed\x12\x17\n" +
+	"\aage_min\x18\x02 \x01(\x05R\x06ageMin\x12\x17\n" +
+	"\aage_max\x18\x03 \x01(\x05R\x06ageMax\x12\x1b\n" +
+	"\tscore_min\x18\x04 \x01(\x01R\bscoreMin\x12\x1b\n" +
+	"\tscore_max\x18\x05 \x01(\x01R\bscoreMax\x12\x16\n" +
+	"\x06cities\x18\x06 \x03(\tR\x06cities\x12\x1a\n" +
+	"\bsurnames\x18\a \x03(\tR\bsurnames\x12!\n" +
+	"\fgiven_names1\x18\b \x03(\tR\vgivenNames1\x12!\n" +
+	"\fgiven_names2\x18\t \x03(\tR\vgivenNames2\x12+\n" +
+	"\x12two_char_name_prob\x18\n" +
+	" \x01(\x01R\x0ftwoCharNameProb\x12\x1d\n" +
+	"\n" +
+	"score_step\x18\v \x01(\x01R\tscoreStep\"z\n" +
+	"\x0fGenerateRequest\x122\n" +
+	"\x06config\x18\x01 \x01(\v2\x1a.hanzi.v1.StudentGenConfigR\x06config\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\x12\x1d\n" +
+	"\n" +
+	"batch_size\x18\x03 \x01(\x05R\tbatchSize\"A\n" +
+	"\x10GenerateResponse\x12-\n" +
+	"\bstudents\x18\x01 \x03(\v2\x11.hanzi.v1.StudentR\bstudents\"\x97\x01\n" +
+	"\fParseOptions\x12\x1c\n" +
+	"\tdelimiter\x18\x01 \x01(\tR\tdelimiter\x12\x1a\n" +
+	"\bencoding\x18\x02 \x01(\tR\bencoding\x12\x14\n" +
+	"\x05sniff\x18\x03 \x01(\bR\x05sniff\x12\x16\n" +
+	"\x06strict\x18\x04 \x01(\bR\x06strict\x12\x1f\n" +
+	"\vdup_headers\x18\x05 \x01(\tR\n" +
+	"dupHeaders\"V\n" +
+	"\fParseRequest\x120\n" +
+	"\aoptions\x18\x01 \x01(\v2\x16.hanzi.v1.ParseOptionsR\aoptions\x12\x14\n" +
+	"\x05chunk\x18\x02 \x01(\fR\x05chunk\"\xb6\x01\n" +
+	"\x06Record\x12+\n" +
+	"\astudent\x18\x01 \x01(\v2\x11.hanzi.v1.StudentR\astudent\x12\x12\n" +
+	"\x04line\x18\x02 \x01(\x05R\x04line\x121\n" +
+	"\x05extra\x18\x03 \x03(\v2\x1b.hanzi.v1.Record.ExtraEntryR\x05extra\x1a8\n" +
+	"\n" +
+	"ExtraEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"h\n" +
+	"\fParseWarning\x12\x12\n" +
+	"\x04kind\x18\x01 \x01(\tR\x04kind\x12\x12\n" +
+	"\x04line\x18\x02 \x01(\x05R\x04line\x12\x16\n" +
+	"\x06column\x18\x03 \x01(\tR\x06column\x12\x18\n" +
+	"\amessage\x18\x04 \x01(\tR\amessage\"\x90\x01\n" +
+	"\rParseResponse\x12*\n" +
+	"\arecords\x18\x01 \x03(\v2\x10.hanzi.v1.RecordR\arecords\x12.\n" +
+	"\x06errors\x18\x02 \x03(\v2\x16.hanzi.v1.ParseWarningR\x06errors\x12#\n" +
+	"\rextra_headers\x18\x03 \x03(\tR\fextraHeaders2\x91\x01\n" +
+	"\x0eStudentService\x12C\n" +
+	"\bGenerate\x12\x19.hanzi.v1.GenerateRequest\x1a\x1a.hanzi.v1.GenerateResponse0\x01\x12:\n" +
+	"\x05Parse\x12\x16.hanzi.v1.ParseRequest\x1a\x17.hanzi.v1.ParseResponse(\x01B0Z.github.com/xianyudd/hanzi-data-kit/rpc/hanzipbb\x06proto3"
+
+var (
+	file_hanzi_proto_rawDescOnce sync.Once
+	file_hanzi_proto_rawDescData []byte
+)
+
+func file_hanzi_proto_rawDescGZIP() []byte {
+	file_hanzi_proto_rawDescOnce.Do(func() {
+		file_hanzi_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_hanzi_proto_rawDesc), len(file_hanzi_proto_rawDesc)))
+	})
+	return file_hanzi_proto_rawDescData
+}
+
+var file_hanzi_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_hanzi_proto_goTypes = []any{
+	(*Student)(nil),          // 0: hanzi.v1.Student
+	(*StudentGenConfig)(nil), // 1: hanzi.v1.StudentGenConfig
+	(*GenerateRequest)(nil),  // 2: hanzi.v1.GenerateRequest
+	(*GenerateResponse)(nil), // 3: hanzi.v1.GenerateResponse
+	(*ParseOptions)(nil),     // 4: hanzi.v1.ParseOptions
+	(*ParseRequest)(nil),     // 5: hanzi.v1.ParseRequest
+	(*Record)(nil),           // 6: hanzi.v1.Record
+	(*ParseWarning)(nil),     // 7: hanzi.v1.ParseWarning
+	(*ParseResponse)(nil),    // 8: hanzi.v1.ParseResponse
+	nil,                      // 9: hanzi.v1.Record.ExtraEntry
+}
+var file_hanzi_proto_depIdxs = []int32{
+	1, // 0: hanzi.v1.GenerateRequest.config:type_name -> hanzi.v1.StudentGenConfig
+	0, // 1: hanzi.v1.GenerateResponse.students:type_name -> hanzi.v1.Student
+	4, // 2: hanzi.v1.ParseRequest.options:type_name -> hanzi.v1.ParseOptions
+	0, // 3: hanzi.v1.Record.student:type_name -> hanzi.v1.Student
+	9, // 4: hanzi.v1.Record.extra:type_name -> hanzi.v1.Record.ExtraEntry
+	6, // 5: hanzi.v1.ParseResponse.records:type_name -> hanzi.v1.Record
+	7, // 6: hanzi.v1.ParseResponse.errors:type_name -> hanzi.v1.ParseWarning
+	2, // 7: hanzi.v1.StudentService.Generate:input_type -> hanzi.v1.GenerateRequest
+	5, // 8: hanzi.v1.StudentService.Parse:input_type -> hanzi.v1.ParseRequest
+	3, // 9: hanzi.v1.StudentService.Generate:output_type -> hanzi.v1.GenerateResponse
+	8, // 10: hanzi.v1.StudentService.Parse:output_type -> hanzi.v1.ParseResponse
+	9, // [9:11] is the sub-list for method output_type
+	7, // [7:9] is the sub-list for method input_type
+	7, // [7:7] is the sub-list for extension type_name
+	7, // [7:7] is the sub-list for extension extendee
+	0, // [0:7] is the sub-list for field type_name
+}
+
+func init() { file_hanzi_proto_init() }
+func file_hanzi_proto_init() {
+	if File_hanzi_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_hanzi_proto_rawDesc), len(file_hanzi_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_hanzi_proto_goTypes,
+		DependencyIndexes: file_hanzi_proto_depIdxs,
+		MessageInfos:      file_hanzi_proto_msgTypes,
+	}.Build()
+	File_hanzi_proto = out.File
+	file_hanzi_proto_goTypes = nil
+	file_hanzi_proto_depIdxs = nil
+}
@@ -0,0 +1,156 @@
+package rpc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/xianyudd/hanzi-data-kit/generator"
+	"github.com/xianyudd/hanzi-data-kit/parser"
+	"github.com/xianyudd/hanzi-data-kit/rpc/hanzipb"
+)
+
+// DefaultBatchSize 为 Generate 每条响应消息默认包含的学生数。
+const DefaultBatchSize = 500
+
+// Options 控制服务的限额。
+type Options struct {
+	// MaxRows 为 Generate 单次请求允许生成的最大条数；<=0 时为 1,000,000。
+	MaxRows int64
+
+	// MaxUploadBytes 为 Parse 上传内容的最大字节数；<=0 时为 32 MiB。
+	MaxUploadBytes int64
+}
+
+func defaultOptions() Options {
+	return Options{MaxRows: 1_000_000, MaxUploadBytes: 32 << 20}
+}
+
+// Service 实现 hanzipb.StudentServiceServer。
+type Service struct {
+	hanzipb.UnimplementedStudentServiceServer
+	opts Options
+}
+
+// NewService 构造服务；零值字段使用默认限额。
+func NewService(opts Options) *Service {
+	def := defaultOptions()
+	if opts.MaxRows <= 0 {
+		opts.MaxRows = def.MaxRows
+	}
+	if opts.MaxUploadBytes <= 0 {
+		opts.MaxUploadBytes = def.MaxUploadBytes
+	}
+	return &Service{opts: opts}
+}
+
+// Register 将服务注册到 s 上。
+func Register(s grpc.ServiceRegistrar, opts Options) {
+	hanzipb.RegisterStudentServiceServer(s, NewService(opts))
+}
+
+// Generate 按配置生成 Count 条学生数据，每 BatchSize 条发送一条响应；客户端取消时提前结束。
+func (s *Service) Generate(req *hanzipb.GenerateRequest, stream grpc.ServerStreamingServer[hanzipb.GenerateResponse]) error {
+	n := req.GetCount()
+	if n < 0 || n > s.opts.MaxRows {
+		return status.Errorf(codes.InvalidArgument, "count 必须在 [0, %d] 区间内", s.opts.MaxRows)
+	}
+	batch := int64(req.GetBatchSize())
+	if batch <= 0 {
+		batch = DefaultBatchSize
+	}
+
+	g := generator.NewStudentGenerator(GenConfigFromProto(req.GetConfig()))
+	ctx := stream.Context()
+	for sent := int64(0); sent < n; {
+		if err := ctx.Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+		size := min(batch, n-sent)
+		resp := &hanzipb.GenerateResponse{Students: make([]*hanzipb.Student, size)}
+		for i := range resp.Students {
+			resp.Students[i] = StudentToProto(g.Next())
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+		sent += size
+	}
+	return nil
+}
+
+// Parse 读取客户端流中的 CSV 分块并整体解析：第一条消息中的 options 决定解析方式。
+// 宽松模式下坏行记入 errors；表头错误或严格模式下的坏行返回 InvalidArgument，超出大小限制返回 ResourceExhausted。
+func (s *Service) Parse(stream grpc.ClientStreamingServer[hanzipb.ParseRequest, hanzipb.ParseResponse]) error {
+	first, err := stream.Recv()
+	if errors.Is(err, io.EOF) {
+		return status.Error(codes.InvalidArgument, "未收到任何数据")
+	}
+	if err != nil {
+		return err
+	}
+	opts, err := parseOptionsFromProto(first.GetOptions())
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "options: %v", err)
+	}
+
+	r := &chunkReader{stream: stream, buf: first.GetChunk(), limit: s.opts.MaxUploadBytes}
+	r.read = int64(len(r.buf))
+	if r.read > r.limit {
+		return r.tooLarge()
+	}
+	table, err := parser.ParseCSVReaderToStudentTable(r, "upload.csv", opts)
+	if err != nil {
+		if errors.Is(err, errTooLarge) {
+			return r.tooLarge()
+		}
+		// 读取客户端流本身出错（如客户端取消）时保留原状态码。
+		if st, ok := status.FromError(err); ok {
+			return st.Err()
+		}
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	resp := &hanzipb.ParseResponse{ExtraHeaders: table.ExtraHeaders}
+	for _, rec := range table.Records {
+		resp.Records = append(resp.Records, recordToProto(rec))
+	}
+	for _, w := range table.Warnings {
+		resp.Errors = append(resp.Errors, WarningToProto(w))
+	}
+	return stream.SendAndClose(resp)
+}
+
+var errTooLarge = errors.New("上传内容超过大小限制")
+
+// chunkReader 将客户端流中的 chunk 拼接为一个 io.Reader，并限制总字节数。
+type chunkReader struct {
+	stream grpc.ClientStreamingServer[hanzipb.ParseRequest, hanzipb.ParseResponse]
+	buf    []byte
+	read   int64
+	limit  int64
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		req, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		r.buf = req.GetChunk()
+		if r.read += int64(len(r.buf)); r.read > r.limit {
+			return 0, errTooLarge
+		}
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+func (r *chunkReader) tooLarge() error {
+	return status.Error(codes.ResourceExhausted, fmt.Sprintf("上传内容超过 %d 字节的限制", r.limit))
+}
@@ -0,0 +1,143 @@
+package rpc_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/xianyudd/hanzi-data-kit/generator"
+	"github.com/xianyudd/hanzi-data-kit/rpc"
+	"github.com/xianyudd/hanzi-data-kit/rpc/hanzipb"
+)
+
+// newClient 在进程内通过 bufconn 启动服务并返回客户端。
+func newClient(t *testing.T, opts rpc.Options) hanzipb.StudentServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	rpc.Register(srv, opts)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return hanzipb.NewStudentServiceClient(conn)
+}
+
+func TestGenerate(t *testing.T) {
+	client := newClient(t, rpc.Options{MaxRows: 10_000})
+	cfg := generator.StudentGenConfig{Seed: 7, AgeMin: 20, AgeMax: 22}
+
+	stream, err := client.Generate(context.Background(), &hanzipb.GenerateRequest{
+		Config: rpc.GenConfigToProto(cfg), Count: 1234, BatchSize: 500,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		batches []int
+		got     []*hanzipb.Student
+	)
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		batches = append(batches, len(resp.Students))
+		got = append(got, resp.Students...)
+	}
+	if len(batches) != 3 || batches[2] != 234 {
+		t.Errorf("batches = %v, want [500 500 234]", batches)
+	}
+
+	// 与直接使用生成器的结果一致。
+	g := generator.NewStudentGenerator(cfg)
+	for i, p := range got {
+		if want := g.Next(); rpc.StudentFromProto(p) != want {
+			t.Fatalf("第 %d 条 = %+v, want %+v", i, p, want)
+		}
+	}
+
+	stream, err = client.Generate(context.Background(), &hanzipb.GenerateRequest{Count: 10_001})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("超出 MaxRows: err = %v, want InvalidArgument", err)
+	}
+}
+
+func TestParse(t *testing.T) {
+	client := newClient(t, rpc.Options{MaxUploadBytes: 256})
+	const data = "姓名,年龄,城市,得分,学号\n张三,18,北京,95.5,S1\n李四,x,上海,80,S2\n王五,20,广州,70,S3\n"
+
+	tests := []struct {
+		name      string
+		options   *hanzipb.ParseOptions
+		data      string
+		chunkSize int
+		wantCode  codes.Code
+		wantRecs  int
+		wantErrs  int
+	}{
+		{"逐字节分块", nil, data, 1, codes.OK, 2, 1},
+		{"单个分块", nil, data, len(data), codes.OK, 2, 1},
+		{"分号分隔", &hanzipb.ParseOptions{Delimiter: ";"}, strings.ReplaceAll(data, ",", ";"), 7, codes.OK, 2, 1},
+		{"严格模式", &hanzipb.ParseOptions{Strict: true}, data, 16, codes.InvalidArgument, 0, 0},
+		{"缺列", nil, "姓名,年龄\n张三,18\n", 16, codes.InvalidArgument, 0, 0},
+		{"非法选项", &hanzipb.ParseOptions{DupHeaders: "x"}, data, 16, codes.InvalidArgument, 0, 0},
+		{"超过大小限制", nil, data + strings.Repeat("赵六,18,北京,90,S4\n", 20), 64, codes.ResourceExhausted, 0, 0},
+		{"空流", nil, "", 1, codes.InvalidArgument, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := client.Parse(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i := 0; i < len(tt.data); i += tt.chunkSize {
+				req := &hanzipb.ParseRequest{Chunk: []byte(tt.data[i:min(i+tt.chunkSize, len(tt.data))])}
+				if i == 0 {
+					req.Options = tt.options
+				}
+				if err := stream.Send(req); err != nil {
+					break // 服务端已提前返回，错误在 CloseAndRecv 中取得
+				}
+			}
+			resp, err := stream.CloseAndRecv()
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("err = %v, want %v", err, tt.wantCode)
+			}
+			if err != nil {
+				return
+			}
+			if len(resp.Records) != tt.wantRecs || len(resp.Errors) != tt.wantErrs {
+				t.Fatalf("records = %d, errors = %d", len(resp.Records), len(resp.Errors))
+			}
+			r := resp.Records[0]
+			if r.Student.GetName() != "张三" || r.Line != 2 || r.Extra["学号"] != "S1" {
+				t.Errorf("records[0] = %v", r)
+			}
+			if e := resp.Errors[0]; e.Line != 3 || e.Column != "年龄" || e.Kind != "row_skipped" {
+				t.Errorf("errors[0] = %v", e)
+			}
+		})
+	}
+}
@@ -0,0 +1,7 @@
+// Package sample 提供可复现的抽样与划分工具：蓄水池抽样、伯努利抽样、分层抽样，
+// 以及按键哈希的 k 路确定性划分（如 train/val/test）。
+//
+// 所有随机行为都由 Seed 驱动：相同的 Seed 与相同的输入流总是得到相同的结果。
+// 划分（Splitter）只依赖 Seed 与记录的键，与记录在文件中的顺序无关，
+// 因此数据追加或重排后，同一条记录仍会落在同一份中。
+package sample
@@ -0,0 +1,232 @@
+package sample
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"slices"
+	"strconv"
+
+	"github.com/xianyudd/hanzi-data-kit/model"
+)
+
+// Reservoir 用蓄水池算法（Algorithm R）从任意长度的流中等概率抽取至多 N 条记录，
+// 内存占用为 O(N)。
+type Reservoir[T any] struct {
+	n     int
+	seen  int
+	rng   *rand.Rand
+	items []positioned[T]
+}
+
+// positioned 记录样本在流中的位置，用于按原顺序输出。
+type positioned[T any] struct {
+	pos  int
+	item T
+}
+
+// NewReservoir 构造容量为 n 的蓄水池；n<=0 时不保留任何记录。
+func NewReservoir[T any](n int, seed int64) *Reservoir[T] {
+	return &Reservoir[T]{n: max(n, 0), rng: rand.New(rand.NewSource(seed))}
+}
+
+// Add 向蓄水池提交流中的下一条记录。
+func (r *Reservoir[T]) Add(item T) {
+	pos := r.seen
+	r.seen++
+	if len(r.items) < r.n {
+		r.items = append(r.items, positioned[T]{pos, item})
+		return
+	}
+	if j := r.rng.Intn(r.seen); j < r.n {
+		r.items[j] = positioned[T]{pos, item}
+	}
+}
+
+// Seen 返回已提交的记录数。
+func (r *Reservoir[T]) Seen() int { return r.seen }
+
+// Items 返回当前样本，按记录在流中的先后顺序排列。
+func (r *Reservoir[T]) Items() []T {
+	sorted := slices.Clone(r.items)
+	slices.SortFunc(sorted, func(a, b positioned[T]) int { return a.pos - b.pos })
+	out := make([]T, len(sorted))
+	for i, p := range sorted {
+		out[i] = p.item
+	}
+	return out
+}
+
+// Bernoulli 以固定概率独立地保留每条记录；样本量随机，期望为 rate×总数。
+type Bernoulli struct {
+	rate float64
+	rng  *rand.Rand
+}
+
+// NewBernoulli 构造保留概率为 rate 的伯努利抽样器；rate 必须在 [0,1] 区间内。
+func NewBernoulli(rate float64, seed int64) (*Bernoulli, error) {
+	if math.IsNaN(rate) || rate < 0 || rate > 1 {
+		return nil, fmt.Errorf("抽样比例必须在 [0,1] 区间内, 实际为 %v", rate)
+	}
+	return &Bernoulli{rate: rate, rng: rand.New(rand.NewSource(seed))}, nil
+}
+
+// Keep 为流中的下一条记录掷一次硬币，返回是否保留。
+func (b *Bernoulli) Keep() bool {
+	return b.rng.Float64() < b.rate
+}
+
+// Stratified 按分层键把记录分到各层，在每层内各用一个蓄水池抽取至多 PerStratum 条。
+// 每层的随机源由 Seed 与层名共同决定，因此某一层的样本不受其他层记录的影响。
+type Stratified[T any] struct {
+	key        func(T) string
+	perStratum int
+	seed       int64
+	seen       int
+	strata     map[string]*Reservoir[positioned[T]]
+	order      []string
+}
+
+// NewStratified 构造分层抽样器：key 返回记录所属的层，perStratum 为每层的样本量。
+func NewStratified[T any](key func(T) string, perStratum int, seed int64) *Stratified[T] {
+	return &Stratified[T]{
+		key:        key,
+		perStratum: perStratum,
+		seed:       seed,
+		strata:     make(map[string]*Reservoir[positioned[T]]),
+	}
+}
+
+// Add 向对应层提交流中的下一条记录。
+func (s *Stratified[T]) Add(item T) {
+	k := s.key(item)
+	r, ok := s.strata[k]
+	if !ok {
+		r = NewReservoir[positioned[T]](s.perStratum, int64(hashKey(s.seed, k)))
+		s.strata[k] = r
+		s.order = append(s.order, k)
+	}
+	r.Add(positioned[T]{s.seen, item})
+	s.seen++
+}
+
+// Strata 返回各层的名称与该层已提交的记录数，按层首次出现的顺序排列。
+func (s *Stratified[T]) Strata() []Stratum {
+	out := make([]Stratum, len(s.order))
+	for i, k := range s.order {
+		r := s.strata[k]
+		out[i] = Stratum{Name: k, Seen: r.Seen(), Sampled: len(r.items)}
+	}
+	return out
+}
+
+// Stratum 是分层抽样中一层的汇总。
+type Stratum struct {
+	Name    string
+	Seen    int
+	Sampled int
+}
+
+// Items 返回全部层的样本，按记录在流中的先后顺序排列。
+func (s *Stratified[T]) Items() []T {
+	var all []positioned[T]
+	for _, k := range s.order {
+		all = append(all, s.strata[k].Items()...)
+	}
+	slices.SortFunc(all, func(a, b positioned[T]) int { return a.pos - b.pos })
+	out := make([]T, len(all))
+	for i, p := range all {
+		out[i] = p.item
+	}
+	return out
+}
+
+// ByCity 以城市作为分层键。
+func ByCity(s model.Student) string { return s.City }
+
+// ByScoreBand 返回按得分分段的分层键函数：width=10 时 85.5 属于 "[80,90)"。
+// width<=0 时按 10 分一段。
+func ByScoreBand(width float64) func(model.Student) string {
+	if width <= 0 {
+		width = 10
+	}
+	return func(s model.Student) string {
+		lo := math.Floor(s.Score/width) * width
+		return "[" + strconv.FormatFloat(lo, 'f', -1, 64) + "," + strconv.FormatFloat(lo+width, 'f', -1, 64) + ")"
+	}
+}
+
+// Splitter 根据记录的键把记录确定性地分到 k 份之一，各份的期望占比由权重决定。
+// 结果只取决于 Seed 与键，与记录顺序、数据总量无关。
+type Splitter struct {
+	seed int64
+	cum  []float64 // 归一化后的累计权重，最后一项为 1
+}
+
+// NewSplitter 构造划分器；weights 为各份的相对权重（如 8,1,1），至少一项且均须为正数。
+func NewSplitter(weights []float64, seed int64) (*Splitter, error) {
+	if len(weights) == 0 {
+		return nil, fmt.Errorf("划分权重不能为空")
+	}
+	var total float64
+	for i, w := range weights {
+		if !(w > 0) || math.IsInf(w, 0) {
+			return nil, fmt.Errorf("第 %d 个划分权重必须为正数, 实际为 %v", i+1, w)
+		}
+		total += w
+	}
+	cum := make([]float64, len(weights))
+	var acc float64
+	for i, w := range weights {
+		acc += w
+		cum[i] = acc / total
+	}
+	cum[len(cum)-1] = 1
+	return &Splitter{seed: seed, cum: cum}, nil
+}
+
+// EqualSplitter 构造 k 份等权重的划分器。
+func EqualSplitter(k int, seed int64) (*Splitter, error) {
+	if k <= 0 {
+		return nil, fmt.Errorf("划分份数必须为正数, 实际为 %d", k)
+	}
+	weights := make([]float64, k)
+	for i := range weights {
+		weights[i] = 1
+	}
+	return NewSplitter(weights, seed)
+}
+
+// Parts 返回份数。
+func (s *Splitter) Parts() int { return len(s.cum) }
+
+// Part 返回键 key 所属的份（0-based）。
+func (s *Splitter) Part(key string) int {
+	// 取高 53 位映射到 [0,1)。
+	u := float64(hashKey(s.seed, key)>>11) / (1 << 53)
+	for i, c := range s.cum {
+		if u < c {
+			return i
+		}
+	}
+	return len(s.cum) - 1
+}
+
+// hashKey 对 (seed, key) 做 FNV-1a 哈希，再经 SplitMix64 终结器打散高位。
+func hashKey(seed int64, key string) uint64 {
+	f := fnv.New64a()
+	var b [8]byte
+	for i := range b {
+		b[i] = byte(uint64(seed) >> (8 * i))
+	}
+	f.Write(b[:])
+	f.Write([]byte(key))
+	x := f.Sum64()
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
@@ -0,0 +1,148 @@
+package sample_test
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/xianyudd/hanzi-data-kit/model"
+	"github.com/xianyudd/hanzi-data-kit/sample"
+)
+
+func TestReservoir(t *testing.T) {
+	run := func(seed int64) []int {
+		r := sample.NewReservoir[int](10, seed)
+		for i := 0; i < 1000; i++ {
+			r.Add(i)
+		}
+		return r.Items()
+	}
+
+	got := run(42)
+	if len(got) != 10 {
+		t.Fatalf("expected 10 items, got %d", len(got))
+	}
+	if !slices.IsSorted(got) {
+		t.Fatalf("items should keep stream order: %v", got)
+	}
+	if again := run(42); !slices.Equal(got, again) {
+		t.Fatalf("same seed should reproduce: %v vs %v", got, again)
+	}
+	if other := run(7); slices.Equal(got, other) {
+		t.Fatalf("different seeds should differ: %v", got)
+	}
+
+	// 流短于容量时保留全部记录。
+	short := sample.NewReservoir[int](10, 1)
+	for i := 0; i < 3; i++ {
+		short.Add(i)
+	}
+	if got := short.Items(); !slices.Equal(got, []int{0, 1, 2}) {
+		t.Fatalf("short stream: got %v", got)
+	}
+}
+
+func TestReservoir_Uniform(t *testing.T) {
+	// 每个位置被选中的概率应接近 n/N = 0.1。
+	const trials, n, total = 2000, 10, 100
+	counts := make([]int, total)
+	for s := 0; s < trials; s++ {
+		r := sample.NewReservoir[int](n, int64(s))
+		for i := 0; i < total; i++ {
+			r.Add(i)
+		}
+		for _, v := range r.Items() {
+			counts[v]++
+		}
+	}
+	for i, c := range counts {
+		if p := float64(c) / trials; math.Abs(p-0.1) > 0.04 {
+			t.Fatalf("position %d selected with p=%.3f, want ~0.1", i, p)
+		}
+	}
+}
+
+func TestBernoulli(t *testing.T) {
+	if _, err := sample.NewBernoulli(1.5, 1); err == nil {
+		t.Fatalf("expected error for rate > 1")
+	}
+	b, err := sample.NewBernoulli(0.2, 42)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	kept := 0
+	for i := 0; i < 10000; i++ {
+		if b.Keep() {
+			kept++
+		}
+	}
+	if kept < 1800 || kept > 2200 {
+		t.Fatalf("expected ~2000 kept, got %d", kept)
+	}
+}
+
+func TestStratified(t *testing.T) {
+	var students []model.Student
+	for i := 0; i < 50; i++ {
+		students = append(students, model.Student{Name: fmt.Sprint("北", i), City: "北京", Score: 95})
+	}
+	for i := 0; i < 3; i++ {
+		students = append(students, model.Student{Name: fmt.Sprint("上", i), City: "上海", Score: 62.5})
+	}
+
+	s := sample.NewStratified(sample.ByCity, 5, 42)
+	for _, stu := range students {
+		s.Add(stu)
+	}
+	want := []sample.Stratum{{Name: "北京", Seen: 50, Sampled: 5}, {Name: "上海", Seen: 3, Sampled: 3}}
+	if got := s.Strata(); !slices.Equal(got, want) {
+		t.Fatalf("strata mismatch: got %+v, want %+v", got, want)
+	}
+	if got := s.Items(); len(got) != 8 || got[len(got)-1].City != "上海" {
+		t.Fatalf("items mismatch: %+v", got)
+	}
+
+	band := sample.ByScoreBand(10)
+	if got := band(students[0]); got != "[90,100)" {
+		t.Fatalf("score band: got %q", got)
+	}
+	if got := band(students[50]); got != "[60,70)" {
+		t.Fatalf("score band: got %q", got)
+	}
+}
+
+func TestSplitter(t *testing.T) {
+	if _, err := sample.NewSplitter([]float64{1, 0}, 1); err == nil {
+		t.Fatalf("expected error for zero weight")
+	}
+
+	sp, err := sample.NewSplitter([]float64{8, 1, 1}, 42)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	counts := make([]int, sp.Parts())
+	for i := 0; i < 10000; i++ {
+		key := fmt.Sprint("学生", i)
+		p := sp.Part(key)
+		if again := sp.Part(key); again != p {
+			t.Fatalf("split should be deterministic for %q", key)
+		}
+		counts[p]++
+	}
+	if counts[0] < 7700 || counts[0] > 8300 || counts[1] < 850 || counts[2] < 850 {
+		t.Fatalf("unexpected split sizes: %v", counts)
+	}
+
+	other, _ := sample.NewSplitter([]float64{8, 1, 1}, 7)
+	same := 0
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprint("学生", i)
+		if sp.Part(key) == other.Part(key) {
+			same++
+		}
+	}
+	if same == 1000 {
+		t.Fatalf("different seeds should produce different splits")
+	}
+}
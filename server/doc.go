@@ -0,0 +1,11 @@
+// Package server 以 HTTP API 的形式提供数据生成、解析与校验，供不安装 Go 的团队获取测试数据。
+//
+// 路由：
+//
+//	GET  /healthz                                     健康检查
+//	GET  /students?n=&seed=&format=csv|jsonl|xlsx     流式生成学生数据（分块传输）
+//	POST /parse                                       上传 CSV，返回解析结果 JSON 与行级错误
+//	POST /validate                                    上传 CSV，只返回校验结论与行级错误
+//
+// 上传既可以是 multipart/form-data 中名为 file 的字段，也可以直接作为请求体。
+package server
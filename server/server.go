@@ -0,0 +1,481 @@
+package server
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/xianyudd/hanzi-data-kit/generator"
+	"github.com/xianyudd/hanzi-data-kit/model"
+	"github.com/xianyudd/hanzi-data-kit/parser"
+)
+
+// Options 控制服务的限额与日志。
+type Options struct {
+	// MaxRows 为 /students 单次请求允许生成的最大行数；<=0 时为 1,000,000。
+	MaxRows int
+
+	// MaxUploadBytes 为 /parse 与 /validate 请求体的最大字节数；<=0 时为 32 MiB。
+	MaxUploadBytes int64
+
+	// Logger 用于输出访问日志；为 nil 时丢弃所有日志。
+	Logger *slog.Logger
+}
+
+func defaultOptions() Options {
+	return Options{MaxRows: 1_000_000, MaxUploadBytes: 32 << 20}
+}
+
+// flushEvery 为流式生成时每隔多少行主动刷新一次响应。
+const flushEvery = 1000
+
+// server 持有补全后的配置。
+type server struct {
+	opts   Options
+	logger *slog.Logger
+}
+
+// New 返回服务的 http.Handler。
+func New(opts Options) http.Handler {
+	def := defaultOptions()
+	if opts.MaxRows <= 0 {
+		opts.MaxRows = def.MaxRows
+	}
+	if opts.MaxUploadBytes <= 0 {
+		opts.MaxUploadBytes = def.MaxUploadBytes
+	}
+	logger := opts.Logger
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+	s := &server{opts: opts, logger: logger}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", s.handleHealth)
+	mux.HandleFunc("GET /students", s.handleStudents)
+	mux.HandleFunc("POST /parse", s.handleParse)
+	mux.HandleFunc("POST /validate", s.handleValidate)
+	return s.logRequests(mux)
+}
+
+func (s *server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// handleStudents 按查询参数生成数据并以分块传输逐行写出。
+func (s *server) handleStudents(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	n, err := intParam("n", q.Get("n"), 100)
+	if err == nil && (n < 0 || n > s.opts.MaxRows) {
+		err = fmt.Errorf("n 必须在 [0, %d] 区间内", s.opts.MaxRows)
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	cfg, err := genConfig(q)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	headers := model.StudentHeadersCN()
+	switch q.Get("headers") {
+	case "", "cn":
+	case "en":
+		headers = model.StudentHeadersEN()
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Errorf("headers 只支持 cn|en, 实际为 %q", q.Get("headers")))
+		return
+	}
+
+	format := q.Get("format")
+	if format == "" {
+		format = "csv"
+	}
+	var sink rowSink
+	body := &bodyWriter{w: w}
+	switch format {
+	case "csv":
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", `attachment; filename="students.csv"`)
+		sink = newCSVSink(body, headers)
+	case "jsonl":
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		sink = newJSONLSink(body)
+	case "xlsx":
+		w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+		w.Header().Set("Content-Disposition", `attachment; filename="students.xlsx"`)
+		sink, err = newXLSXSink(body, headers)
+	default:
+		writeError(w, http.StatusBadRequest, fmt.Errorf("format 只支持 csv|jsonl|xlsx, 实际为 %q", format))
+		return
+	}
+	if err == nil {
+		err = s.stream(w, r, sink, generator.NewStudentGenerator(cfg), n)
+	}
+	if err != nil && !body.started {
+		w.Header().Del("Content-Disposition")
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if err != nil {
+		// 响应头已经发出，无法再返回错误状态：记录日志后中断连接，使客户端能发现响应不完整
+		// （否则分块传输会正常结束，截断的文件看起来与完整的无异）。
+		s.logger.Warn("生成数据中断", "error", err)
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// bodyWriter 记录是否已开始写出响应体；开始之前出错仍可返回错误状态。
+type bodyWriter struct {
+	w       io.Writer
+	started bool
+}
+
+func (b *bodyWriter) Write(p []byte) (int, error) {
+	b.started = true
+	return b.w.Write(p)
+}
+
+func (s *server) stream(w http.ResponseWriter, r *http.Request, sink rowSink, g *generator.StudentGenerator, n int) error {
+	rc := http.NewResponseController(w)
+	for i := 1; i <= n; i++ {
+		if err := sink.write(g.Next()); err != nil {
+			return err
+		}
+		if i%flushEvery == 0 {
+			if err := r.Context().Err(); err != nil {
+				return err
+			}
+			if err := sink.flush(); err != nil {
+				return err
+			}
+			if err := rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+				return err
+			}
+		}
+	}
+	return sink.close()
+}
+
+// genConfig 从查询参数构造生成配置：seed、age_min、age_max、score_min、score_max。
+func genConfig(q map[string][]string) (generator.StudentGenConfig, error) {
+	get := func(k string) string {
+		if v := q[k]; len(v) > 0 {
+			return v[0]
+		}
+		return ""
+	}
+	var cfg generator.StudentGenConfig
+	seed, err := strconv.ParseInt(valueOr(get("seed"), "42"), 10, 64)
+	if err != nil {
+		return cfg, fmt.Errorf("seed 不是整数: %q", get("seed"))
+	}
+	cfg.Seed = seed
+	for _, p := range []struct {
+		name string
+		dst  *int
+	}{{"age_min", &cfg.AgeMin}, {"age_max", &cfg.AgeMax}} {
+		if *p.dst, err = intParam(p.name, get(p.name), 0); err != nil {
+			return cfg, err
+		}
+	}
+	for _, p := range []struct {
+		name string
+		dst  *float64
+	}{{"score_min", &cfg.ScoreMin}, {"score_max", &cfg.ScoreMax}} {
+		v := get(p.name)
+		if v == "" {
+			continue
+		}
+		if *p.dst, err = strconv.ParseFloat(v, 64); err != nil {
+			return cfg, fmt.Errorf("%s 不是数字: %q", p.name, v)
+		}
+	}
+	return cfg, nil
+}
+
+// parseResponse 为 /parse 的响应体。
+type parseResponse struct {
+	Count        int                   `json:"count"`
+	Students     []model.Student       `json:"students"`
+	Extra        []map[string]string   `json:"extra,omitempty"`
+	ExtraHeaders []string              `json:"extra_headers,omitempty"`
+	Header       parser.HeaderReport   `json:"header"`
+	Errors       []parser.ParseWarning `json:"errors"`
+}
+
+// handleParse 解析上传的 CSV；坏行默认跳过并在 errors 中列出（skip_bad_rows=false 时遇到坏行返回 422）。
+func (s *server) handleParse(w http.ResponseWriter, r *http.Request) {
+	opts, err := parseOptions(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	body, name, err := s.upload(w, r)
+	if err != nil {
+		writeUploadError(w, err)
+		return
+	}
+	table, err := parser.ParseCSVReaderToStudentTable(body, name, opts)
+	if err != nil {
+		writeUploadError(w, err)
+		return
+	}
+
+	resp := parseResponse{
+		Count:        len(table.Records),
+		Students:     table.Students(),
+		ExtraHeaders: table.ExtraHeaders,
+		Header:       table.Header,
+		Errors:       table.Warnings,
+	}
+	if len(table.ExtraHeaders) > 0 {
+		for _, rec := range table.Records {
+			resp.Extra = append(resp.Extra, rec.Extra)
+		}
+	}
+	if resp.Errors == nil {
+		resp.Errors = []parser.ParseWarning{}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// validateResponse 为 /validate 的响应体。
+type validateResponse struct {
+	Valid  bool                  `json:"valid"`
+	Rows   int                   `json:"rows"`
+	Error  string                `json:"error,omitempty"`
+	Header *parser.HeaderReport  `json:"header,omitempty"`
+	Errors []parser.ParseWarning `json:"errors"`
+}
+
+// handleValidate 流式校验上传的 CSV，不保留记录；表头错误（如缺列）记入 error 字段，仍返回 200。
+func (s *server) handleValidate(w http.ResponseWriter, r *http.Request) {
+	opts, err := parseOptions(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	// 校验需要列出所有坏行，因此总是宽松解析。
+	opts.SkipBadRows = true
+	resp := validateResponse{Errors: []parser.ParseWarning{}}
+	opts.OnWarning = func(pw parser.ParseWarning) { resp.Errors = append(resp.Errors, pw) }
+
+	body, name, err := s.upload(w, r)
+	if err != nil {
+		writeUploadError(w, err)
+		return
+	}
+	sc, err := parser.NewStudentScannerFromReader(body, name, opts)
+	if err == nil {
+		header := sc.Header()
+		resp.Header = &header
+		for {
+			if _, err = sc.Next(); err != nil {
+				break
+			}
+			resp.Rows++
+		}
+		if errors.Is(err, io.EOF) {
+			err = nil
+		}
+	}
+	if err != nil {
+		var mbe *http.MaxBytesError
+		if errors.As(err, &mbe) {
+			writeUploadError(w, err)
+			return
+		}
+		resp.Error = err.Error()
+	}
+	resp.Valid = err == nil && len(resp.Errors) == 0 && resp.Header.OK()
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// parseOptions 从查询参数构造解析选项：delimiter、encoding、sniff、skip_bad_rows、dup_headers。
+func parseOptions(r *http.Request) (parser.CSVParseOptions, error) {
+	q := r.URL.Query()
+	opts := parser.CSVParseOptions{TrimSpace: true, AllowBOM: true, SkipBadRows: true, Encoding: q.Get("encoding")}
+	delim, err := parser.ParseDelimiter(q.Get("delimiter"))
+	if err != nil {
+		return opts, fmt.Errorf("delimiter: %w", err)
+	}
+	opts.Dialect.Delimiter = delim
+	if opts.SniffDialect, err = boolParam("sniff", q.Get("sniff"), false); err != nil {
+		return opts, err
+	}
+	if opts.SkipBadRows, err = boolParam("skip_bad_rows", q.Get("skip_bad_rows"), true); err != nil {
+		return opts, err
+	}
+	if v := q.Get("dup_headers"); v != "" {
+		if opts.DuplicateHeaders, err = parser.ParseDuplicateHeaderPolicy(v); err != nil {
+			return opts, err
+		}
+	}
+	return opts, nil
+}
+
+// upload 返回上传内容：multipart/form-data 时为名为 file 的字段，否则为请求体；两者都受 MaxUploadBytes 限制。
+func (s *server) upload(w http.ResponseWriter, r *http.Request) (io.Reader, string, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, s.opts.MaxUploadBytes)
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		return r.Body, "upload.csv", nil
+	}
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return nil, "", err
+	}
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			return nil, "", errMissingFile
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		if part.FormName() == "file" {
+			name := part.FileName()
+			if name == "" {
+				name = "upload.csv"
+			}
+			return part, name, nil
+		}
+	}
+}
+
+var errMissingFile = errors.New("multipart 请求中缺少名为 file 的字段")
+
+// writeUploadError 按错误类型选择状态码：超出大小限制 413，缺少文件 400，其余（CSV 内容问题）422。
+func writeUploadError(w http.ResponseWriter, err error) {
+	var mbe *http.MaxBytesError
+	switch {
+	case errors.As(err, &mbe):
+		writeError(w, http.StatusRequestEntityTooLarge, fmt.Errorf("请求体超过 %d 字节的限制", mbe.Limit))
+	case errors.Is(err, errMissingFile), errors.Is(err, http.ErrNotMultipart):
+		writeError(w, http.StatusBadRequest, err)
+	default:
+		writeError(w, http.StatusUnprocessableEntity, err)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func intParam(name, v string, def int) (int, error) {
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("参数 %s 不是整数: %q", name, v)
+	}
+	return n, nil
+}
+
+func boolParam(name, v string, def bool) (bool, error) {
+	if v == "" {
+		return def, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("参数 %s 不是布尔值: %q", name, v)
+	}
+	return b, nil
+}
+
+func valueOr(v, def string) string {
+	if v == "" {
+		return def
+	}
+	return v
+}
+
+// rowSink 为 /students 的一种输出格式。
+type rowSink interface {
+	write(model.Student) error
+	flush() error
+	close() error
+}
+
+type csvSink struct{ w *csv.Writer }
+
+func newCSVSink(w io.Writer, headers []string) *csvSink {
+	s := &csvSink{w: csv.NewWriter(w)}
+	s.w.Write(headers)
+	return s
+}
+
+func (s *csvSink) write(stu model.Student) error { return s.w.Write(model.StudentToRowCN(stu)) }
+func (s *csvSink) flush() error                  { s.w.Flush(); return s.w.Error() }
+func (s *csvSink) close() error                  { return s.flush() }
+
+type jsonlSink struct{ enc *json.Encoder }
+
+func newJSONLSink(w io.Writer) *jsonlSink {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return &jsonlSink{enc: enc}
+}
+
+func (s *jsonlSink) write(stu model.Student) error { return s.enc.Encode(stu) }
+func (s *jsonlSink) flush() error                  { return nil }
+func (s *jsonlSink) close() error                  { return nil }
+
+type xlsxSink struct{ x *parser.XLSXWriter }
+
+func newXLSXSink(w io.Writer, headers []string) (*xlsxSink, error) {
+	x, err := parser.NewXLSXWriter(w, "students")
+	if err != nil {
+		return nil, err
+	}
+	x.NumericColumns(1, 3)
+	return &xlsxSink{x: x}, x.Write(headers)
+}
+
+func (s *xlsxSink) write(stu model.Student) error { return s.x.Write(model.StudentToRowCN(stu)) }
+func (s *xlsxSink) flush() error                  { return s.x.Flush() }
+func (s *xlsxSink) close() error                  { return s.x.Close() }
+
+// logRequests 为每个请求输出一条访问日志。
+func (s *server) logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		// 用 defer 使以 http.ErrAbortHandler 中断的请求也有访问日志。
+		defer func() {
+			s.logger.Info("请求", "method", r.Method, "path", r.URL.Path, "status", sw.status, "duration", time.Since(start))
+		}()
+		next.ServeHTTP(sw, r)
+	})
+}
+
+// statusWriter 记录响应状态码；Unwrap 使 http.ResponseController 仍能找到底层的 Flusher。
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Unwrap() http.ResponseWriter { return w.ResponseWriter }
@@ -0,0 +1,271 @@
+package server_test
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/xianyudd/hanzi-data-kit/model"
+	"github.com/xianyudd/hanzi-data-kit/server"
+)
+
+func newServer(t *testing.T, opts server.Options) *httptest.Server {
+	t.Helper()
+	ts := httptest.NewServer(server.New(opts))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestHealth(t *testing.T) {
+	ts := newServer(t, server.Options{})
+	resp, err := http.Get(ts.URL + "/healthz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d", resp.StatusCode)
+	}
+}
+
+func TestStudents(t *testing.T) {
+	ts := newServer(t, server.Options{MaxRows: 5000})
+
+	t.Run("csv", func(t *testing.T) {
+		resp, err := http.Get(ts.URL + "/students?n=2500&seed=7")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		rows, err := csv.NewReader(resp.Body).ReadAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(rows) != 2501 || strings.Join(rows[0], ",") != "姓名,年龄,城市,得分" {
+			t.Fatalf("rows = %d, header = %v", len(rows), rows[0])
+		}
+		if resp.TransferEncoding == nil || resp.TransferEncoding[0] != "chunked" {
+			t.Errorf("TransferEncoding = %v, want chunked", resp.TransferEncoding)
+		}
+
+		// 相同 seed 结果可复现。
+		again, err := http.Get(ts.URL + "/students?n=2500&seed=7")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer again.Body.Close()
+		rows2, _ := csv.NewReader(again.Body).ReadAll()
+		if strings.Join(rows[100], ",") != strings.Join(rows2[100], ",") {
+			t.Errorf("同一 seed 结果不同: %v vs %v", rows[100], rows2[100])
+		}
+	})
+
+	t.Run("jsonl", func(t *testing.T) {
+		resp, err := http.Get(ts.URL + "/students?n=3&format=jsonl&age_min=20&age_max=20")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		sc := bufio.NewScanner(resp.Body)
+		n := 0
+		for sc.Scan() {
+			var s model.Student
+			if err := json.Unmarshal(sc.Bytes(), &s); err != nil {
+				t.Fatal(err)
+			}
+			if s.Age != 20 || s.Name == "" {
+				t.Errorf("student = %+v", s)
+			}
+			n++
+		}
+		if n != 3 {
+			t.Errorf("lines = %d, want 3", n)
+		}
+	})
+
+	t.Run("xlsx", func(t *testing.T) {
+		resp, err := http.Get(ts.URL + "/students?n=10&format=xlsx")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		if _, err := zip.NewReader(bytes.NewReader(body), int64(len(body))); err != nil {
+			t.Fatalf("不是合法的 xlsx: %v", err)
+		}
+	})
+
+	for _, q := range []string{"n=5001", "n=-1", "n=x", "format=xml", "seed=abc", "headers=jp"} {
+		resp, err := http.Get(ts.URL + "/students?" + q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", q, resp.StatusCode)
+		}
+	}
+
+	// 错误信息指明是哪个参数。
+	resp, err := http.Get(ts.URL + "/students?age_min=x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if body, _ := io.ReadAll(resp.Body); !strings.Contains(string(body), "参数 age_min 不是整数") {
+		t.Errorf("body = %s", body)
+	}
+}
+
+// failingWriter 在写出 limit 字节后返回错误，模拟响应中途失败。
+type failingWriter struct {
+	*httptest.ResponseRecorder
+	limit int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.Body.Len()+len(p) > w.limit {
+		return 0, errors.New("连接已断开")
+	}
+	return w.ResponseRecorder.Write(p)
+}
+
+// 响应头发出后生成失败时，应以 http.ErrAbortHandler 中断连接，而不是让截断的响应正常结束。
+func TestStudents_AbortsOnMidStreamError(t *testing.T) {
+	h := server.New(server.Options{MaxRows: 5000})
+	w := &failingWriter{ResponseRecorder: httptest.NewRecorder(), limit: 1024}
+	defer func() {
+		if got := recover(); got != http.ErrAbortHandler {
+			t.Fatalf("recover() = %v, want http.ErrAbortHandler", got)
+		}
+	}()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/students?n=5000", nil))
+}
+
+const sampleCSV = "姓名,年龄,城市,得分,学号\n张三,18,北京,95.5,S1\n李四,x,上海,80,S2\n王五,20,广州,70,S3\n"
+
+func TestParse(t *testing.T) {
+	ts := newServer(t, server.Options{MaxUploadBytes: 1024})
+
+	var body struct {
+		Count        int             `json:"count"`
+		Students     []model.Student `json:"students"`
+		ExtraHeaders []string        `json:"extra_headers"`
+		Errors       []struct {
+			Kind   string `json:"kind"`
+			Line   int    `json:"line"`
+			Column string `json:"column"`
+		} `json:"errors"`
+	}
+
+	// multipart 上传
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	fw, _ := mw.CreateFormFile("file", "students.csv")
+	io.WriteString(fw, sampleCSV)
+	mw.Close()
+	resp, err := http.Post(ts.URL+"/parse", mw.FormDataContentType(), &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Count != 2 || body.Students[0].Name != "张三" || body.Students[1].Score != 70 {
+		t.Errorf("students = %+v", body.Students)
+	}
+	if len(body.ExtraHeaders) != 1 || body.ExtraHeaders[0] != "学号" {
+		t.Errorf("extra_headers = %v", body.ExtraHeaders)
+	}
+	if len(body.Errors) != 1 || body.Errors[0].Line != 3 || body.Errors[0].Column != "年龄" {
+		t.Errorf("errors = %+v", body.Errors)
+	}
+
+	tests := []struct {
+		name        string
+		query       string
+		contentType string
+		body        string
+		want        int
+	}{
+		{"原始请求体", "", "text/csv", sampleCSV, http.StatusOK},
+		{"严格模式遇坏行", "?skip_bad_rows=false", "text/csv", sampleCSV, http.StatusUnprocessableEntity},
+		{"缺列", "", "text/csv", "姓名,年龄\n张三,18\n", http.StatusUnprocessableEntity},
+		{"超过大小限制", "", "text/csv", "姓名,年龄,城市,得分\n" + strings.Repeat("张三,18,北京,90\n", 100), http.StatusRequestEntityTooLarge},
+		{"非法参数", "?delimiter=abc", "text/csv", sampleCSV, http.StatusBadRequest},
+		{"缺少 file 字段", "", "multipart/form-data; boundary=x", "--x--\r\n", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Post(ts.URL+"/parse"+tt.query, tt.contentType, strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != tt.want {
+				b, _ := io.ReadAll(resp.Body)
+				t.Errorf("status = %d, want %d, body = %s", resp.StatusCode, tt.want, b)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	ts := newServer(t, server.Options{MaxUploadBytes: 1024})
+
+	tests := []struct {
+		name      string
+		body      string
+		wantValid bool
+		wantRows  int
+		wantErrs  int
+		wantFatal bool
+	}{
+		{"合法", "姓名,年龄,城市,得分\n张三,18,北京,95.5\n", true, 1, 0, false},
+		{"含坏行", sampleCSV, false, 2, 1, false},
+		{"缺列", "姓名,年龄\n张三,18\n", false, 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Post(ts.URL+"/validate", "text/csv", strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			var got struct {
+				Valid  bool              `json:"valid"`
+				Rows   int               `json:"rows"`
+				Error  string            `json:"error"`
+				Errors []json.RawMessage `json:"errors"`
+			}
+			if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+				t.Fatal(err)
+			}
+			if got.Valid != tt.wantValid || got.Rows != tt.wantRows || len(got.Errors) != tt.wantErrs || (got.Error != "") != tt.wantFatal {
+				t.Errorf("got %+v", got)
+			}
+		})
+	}
+
+	resp, err := http.Post(ts.URL+"/validate", "text/csv", strings.NewReader("姓名,年龄,城市,得分\n"+strings.Repeat("张三,18,北京,90\n", 100)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, want 413", resp.StatusCode)
+	}
+}
@@ -0,0 +1,6 @@
+// Package sqlquery 把学生 CSV 载入内嵌的纯 Go SQLite（modernc.org/sqlite，无需 cgo 与数据库服务）内存库，
+// 以便直接用 SQL 做临时分析。
+//
+// 每个文件载入为一张表：标准列为 "姓名" TEXT、"年龄" INTEGER、"城市" TEXT、"得分" REAL，
+// 附加列按原表头追加为 TEXT 列。中文列名可以直接书写，也可以用双引号括起，如 SELECT "城市", avg("得分") FROM students。
+package sqlquery
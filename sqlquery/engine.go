@@ -0,0 +1,184 @@
+package sqlquery
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/xianyudd/hanzi-data-kit/model"
+	"github.com/xianyudd/hanzi-data-kit/parser"
+
+	_ "modernc.org/sqlite" // 注册 "sqlite" 驱动
+)
+
+// Engine 是一个内存中的 SQLite 数据库。
+type Engine struct {
+	db *sql.DB
+}
+
+// NewEngine 创建一个空的内存数据库；用完后需调用 Close。
+func NewEngine() (*Engine, error) {
+	db, err := sql.Open("sqlite", "file::memory:")
+	if err != nil {
+		return nil, fmt.Errorf("打开内存数据库失败: %w", err)
+	}
+	// 每个连接都有独立的内存库，限制为单连接以保证所有语句看到同一份数据。
+	db.SetMaxOpenConns(1)
+	return &Engine{db: db}, nil
+}
+
+// DB 返回底层的 *sql.DB，便于执行任意语句。
+func (e *Engine) DB() *sql.DB { return e.db }
+
+// Close 关闭数据库。
+func (e *Engine) Close() error { return e.db.Close() }
+
+// TableName 由文件路径推导表名：取不含扩展名的文件名，非字母数字字符替换为下划线。
+func TableName(path string) string {
+	base := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	name := strings.Map(func(r rune) rune {
+		if r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, base)
+	if name == "" || unicode.IsDigit([]rune(name)[0]) {
+		name = "t_" + name
+	}
+	return name
+}
+
+// ParseTableSpec 解析命令行的 "表名=路径" 或 "路径"：只在第一个 "=" 处切分，且只有其前面是合法的表名
+// （字母、数字与下划线）时才视为表名，因此路径本身含有 "=" 时（如 data/a=b.csv）仍整体作为路径；
+// 省略表名时由 TableName 推导。
+func ParseTableSpec(spec string) (name, path string) {
+	if name, path, ok := strings.Cut(spec, "="); ok && name != "" && TableName(name) == name {
+		return name, path
+	}
+	return TableName(spec), spec
+}
+
+// LoadCSV 以流式方式读取 path 并载入为表 table（表已存在时返回错误），返回载入的行数。
+// 解析出错（如严格模式下的坏行）时不会留下该表。
+func (e *Engine) LoadCSV(ctx context.Context, table, path string, opts parser.CSVParseOptions) (int, error) {
+	sc, err := parser.NewStudentScanner(path, opts)
+	if err != nil {
+		return 0, err
+	}
+	defer sc.Close()
+
+	return e.load(ctx, table, sc.ExtraHeaders(), sc.Next)
+}
+
+// LoadTable 将已解析的表载入为表 table（表已存在时返回错误），返回载入的行数。
+func (e *Engine) LoadTable(ctx context.Context, table string, t *parser.StudentTable) (int, error) {
+	i := 0
+	return e.load(ctx, table, t.ExtraHeaders, func() (parser.StudentRecord, error) {
+		if i >= len(t.Records) {
+			return parser.StudentRecord{}, io.EOF
+		}
+		i++
+		return t.Records[i-1], nil
+	})
+}
+
+// load 在一个事务中建表并用预编译语句逐行插入；next 以 io.EOF 表示结束。
+// next 返回其他 error 时整个事务回滚（包括建表），表不会以半载入的状态留下，调用方可以修正后重试。
+func (e *Engine) load(ctx context.Context, table string, extra []string, next func() (parser.StudentRecord, error)) (int, error) {
+	cols := append(model.StudentHeadersCN(), extra...)
+	defs := []string{
+		QuoteIdent("姓名") + " TEXT",
+		QuoteIdent("年龄") + " INTEGER",
+		QuoteIdent("城市") + " TEXT",
+		QuoteIdent("得分") + " REAL",
+	}
+	quoted := make([]string, len(cols))
+	for i, c := range cols {
+		quoted[i] = QuoteIdent(c)
+		if i >= len(defs) {
+			defs = append(defs, quoted[i]+" TEXT")
+		}
+	}
+
+	tx, err := e.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("CREATE TABLE %s (%s)", QuoteIdent(table), strings.Join(defs, ", "))); err != nil {
+		return 0, fmt.Errorf("创建表 %s 失败: %w", table, err)
+	}
+	stmt, err := tx.PrepareContext(ctx, fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		QuoteIdent(table), strings.Join(quoted, ", "), strings.TrimSuffix(strings.Repeat("?, ", len(cols)), ", ")))
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	n := 0
+	args := make([]any, len(cols))
+	for {
+		rec, err := next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+		args[0], args[1], args[2], args[3] = rec.Name, rec.Age, rec.City, rec.Score
+		for i, h := range extra {
+			args[4+i] = rec.Extra[h]
+		}
+		if _, err := stmt.ExecContext(ctx, args...); err != nil {
+			return 0, fmt.Errorf("写入表 %s 第 %d 行失败: %w", table, rec.Line, err)
+		}
+		n++
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// Query 执行一条 SQL 语句：先以列名调用一次 header，再对每一行调用 row。
+// row 收到的值为驱动返回的 int64、float64、string、[]byte 或 nil；切片在回调返回后会被复用。
+func (e *Engine) Query(ctx context.Context, query string, header func(cols []string) error, row func(vals []any) error) error {
+	rows, err := e.db.QueryContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("执行SQL失败: %w", err)
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	if err := header(cols); err != nil {
+		return err
+	}
+	vals := make([]any, len(cols))
+	ptrs := make([]any, len(cols))
+	for i := range vals {
+		ptrs[i] = &vals[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(ptrs...); err != nil {
+			return err
+		}
+		if err := row(vals); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// QuoteIdent 用双引号括起 SQL 标识符，并转义其中的双引号。
+func QuoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
@@ -0,0 +1,114 @@
+package sqlquery_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/xianyudd/hanzi-data-kit/parser"
+	"github.com/xianyudd/hanzi-data-kit/sqlquery"
+)
+
+func TestEngine_LoadAndQuery(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "students-2024.csv")
+	content := "学号,姓名,年龄,城市,得分\n" +
+		"S001,张三,22,北京,95.0\n" +
+		"S002,李四,25,上海,88.5\n" +
+		"S003,王五,28,北京,81.0\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write temp file failed: %v", err)
+	}
+
+	e, err := sqlquery.NewEngine()
+	if err != nil {
+		t.Fatalf("new engine: %v", err)
+	}
+	defer e.Close()
+
+	ctx := context.Background()
+	name := sqlquery.TableName(path)
+	if name != "students_2024" {
+		t.Fatalf("table name: got %q", name)
+	}
+	n, err := e.LoadCSV(ctx, name, path, parser.CSVParseOptions{TrimSpace: true})
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if n != 3 {
+		t.Fatalf("expected 3 rows loaded, got %d", n)
+	}
+
+	var (
+		cols []string
+		got  []string
+	)
+	err = e.Query(ctx, `SELECT "城市", count(*) AS 人数, avg(得分) AS 平均分, max(学号) FROM students_2024 GROUP BY 城市 ORDER BY 城市`,
+		func(c []string) error { cols = c; return nil },
+		func(vals []any) error { got = append(got, fmt.Sprintf("%v|%v|%v|%v", vals...)); return nil })
+	if err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if len(cols) != 4 || cols[0] != "城市" || cols[1] != "人数" || cols[2] != "平均分" {
+		t.Fatalf("columns: got %q", cols)
+	}
+	want := []string{"上海|1|88.5|S002", "北京|2|88|S003"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("rows: got %q, want %q", got, want)
+	}
+
+	if _, err := e.LoadCSV(ctx, name, path, parser.CSVParseOptions{TrimSpace: true}); err == nil {
+		t.Fatalf("loading the same table twice should fail")
+	}
+	if err := e.Query(ctx, "SELECT * FROM missing", func([]string) error { return nil }, func([]any) error { return nil }); err == nil {
+		t.Fatalf("expected error for missing table")
+	}
+}
+
+// 严格模式下遇到坏行时整个载入回滚：表不存在，修正文件后可以用同一表名重试。
+func TestEngine_LoadCSVRollsBackOnBadRow(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bad.csv")
+	content := "姓名,年龄,城市,得分\n张三,22,北京,95.0\n李四,x,上海,88.5\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write temp file failed: %v", err)
+	}
+	e, err := sqlquery.NewEngine()
+	if err != nil {
+		t.Fatalf("new engine: %v", err)
+	}
+	defer e.Close()
+
+	ctx := context.Background()
+	if n, err := e.LoadCSV(ctx, "bad", path, parser.CSVParseOptions{TrimSpace: true}); err == nil || n != 0 {
+		t.Fatalf("load = %d, %v; want 0 and an error", n, err)
+	}
+	var tables int
+	err = e.Query(ctx, "SELECT count(*) FROM sqlite_master WHERE name = 'bad'",
+		func([]string) error { return nil },
+		func(vals []any) error { tables = int(vals[0].(int64)); return nil })
+	if err != nil || tables != 0 {
+		t.Fatalf("表 bad 不应存在: count=%d, err=%v", tables, err)
+	}
+	if _, err := e.LoadCSV(ctx, "bad", path, parser.CSVParseOptions{TrimSpace: true, SkipBadRows: true}); err != nil {
+		t.Fatalf("重试载入失败: %v", err)
+	}
+}
+
+func TestParseTableSpec(t *testing.T) {
+	tests := []struct {
+		spec, name, path string
+	}{
+		{"data/students.csv", "students", "data/students.csv"},
+		{"s=data/students.csv", "s", "data/students.csv"},
+		{"s=data/a=b.csv", "s", "data/a=b.csv"},
+		{"data/a=b.csv", "a_b", "data/a=b.csv"},
+		{"=x.csv", "_x", "=x.csv"},
+	}
+	for _, tt := range tests {
+		if name, path := sqlquery.ParseTableSpec(tt.spec); name != tt.name || path != tt.path {
+			t.Errorf("ParseTableSpec(%q) = %q, %q; want %q, %q", tt.spec, name, path, tt.name, tt.path)
+		}
+	}
+}
@@ -0,0 +1,4 @@
+// Package stats 提供针对 model.Student 的流式统计：计数、极值、均值/方差（Welford）、
+// 近似分位数（t-digest）、按城市分组汇总、近似去重计数（HyperLogLog）与直方图。
+// 所有统计量都只需单遍扫描且内存占用与数据量无关，适合处理大型 CSV。
+package stats
@@ -0,0 +1,81 @@
+package stats
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/xianyudd/hanzi-data-kit/hanzi"
+)
+
+// Histogram 是区间 [Lo, Hi] 上的等宽直方图。落在区间外的值计入首/末个桶，保证计数不丢失。
+type Histogram struct {
+	Lo, Hi float64
+	Counts []int64
+}
+
+// NewHistogram 创建 bins 个等宽桶的直方图；bins<=0 或 hi<=lo 时退化为单桶。
+func NewHistogram(lo, hi float64, bins int) *Histogram {
+	if bins <= 0 || hi <= lo {
+		bins = 1
+	}
+	return &Histogram{Lo: lo, Hi: hi, Counts: make([]int64, bins)}
+}
+
+// Add 加入一个观测值。
+func (h *Histogram) Add(x float64) {
+	i := 0
+	if h.Hi > h.Lo {
+		i = int(math.Floor((x - h.Lo) / (h.Hi - h.Lo) * float64(len(h.Counts))))
+	}
+	h.Counts[min(max(i, 0), len(h.Counts)-1)]++
+}
+
+// Bins 返回各桶的区间与计数。
+func (h *Histogram) Bins() []Bin {
+	width := (h.Hi - h.Lo) / float64(len(h.Counts))
+	bins := make([]Bin, len(h.Counts))
+	for i, c := range h.Counts {
+		lo := h.Lo + float64(i)*width
+		bins[i] = Bin{
+			Label: fmt.Sprintf("[%g, %g)", lo, lo+width),
+			Count: c,
+		}
+	}
+	// 末桶为闭区间，与 Add 的归桶规则一致。
+	if n := len(bins); n > 0 {
+		bins[n-1].Label = fmt.Sprintf("[%g, %g]", h.Hi-width, h.Hi)
+	}
+	return bins
+}
+
+// Bin 是直方图的一个桶（或分类计数中的一个类别）。
+type Bin struct {
+	Label string `json:"label"`
+	Count int64  `json:"count"`
+}
+
+// RenderBars 以 ASCII 条形图形式输出 bins；最长的条为 width 个字符。
+func RenderBars(w io.Writer, bins []Bin, width int) error {
+	if width <= 0 {
+		width = 40
+	}
+	var maxCount int64
+	labelWidth := 0
+	for _, b := range bins {
+		maxCount = max(maxCount, b.Count)
+		labelWidth = max(labelWidth, hanzi.DisplayWidth(b.Label))
+	}
+	for _, b := range bins {
+		bar := 0
+		if maxCount > 0 {
+			bar = int(math.Round(float64(b.Count) / float64(maxCount) * float64(width)))
+		}
+		pad := strings.Repeat(" ", labelWidth-hanzi.DisplayWidth(b.Label))
+		if _, err := fmt.Fprintf(w, "%s%s | %s %d\n", b.Label, pad, strings.Repeat("#", bar), b.Count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package stats
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// TableOptions 控制 WriteTable 的输出内容。
+type TableOptions struct {
+	// TopCities 为城市分组表最多输出的行数；<=0 表示全部输出。
+	TopCities int
+
+	// Histograms 为 true 时追加年龄分布与得分直方图（ASCII 条形图）。
+	Histograms bool
+
+	// BarWidth 为条形图最长条的字符数；<=0 时为 40。
+	BarWidth int
+}
+
+// WriteTable 将 r 以适合终端阅读的表格形式写到 w。
+func WriteTable(w io.Writer, r Report, opts TableOptions) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "field\tmin\tmax\tmean\tstddev\tp25\tp50\tp75\tp90\tp99\t\n")
+	for _, f := range []struct {
+		name string
+		r    NumericReport
+	}{{"age", r.Age}, {"score", r.Score}} {
+		q := f.r.Quantiles
+		fmt.Fprintf(tw, "%s\t%.1f\t%.1f\t%.2f\t%.2f\t%.1f\t%.1f\t%.1f\t%.1f\t%.1f\t\n",
+			f.name, f.r.Min, f.r.Max, f.r.Mean, f.r.StdDev, q["p25"], q["p50"], q["p75"], q["p90"], q["p99"])
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "\n记录数: %d  不同姓名(约): %d  不同城市: %d\n", r.Count, r.DistinctNames, r.DistinctCities)
+
+	cities := r.Cities
+	if opts.TopCities > 0 && len(cities) > opts.TopCities {
+		cities = cities[:opts.TopCities]
+	}
+	fmt.Fprintf(w, "\n城市 Top %d:\n", len(cities))
+	// 城市名含中文，tabwriter 无法按显示宽度对齐，因此把它放在最后一列。
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "count\tage_mean\tscore_mean\tscore_min\tscore_max\t\tcity\n")
+	for _, c := range cities {
+		fmt.Fprintf(tw, "%d\t%.2f\t%.2f\t%.1f\t%.1f\t\t%s\n", c.Count, c.AgeMean, c.ScoreMean, c.ScoreMin, c.ScoreMax, c.City)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if !opts.Histograms {
+		return nil
+	}
+	fmt.Fprintln(w, "\n年龄分布:")
+	if err := RenderBars(w, r.AgeCounts, opts.BarWidth); err != nil {
+		return err
+	}
+	fmt.Fprintln(w, "\n得分分布:")
+	return RenderBars(w, r.ScoreHistogram, opts.BarWidth)
+}
@@ -0,0 +1,98 @@
+package stats_test
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/xianyudd/hanzi-data-kit/model"
+	"github.com/xianyudd/hanzi-data-kit/stats"
+)
+
+func TestSummary_Welford(t *testing.T) {
+	var s stats.Summary
+	for _, x := range []float64{2, 4, 4, 4, 5, 5, 7, 9} {
+		s.Add(x)
+	}
+	if s.Count() != 8 || s.Min() != 2 || s.Max() != 9 || s.Mean() != 5 {
+		t.Fatalf("summary mismatch: n=%d min=%v max=%v mean=%v", s.Count(), s.Min(), s.Max(), s.Mean())
+	}
+	// 样本方差 = 32 / 7
+	if got, want := s.Variance(), 32.0/7; math.Abs(got-want) > 1e-12 {
+		t.Fatalf("variance: got %v, want %v", got, want)
+	}
+
+	var empty stats.Summary
+	if !math.IsNaN(empty.Mean()) || empty.Variance() != 0 {
+		t.Fatalf("empty summary should have NaN mean and 0 variance")
+	}
+}
+
+func TestTDigest_QuantilesCloseToExact(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	d := stats.NewTDigest(0)
+	xs := make([]float64, 100_000)
+	for i := range xs {
+		xs[i] = rng.NormFloat64()*10 + 75
+		d.Add(xs[i])
+	}
+	sort.Float64s(xs)
+
+	for _, q := range []float64{0.01, 0.25, 0.5, 0.75, 0.99} {
+		exact := xs[int(q*float64(len(xs)))]
+		if got := d.Quantile(q); math.Abs(got-exact) > 0.2 {
+			t.Fatalf("q=%v: got %v, exact %v", q, got, exact)
+		}
+	}
+	if d.Quantile(0) != xs[0] || d.Quantile(1) != xs[len(xs)-1] {
+		t.Fatalf("q=0/1 should return min/max")
+	}
+}
+
+func TestHyperLogLog_EstimateWithinError(t *testing.T) {
+	for _, n := range []int{10, 1000, 200_000} {
+		h := stats.NewHyperLogLog()
+		for i := 0; i < n; i++ {
+			h.Add("学生" + strconv.Itoa(i))
+			h.Add("学生" + strconv.Itoa(i)) // 重复元素不应影响结果
+		}
+		got := float64(h.Estimate())
+		if rel := math.Abs(got-float64(n)) / float64(n); rel > 0.03 {
+			t.Fatalf("n=%d: estimate %v, relative error %.4f", n, got, rel)
+		}
+	}
+}
+
+func TestStudentStats_Report(t *testing.T) {
+	st := stats.NewStudentStats(stats.StudentStatsConfig{})
+	for _, stu := range []model.Student{
+		{Name: "张三", Age: 22, City: "北京", Score: 95.0},
+		{Name: "李四", Age: 25, City: "上海", Score: 62.5},
+		{Name: "王五", Age: 22, City: "北京", Score: 80.0},
+	} {
+		st.Add(stu)
+	}
+
+	r := st.Report()
+	if r.Count != 3 || r.DistinctNames != 3 || r.DistinctCities != 2 {
+		t.Fatalf("report counts mismatch: %+v", r)
+	}
+	if r.Score.Min != 62.5 || r.Score.Max != 95 {
+		t.Fatalf("score min/max mismatch: %+v", r.Score)
+	}
+	if len(r.AgeCounts) != 2 || r.AgeCounts[0] != (stats.Bin{Label: "22", Count: 2}) {
+		t.Fatalf("age distribution mismatch: %+v", r.AgeCounts)
+	}
+	if c := r.Cities[0]; c.City != "北京" || c.Count != 2 || c.ScoreMean != 87.5 {
+		t.Fatalf("top city mismatch: %+v", c)
+	}
+	var total int64
+	for _, b := range r.ScoreHistogram {
+		total += b.Count
+	}
+	if len(r.ScoreHistogram) != 10 || total != 3 || r.ScoreHistogram[9].Count != 1 {
+		t.Fatalf("score histogram mismatch: %+v", r.ScoreHistogram)
+	}
+}
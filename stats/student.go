@@ -0,0 +1,178 @@
+package stats
+
+import (
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/xianyudd/hanzi-data-kit/model"
+)
+
+// StudentStatsConfig 控制 StudentStats 的统计口径。
+type StudentStatsConfig struct {
+	// ScoreLo/ScoreHi/ScoreBins 定义得分直方图；全为零时使用 [0, 100] 共 10 个桶。
+	ScoreLo   float64
+	ScoreHi   float64
+	ScoreBins int
+
+	// Compression 为 t-digest 压缩参数；0 表示 DefaultCompression。
+	Compression float64
+}
+
+// StudentStats 对 model.Student 流做单遍汇总。使用 NewStudentStats 创建。
+type StudentStats struct {
+	count     int64
+	age       Summary
+	score     Summary
+	ageDigest *TDigest
+	scoreDig  *TDigest
+	ageCounts map[int]int64
+	scoreHist *Histogram
+	names     *HyperLogLog
+	cities    map[string]*CityStats
+}
+
+// CityStats 是单个城市的分组汇总。
+type CityStats struct {
+	Age   Summary
+	Score Summary
+}
+
+// NewStudentStats 创建一个空的汇总器。
+func NewStudentStats(cfg StudentStatsConfig) *StudentStats {
+	if cfg.ScoreLo == 0 && cfg.ScoreHi == 0 {
+		cfg.ScoreLo, cfg.ScoreHi = 0, 100
+	}
+	if cfg.ScoreBins <= 0 {
+		cfg.ScoreBins = 10
+	}
+	return &StudentStats{
+		ageDigest: NewTDigest(cfg.Compression),
+		scoreDig:  NewTDigest(cfg.Compression),
+		ageCounts: make(map[int]int64),
+		scoreHist: NewHistogram(cfg.ScoreLo, cfg.ScoreHi, cfg.ScoreBins),
+		names:     NewHyperLogLog(),
+		cities:    make(map[string]*CityStats),
+	}
+}
+
+// Add 加入一条学生记录。
+func (s *StudentStats) Add(stu model.Student) {
+	s.count++
+	age := float64(stu.Age)
+	s.age.Add(age)
+	s.score.Add(stu.Score)
+	s.ageDigest.Add(age)
+	s.scoreDig.Add(stu.Score)
+	s.ageCounts[stu.Age]++
+	s.scoreHist.Add(stu.Score)
+	s.names.Add(stu.Name)
+
+	c, ok := s.cities[stu.City]
+	if !ok {
+		c = &CityStats{}
+		s.cities[stu.City] = c
+	}
+	c.Age.Add(age)
+	c.Score.Add(stu.Score)
+}
+
+// reportQuantiles 为报告中输出的分位点。
+var reportQuantiles = []struct {
+	name string
+	q    float64
+}{
+	{"p25", 0.25}, {"p50", 0.50}, {"p75", 0.75}, {"p90", 0.90}, {"p99", 0.99},
+}
+
+// Report 是可直接序列化为 JSON 的汇总结果。
+type Report struct {
+	Count          int64         `json:"count"`
+	Age            NumericReport `json:"age"`
+	Score          NumericReport `json:"score"`
+	DistinctNames  uint64        `json:"distinct_names_approx"`
+	DistinctCities int           `json:"distinct_cities"`
+	AgeCounts      []Bin         `json:"age_distribution"`
+	ScoreHistogram []Bin         `json:"score_histogram"`
+	Cities         []CityReport  `json:"cities"`
+}
+
+// NumericReport 为单个数值字段的汇总。没有数据时数值字段为 0。
+type NumericReport struct {
+	Min       float64            `json:"min"`
+	Max       float64            `json:"max"`
+	Mean      float64            `json:"mean"`
+	StdDev    float64            `json:"stddev"`
+	Quantiles map[string]float64 `json:"quantiles,omitempty"`
+}
+
+// CityReport 为单个城市的分组汇总。
+type CityReport struct {
+	City      string  `json:"city"`
+	Count     int64   `json:"count"`
+	AgeMean   float64 `json:"age_mean"`
+	ScoreMean float64 `json:"score_mean"`
+	ScoreMin  float64 `json:"score_min"`
+	ScoreMax  float64 `json:"score_max"`
+}
+
+// Report 生成当前的汇总结果。城市按人数降序排列（人数相同时按城市名升序）。
+func (s *StudentStats) Report() Report {
+	r := Report{
+		Count:          s.count,
+		Age:            numericReport(&s.age, s.ageDigest),
+		Score:          numericReport(&s.score, s.scoreDig),
+		DistinctNames:  s.names.Estimate(),
+		DistinctCities: len(s.cities),
+		ScoreHistogram: s.scoreHist.Bins(),
+	}
+
+	ages := make([]int, 0, len(s.ageCounts))
+	for a := range s.ageCounts {
+		ages = append(ages, a)
+	}
+	sort.Ints(ages)
+	for _, a := range ages {
+		r.AgeCounts = append(r.AgeCounts, Bin{Label: strconv.Itoa(a), Count: s.ageCounts[a]})
+	}
+
+	for name, c := range s.cities {
+		r.Cities = append(r.Cities, CityReport{
+			City:      name,
+			Count:     c.Score.Count(),
+			AgeMean:   c.Age.Mean(),
+			ScoreMean: c.Score.Mean(),
+			ScoreMin:  c.Score.Min(),
+			ScoreMax:  c.Score.Max(),
+		})
+	}
+	sort.Slice(r.Cities, func(i, j int) bool {
+		if r.Cities[i].Count != r.Cities[j].Count {
+			return r.Cities[i].Count > r.Cities[j].Count
+		}
+		return r.Cities[i].City < r.Cities[j].City
+	})
+	return r
+}
+
+func numericReport(s *Summary, d *TDigest) NumericReport {
+	if s.Count() == 0 {
+		return NumericReport{}
+	}
+	r := NumericReport{
+		Min:       s.Min(),
+		Max:       s.Max(),
+		Mean:      s.Mean(),
+		StdDev:    s.StdDev(),
+		Quantiles: make(map[string]float64, len(reportQuantiles)),
+	}
+	for _, q := range reportQuantiles {
+		r.Quantiles[q.name] = roundTo(d.Quantile(q.q), 4)
+	}
+	return r
+}
+
+func roundTo(x float64, digits int) float64 {
+	p := math.Pow(10, float64(digits))
+	return math.Round(x*p) / p
+}
@@ -0,0 +1,67 @@
+package stats
+
+import "math"
+
+// Summary 以 Welford 算法流式维护一列数值的计数、极值、均值与方差。
+// 零值可直接使用。
+type Summary struct {
+	n    int64
+	mean float64
+	m2   float64
+	min  float64
+	max  float64
+}
+
+// Add 加入一个观测值。
+func (s *Summary) Add(x float64) {
+	s.n++
+	if s.n == 1 {
+		s.min, s.max = x, x
+	} else {
+		s.min = math.Min(s.min, x)
+		s.max = math.Max(s.max, x)
+	}
+	delta := x - s.mean
+	s.mean += delta / float64(s.n)
+	s.m2 += delta * (x - s.mean)
+}
+
+// Count 返回观测值个数。
+func (s *Summary) Count() int64 { return s.n }
+
+// Min 返回最小值；没有观测值时返回 NaN。
+func (s *Summary) Min() float64 {
+	if s.n == 0 {
+		return math.NaN()
+	}
+	return s.min
+}
+
+// Max 返回最大值；没有观测值时返回 NaN。
+func (s *Summary) Max() float64 {
+	if s.n == 0 {
+		return math.NaN()
+	}
+	return s.max
+}
+
+// Mean 返回算术平均值；没有观测值时返回 NaN。
+func (s *Summary) Mean() float64 {
+	if s.n == 0 {
+		return math.NaN()
+	}
+	return s.mean
+}
+
+// Variance 返回样本方差（分母为 n-1）；观测值少于 2 个时返回 0。
+func (s *Summary) Variance() float64 {
+	if s.n < 2 {
+		return 0
+	}
+	return s.m2 / float64(s.n-1)
+}
+
+// StdDev 返回样本标准差。
+func (s *Summary) StdDev() float64 {
+	return math.Sqrt(s.Variance())
+}
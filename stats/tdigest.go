@@ -0,0 +1,125 @@
+package stats
+
+import (
+	"math"
+	"sort"
+)
+
+// DefaultCompression 为 t-digest 的默认压缩参数：越大越精确、占用越多（质心数约为其 1~2 倍）。
+const DefaultCompression = 100
+
+// TDigest 是用于近似分位数的合并式 t-digest（Dunning & Ertl）。
+// 它以有限个质心概括数据分布，两端（如 p1/p99）精度高于中部。
+type TDigest struct {
+	compression float64
+	centroids   []centroid
+	buf         []float64
+	count       float64
+	min, max    float64
+}
+
+type centroid struct {
+	mean   float64
+	weight float64
+}
+
+// NewTDigest 创建 t-digest；compression<=0 时使用 DefaultCompression。
+func NewTDigest(compression float64) *TDigest {
+	if compression <= 0 {
+		compression = DefaultCompression
+	}
+	return &TDigest{
+		compression: compression,
+		buf:         make([]float64, 0, int(compression)*5),
+		min:         math.Inf(1),
+		max:         math.Inf(-1),
+	}
+}
+
+// Add 加入一个观测值。
+func (t *TDigest) Add(x float64) {
+	t.buf = append(t.buf, x)
+	t.count++
+	t.min = math.Min(t.min, x)
+	t.max = math.Max(t.max, x)
+	if len(t.buf) == cap(t.buf) {
+		t.flush()
+	}
+}
+
+// Count 返回观测值个数。
+func (t *TDigest) Count() int64 { return int64(t.count) }
+
+// Quantile 返回第 q 分位数（q∈[0,1]）的近似值；没有观测值时返回 NaN。
+func (t *TDigest) Quantile(q float64) float64 {
+	t.flush()
+	if len(t.centroids) == 0 {
+		return math.NaN()
+	}
+	if q <= 0 {
+		return t.min
+	}
+	if q >= 1 {
+		return t.max
+	}
+	if len(t.centroids) == 1 {
+		return t.centroids[0].mean
+	}
+
+	target := q * t.count
+	// 每个质心的“中心位置”为其之前的累计权重 + 自身权重的一半，在相邻中心之间线性插值。
+	first := t.centroids[0]
+	if target < first.weight/2 {
+		return t.min + (first.mean-t.min)*target/(first.weight/2)
+	}
+	cum := 0.0
+	for i := 0; i < len(t.centroids)-1; i++ {
+		a, b := t.centroids[i], t.centroids[i+1]
+		left := cum + a.weight/2
+		right := cum + a.weight + b.weight/2
+		if target < right {
+			return a.mean + (b.mean-a.mean)*(target-left)/(right-left)
+		}
+		cum += a.weight
+	}
+	last := t.centroids[len(t.centroids)-1]
+	tail := t.count - last.weight/2
+	return last.mean + (t.max-last.mean)*(target-tail)/(last.weight/2)
+}
+
+// flush 将缓冲区中的新值与已有质心合并，并按 k1 尺度函数重新压缩。
+func (t *TDigest) flush() {
+	if len(t.buf) == 0 {
+		return
+	}
+	all := make([]centroid, 0, len(t.centroids)+len(t.buf))
+	all = append(all, t.centroids...)
+	for _, x := range t.buf {
+		all = append(all, centroid{mean: x, weight: 1})
+	}
+	t.buf = t.buf[:0]
+	sort.Slice(all, func(i, j int) bool { return all[i].mean < all[j].mean })
+
+	merged := all[:1]
+	cur := &merged[0]
+	qLeft := 0.0
+	kLeft := t.k(qLeft)
+	for _, c := range all[1:] {
+		qRight := qLeft + (cur.weight+c.weight)/t.count
+		if t.k(qRight)-kLeft <= 1 {
+			cur.mean += (c.mean - cur.mean) * c.weight / (cur.weight + c.weight)
+			cur.weight += c.weight
+			continue
+		}
+		qLeft += cur.weight / t.count
+		kLeft = t.k(qLeft)
+		merged = append(merged, c)
+		cur = &merged[len(merged)-1]
+	}
+	t.centroids = append([]centroid(nil), merged...)
+}
+
+// k 为 k1 尺度函数：在 q 接近 0 或 1 时变化更快，使两端的质心更小、精度更高。
+func (t *TDigest) k(q float64) float64 {
+	return t.compression / (2 * math.Pi) * math.Asin(2*math.Min(1, math.Max(0, q))-1)
+}